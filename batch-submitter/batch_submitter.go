@@ -126,6 +126,10 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 
 		var services []*bsscore.Service
 		if cfg.RunTxBatchSubmitter {
+			var txBatchTssClient tss.TssClient
+			if cfg.EnableTxBatchAttestation {
+				txBatchTssClient = tssClient
+			}
 			batchTxDriver, err := sequencer.NewDriver(sequencer.Config{
 				Name:                "Sequencer",
 				L1Client:            l1Client,
@@ -143,6 +147,7 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 				BatchType:           sequencer.BatchTypeFromString(cfg.SequencerBatchType),
 				MaxRollupTxn:        cfg.MaxRollupTxn,
 				MinRollupTxn:        cfg.MinRollupTxn,
+				TssClient:           txBatchTssClient,
 			})
 			if err != nil {
 				return err
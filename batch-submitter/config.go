@@ -197,6 +197,10 @@ type Config struct {
 
 	EnableSccRollback bool
 
+	// EnableTxBatchAttestation has the tss managers attest every tx batch
+	// before it is submitted.
+	EnableTxBatchAttestation bool
+
 	// use cloud-hsm to sign for proposer
 	EnableProposerHsm bool
 
@@ -276,6 +280,7 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 		MetricsPort:                 ctx.GlobalUint64(flags.MetricsPortFlag.Name),
 		DisableHTTP2:                ctx.GlobalBool(flags.HTTP2DisableFlag.Name),
 		EnableSccRollback:           ctx.GlobalBool(flags.SccRollbackFlag.Name),
+		EnableTxBatchAttestation:    ctx.GlobalBool(flags.TxBatchAttestationFlag.Name),
 		EnableSequencerHsm:          ctx.GlobalBool(flags.EnableSequencerHsmFlag.Name),
 		SequencerHsmAddress:         ctx.GlobalString(flags.SequencerHsmAddressFlag.Name),
 		SequencerHsmAPIName:         ctx.GlobalString(flags.SequencerHsmAPIName.Name),
@@ -0,0 +1,56 @@
+package sequencer
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+
+	tssClient "github.com/mantlenetworkio/mantle/batch-submitter/tss-client"
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
+	tss_types "github.com/mantlenetworkio/mantle/tss/common"
+)
+
+// TxBatchAttester asks the tss managers to attest tx batches. The attestation
+// of the last batch is kept, crafting the same batch again does not run
+// another signing round.
+type TxBatchAttester struct {
+	client tssClient.TssClient
+
+	mu        sync.Mutex
+	batchHash []byte
+	signature []byte
+}
+
+func NewTxBatchAttester(client tssClient.TssClient) *TxBatchAttester {
+	return &TxBatchAttester{client: client}
+}
+
+// Attest returns the attestation of the transactions of the l2 blocks, start
+// is the number of the first block. The tss nodes check every tx root against
+// their own l2geth before signing.
+func (a *TxBatchAttester) Attest(start *big.Int, blocks []*l2types.Block) ([]byte, error) {
+	txRoots := make([][32]byte, 0, len(blocks))
+	for _, block := range blocks {
+		txRoots = append(txRoots, block.TxHash())
+	}
+	batchHash, err := tss_types.TxBatchHash(txRoots, start)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.signature != nil && bytes.Equal(a.batchHash, batchHash) {
+		return a.signature, nil
+	}
+	signature, err := a.client.GetSignTxBatch(tss_types.SignTxBatchRequest{
+		StartBlock: start,
+		TxRoots:    txRoots,
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.batchHash = batchHash
+	a.signature = signature
+	return signature, nil
+}
@@ -0,0 +1,61 @@
+package sequencer_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mantlenetworkio/mantle/batch-submitter/drivers/sequencer"
+	l2common "github.com/mantlenetworkio/mantle/l2geth/common"
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
+	tss_types "github.com/mantlenetworkio/mantle/tss/common"
+	"github.com/stretchr/testify/require"
+)
+
+type testTssClient struct {
+	request  tss_types.SignTxBatchRequest
+	requests int
+}
+
+func (c *testTssClient) GetSignStateBatch(tss_types.SignStateRequest) ([]byte, error) {
+	panic("unexpected state batch request")
+}
+
+func (c *testTssClient) GetSignTxBatch(request tss_types.SignTxBatchRequest) ([]byte, error) {
+	c.request = request
+	c.requests++
+	return []byte("signature"), nil
+}
+
+func TestTxBatchAttestation(t *testing.T) {
+	var blocks []*l2types.Block
+	for i := uint64(0); i < 3; i++ {
+		tx := l2types.NewTransaction(
+			i, l2common.Address{}, new(big.Int), 21000, new(big.Int), []byte{},
+		)
+		blocks = append(blocks, l2types.NewBlock(&l2types.Header{}, []*l2types.Transaction{tx}, nil, nil))
+	}
+	client := &testTssClient{}
+	attester := sequencer.NewTxBatchAttester(client)
+
+	signature, err := attester.Attest(big.NewInt(42), blocks)
+	require.NoError(t, err)
+	require.Equal(t, []byte("signature"), signature)
+	require.Equal(t, big.NewInt(42), client.request.StartBlock)
+	require.Len(t, client.request.TxRoots, len(blocks))
+	for i, block := range blocks {
+		require.Equal(t, [32]byte(block.TxHash()), client.request.TxRoots[i])
+	}
+	require.NotEqual(t, client.request.TxRoots[0], client.request.TxRoots[1])
+
+	// the same range is attested once
+	signature, err = attester.Attest(big.NewInt(42), blocks)
+	require.NoError(t, err)
+	require.Equal(t, []byte("signature"), signature)
+	require.Equal(t, 1, client.requests)
+
+	// a trimmed range is a different batch
+	_, err = attester.Attest(big.NewInt(42), blocks[:2])
+	require.NoError(t, err)
+	require.Equal(t, 2, client.requests)
+	require.Len(t, client.request.TxRoots, 2)
+}
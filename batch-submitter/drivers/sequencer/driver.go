@@ -18,10 +18,12 @@ import (
 
 	"github.com/mantlenetworkio/mantle/batch-submitter/bindings/ctc"
 	"github.com/mantlenetworkio/mantle/batch-submitter/bindings/da"
+	tssClient "github.com/mantlenetworkio/mantle/batch-submitter/tss-client"
 	bsscore "github.com/mantlenetworkio/mantle/bss-core"
 	"github.com/mantlenetworkio/mantle/bss-core/drivers"
 	"github.com/mantlenetworkio/mantle/bss-core/metrics"
 	"github.com/mantlenetworkio/mantle/bss-core/txmgr"
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
 	l2ethclient "github.com/mantlenetworkio/mantle/l2geth/ethclient"
 
 	kms "cloud.google.com/go/kms/apiv1"
@@ -51,6 +53,9 @@ type Config struct {
 	BatchType           BatchType
 	MaxRollupTxn        uint64
 	MinRollupTxn        uint64
+	// TssClient attests every tx batch before it is submitted, nil
+	// submits tx batches with the sequencer key only
+	TssClient tssClient.TssClient
 }
 
 type Driver struct {
@@ -62,6 +67,7 @@ type Driver struct {
 	walletAddr       common.Address
 	ctcABI           *abi.ABI
 	DaABI            *abi.ABI
+	attester         *TxBatchAttester
 	metrics          *Metrics
 }
 
@@ -128,6 +134,11 @@ func NewDriver(cfg Config) (*Driver, error) {
 		log.Info("not use sequencer hsm", "walletaddr", walletAddr)
 	}
 
+	var attester *TxBatchAttester
+	if cfg.TssClient != nil {
+		attester = NewTxBatchAttester(cfg.TssClient)
+	}
+
 	return &Driver{
 		cfg:              cfg,
 		ctcContract:      ctcContract,
@@ -137,6 +148,7 @@ func NewDriver(cfg Config) (*Driver, error) {
 		walletAddr:       walletAddr,
 		ctcABI:           ctcABI,
 		DaABI:            daABI,
+		attester:         attester,
 		metrics:          NewMetrics(cfg.Name),
 	}, nil
 }
@@ -232,12 +244,14 @@ func (d *Driver) CraftBatchTx(
 		"nonce", nonce, "type", d.cfg.BatchType.String())
 
 	var batchElements []BatchElement
+	var blocks []*l2types.Block
 
 	for i := new(big.Int).Set(start); i.Cmp(end) < 0; i.Add(i, bigOne) {
 		block, err := d.cfg.L2Client.BlockByNumber(ctx, i)
 		if err != nil {
 			return nil, err
 		}
+		blocks = append(blocks, block)
 
 		// For each sequencer transaction, update our running total with the
 		// size of the transaction.
@@ -254,6 +268,17 @@ func (d *Driver) CraftBatchTx(
 			return nil, err
 		}
 
+		// Only the blocks the batch appends are attested.
+		if d.attester != nil {
+			attested := blocks[:batchParams.TotalElementsToAppend]
+			attestedEnd := new(big.Int).Add(start, big.NewInt(int64(len(attested))))
+			if _, err := d.attester.Attest(start, attested); err != nil {
+				log.Error(name+" get tss tx batch attestation fail", "start", start, "end", attestedEnd, "err", err)
+				return nil, err
+			}
+			log.Info(name+" tx batch attested by tss", "start", start, "end", attestedEnd)
+		}
+
 		// Encode the batch arguments using the configured encoding type.
 		batchArguments, err := batchParams.Serialize(d.cfg.BatchType, start, big.NewInt(int64(d.cfg.DaUpgradeBlock)))
 		if err != nil {
@@ -42,7 +42,7 @@ var (
 	}
 	TssClientUrl = cli.StringFlag{
 		Name:     "tss-client-url",
-		Usage:    "HTTP provider URL for tss",
+		Usage:    "HTTP provider URL for tss, comma separated URLs of HA tss managers are tried in turn",
 		Required: true,
 		EnvVar:   "TSS_CLIENT_RPC",
 	}
@@ -263,6 +263,11 @@ var (
 		Usage:  "Whether or not to enable scc rollback.",
 		EnvVar: prefixEnvVar("SCC_ROLLBACK"),
 	}
+	TxBatchAttestationFlag = cli.BoolFlag{
+		Name:   "enable-tx-batch-attestation",
+		Usage:  "Whether or not to have the tss managers attest every tx batch before it is submitted",
+		EnvVar: prefixEnvVar("ENABLE_TX_BATCH_ATTESTATION"),
+	}
 	EnableSequencerHsmFlag = cli.BoolFlag{
 		Name:   "enable-sequencer-hsm",
 		Usage:  "Whether or not to use cloudhsm for sequencer",
@@ -391,6 +396,7 @@ var optionalFlags = []cli.Flag{
 	MetricsHostnameFlag,
 	MetricsPortFlag,
 	HTTP2DisableFlag,
+	TxBatchAttestationFlag,
 	EnableProposerHsmFlag,
 	ProposerHsmAddressFlag,
 	ProposerHsmAPIName,
@@ -2,6 +2,9 @@ package tss_client
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -19,10 +22,18 @@ var errTssHTTPError = errors.New("tss http error")
 
 type TssClient interface {
 	GetSignStateBatch(BatchData common.SignStateRequest) ([]byte, error)
+	GetSignTxBatch(BatchData common.SignTxBatchRequest) ([]byte, error)
 }
 
+// Client talks to one or more tss managers. In HA mode only the leader
+// manager signs, the client sticks to the manager that answered last and
+// fails over to the next url when it is unreachable or answers with a server
+// error, a standby manager answers 503.
 type Client struct {
-	client *resty.Client
+	clients []*resty.Client
+	urls    []string
+	current int
+	lock    sync.Mutex
 }
 
 type TssResponse struct {
@@ -30,11 +41,12 @@ type TssResponse struct {
 	RollBack  bool   `json:"roll_back"`
 }
 
-func NewClient(url string, jwtSecretStr string) (*Client, error) {
-	client := resty.New()
-	client.SetHostURL(url)
+// NewClient creates a client for the comma separated manager urls
+func NewClient(urls string, jwtSecretStr string) (*Client, error) {
+	var jwtSecret []byte
 	if len(jwtSecretStr) != 0 {
-		jwtSecret, err := hexutil.Decode(jwtSecretStr)
+		var err error
+		jwtSecret, err = hexutil.Decode(jwtSecretStr)
 		if err != nil {
 			return nil, fmt.Errorf("invalid jwt secret %s", err.Error())
 		}
@@ -42,6 +54,26 @@ func NewClient(url string, jwtSecretStr string) (*Client, error) {
 			return nil, fmt.Errorf("invalid jwt secret length, expected length %d, actual length %d",
 				JwtSecretLength, len(jwtSecret))
 		}
+	}
+	c := &Client{}
+	for _, url := range strings.Split(urls, ",") {
+		url = strings.TrimSpace(url)
+		if len(url) == 0 {
+			continue
+		}
+		c.urls = append(c.urls, url)
+		c.clients = append(c.clients, newRestyClient(url, jwtSecret))
+	}
+	if len(c.clients) == 0 {
+		return nil, errors.New("no tss manager url")
+	}
+	return c, nil
+}
+
+func newRestyClient(url string, jwtSecret []byte) *resty.Client {
+	client := resty.New()
+	client.SetHostURL(url)
+	if len(jwtSecret) != 0 {
 		client.SetAuthScheme("Bearer")
 		client.OnBeforeRequest(func(c *resty.Client, r *resty.Request) error {
 			token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -64,23 +96,58 @@ func NewClient(url string, jwtSecretStr string) (*Client, error) {
 		}
 		return nil
 	})
-	return &Client{
-		client: client,
-	}, nil
+	return client
 }
 
 func (c *Client) GetSignStateBatch(BatchData common.SignStateRequest) ([]byte, error) {
-	var signature []byte
-	response, err := c.client.R().
-		SetBody(map[string]interface{}{"start_block": BatchData.StartBlock, "offset_starts_at_index": BatchData.OffsetStartsAtIndex, "state_roots": BatchData.StateRoots}).
-		SetResult(signature).
-		Post("/api/v1/sign/state")
-	if err != nil {
-		return nil, fmt.Errorf("cannot get signature: %w", err)
+	return c.post("/api/v1/sign/state", map[string]interface{}{"start_block": BatchData.StartBlock, "offset_starts_at_index": BatchData.OffsetStartsAtIndex, "state_roots": BatchData.StateRoots})
+}
+
+// GetSignTxBatch returns the tss attestation of the transactions of a batch
+func (c *Client) GetSignTxBatch(BatchData common.SignTxBatchRequest) ([]byte, error) {
+	return c.post("/api/v1/sign/txbatch", map[string]interface{}{"start_block": BatchData.StartBlock, "tx_roots": BatchData.TxRoots})
+}
+
+// post asks every manager in turn, starting from the one that answered
+// last, until one of them signs. A request the manager rejected is not sent
+// to the others.
+func (c *Client) post(path string, body interface{}) ([]byte, error) {
+	c.lock.Lock()
+	start := c.current
+	c.lock.Unlock()
+	var lastErr error
+	for i := 0; i < len(c.clients); i++ {
+		idx := (start + i) % len(c.clients)
+		signature, failover, err := c.postTo(c.clients[idx], path, body)
+		if err == nil {
+			c.lock.Lock()
+			c.current = idx
+			c.lock.Unlock()
+			return signature, nil
+		}
+		lastErr = fmt.Errorf("tss manager %s: %w", c.urls[idx], err)
+		if !failover {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// postTo sends the request to a single manager, failover reports whether the
+// error is a transport or server error another manager may not have
+func (c *Client) postTo(client *resty.Client, path string, body interface{}) ([]byte, bool, error) {
+	response, err := client.R().
+		SetBody(body).
+		Post(path)
+	if response == nil || response.RawResponse == nil {
+		return nil, true, fmt.Errorf("cannot get signature: %w", err)
 	}
-	if response.StatusCode() == 200 {
-		return response.Body(), nil
-	} else {
-		return nil, errors.New("fetch tss manager signature faill")
+	switch status := response.StatusCode(); {
+	case status == http.StatusOK:
+		return response.Body(), false, nil
+	case status >= http.StatusInternalServerError:
+		return nil, true, fmt.Errorf("%d %s: %w", status, response.String(), errTssHTTPError)
+	default:
+		return nil, false, fmt.Errorf("%d %s: %w", status, response.String(), errTssHTTPError)
 	}
 }
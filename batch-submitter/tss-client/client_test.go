@@ -0,0 +1,81 @@
+package tss_client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mantlenetworkio/mantle/tss/common"
+)
+
+// testManager answers every request with status and body and counts them
+func testManager(t *testing.T, status int, body string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestClientFailover(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		failover bool
+	}{
+		{name: "standby manager", status: http.StatusServiceUnavailable, failover: true},
+		{name: "server error", status: http.StatusInternalServerError, failover: true},
+		{name: "bad request", status: http.StatusBadRequest},
+		{name: "unauthorized", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, firstCalls := testManager(t, tt.status, "error")
+			second, secondCalls := testManager(t, http.StatusOK, "signature")
+			client, err := NewClient(first.URL+","+second.URL, "")
+			require.NoError(t, err)
+
+			signature, err := client.GetSignStateBatch(common.SignStateRequest{})
+			require.EqualValues(t, 1, atomic.LoadInt32(firstCalls))
+			if !tt.failover {
+				require.ErrorIs(t, err, errTssHTTPError)
+				require.ErrorContains(t, err, first.URL)
+				require.EqualValues(t, 0, atomic.LoadInt32(secondCalls))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []byte("signature"), signature)
+
+			// the client sticks to the manager that signed
+			_, err = client.GetSignStateBatch(common.SignStateRequest{})
+			require.NoError(t, err)
+			require.EqualValues(t, 1, atomic.LoadInt32(firstCalls))
+			require.EqualValues(t, 2, atomic.LoadInt32(secondCalls))
+		})
+	}
+}
+
+func TestClientFailoverUnreachable(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	up, calls := testManager(t, http.StatusOK, "signature")
+	client, err := NewClient(down.URL+", "+up.URL, "")
+	require.NoError(t, err)
+
+	signature, err := client.GetSignTxBatch(common.SignTxBatchRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []byte("signature"), signature)
+	require.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	// every manager failed
+	client, err = NewClient(down.URL, "")
+	require.NoError(t, err)
+	_, err = client.GetSignTxBatch(common.SignTxBatchRequest{})
+	require.ErrorContains(t, err, down.URL)
+}
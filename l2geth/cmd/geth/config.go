@@ -18,6 +18,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -135,6 +136,44 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
 	return stack, cfg
 }
 
+// makeMantleUpgrades applies the upgrade config file and then the single
+// upgrade height flags on top of the overrides of the config file
+func makeMantleUpgrades(ctx *cli.Context, upgrades *params.MantleUpgradesConfig) *params.MantleUpgradesConfig {
+	if file := ctx.GlobalString(utils.UpgradeConfigFlag.Name); file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			utils.Fatalf("Failed to read upgrade config file: %v", err)
+		}
+		defer f.Close()
+
+		overrides := new(params.MantleUpgradesConfig)
+		if err := json.NewDecoder(f).Decode(overrides); err != nil {
+			utils.Fatalf("Invalid upgrade config file: %v", err)
+		}
+		upgrades = upgrades.Merge(overrides)
+	}
+	overrides := new(params.MantleUpgradesConfig)
+	if ctx.GlobalIsSet(utils.OverrideTssRewardFlag.Name) {
+		overrides.TssRewardL2Block = new(big.Int).SetUint64(ctx.GlobalUint64(utils.OverrideTssRewardFlag.Name))
+	}
+	if ctx.GlobalIsSet(utils.OverrideMantleTokenFlag.Name) {
+		overrides.MantleTokenL2Block = new(big.Int).SetUint64(ctx.GlobalUint64(utils.OverrideMantleTokenFlag.Name))
+	}
+	if ctx.GlobalIsSet(utils.OverrideUpdateGasLimitFlag.Name) {
+		overrides.UpdateGasLimitL2Block = new(big.Int).SetUint64(ctx.GlobalUint64(utils.OverrideUpdateGasLimitFlag.Name))
+	}
+	if ctx.GlobalIsSet(utils.OverrideEigenDaFlag.Name) {
+		overrides.EigenDaL2Block = new(big.Int).SetUint64(ctx.GlobalUint64(utils.OverrideEigenDaFlag.Name))
+	}
+	if ctx.GlobalIsSet(utils.OverrideMockUpgradeL1Flag.Name) {
+		overrides.MockUpgradeL1Block = new(big.Int).SetUint64(ctx.GlobalUint64(utils.OverrideMockUpgradeL1Flag.Name))
+	}
+	if *overrides != (params.MantleUpgradesConfig{}) {
+		upgrades = upgrades.Merge(overrides)
+	}
+	return upgrades
+}
+
 // enableWhisper returns true in case one of the whisper flags is set.
 func enableWhisper(ctx *cli.Context) bool {
 	for _, flag := range whisperFlags {
@@ -153,6 +192,7 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	if ctx.GlobalIsSet(utils.OverrideMuirGlacierFlag.Name) {
 		cfg.Eth.OverrideMuirGlacier = new(big.Int).SetUint64(ctx.GlobalUint64(utils.OverrideMuirGlacierFlag.Name))
 	}
+	cfg.Eth.OverrideMantleUpgrades = makeMantleUpgrades(ctx, cfg.Eth.OverrideMantleUpgrades)
 	utils.RegisterEthService(stack, &cfg.Eth) // TODO-FIXME
 
 	// Whisper must be explicitly enabled by specifying at least 1 whisper flag or in dev mode
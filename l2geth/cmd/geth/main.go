@@ -73,6 +73,12 @@ var (
 		utils.SmartCardDaemonPathFlag,
 		utils.OverrideIstanbulFlag,
 		utils.OverrideMuirGlacierFlag,
+		utils.UpgradeConfigFlag,
+		utils.OverrideTssRewardFlag,
+		utils.OverrideMantleTokenFlag,
+		utils.OverrideUpdateGasLimitFlag,
+		utils.OverrideEigenDaFlag,
+		utils.OverrideMockUpgradeL1Flag,
 		utils.EthashCacheDirFlag,
 		utils.EthashCachesInMemoryFlag,
 		utils.EthashCachesOnDiskFlag,
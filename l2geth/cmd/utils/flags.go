@@ -252,6 +252,30 @@ var (
 		Name:  "override.muirglacier",
 		Usage: "Manually specify Muir Glacier fork-block, overriding the bundled setting",
 	}
+	UpgradeConfigFlag = cli.StringFlag{
+		Name:  "upgrade.config",
+		Usage: "JSON file with the mantle upgrade heights, overriding the bundled setting",
+	}
+	OverrideTssRewardFlag = cli.Uint64Flag{
+		Name:  "override.tssreward",
+		Usage: "Manually specify the TssReward upgrade layer2 block, overriding the bundled setting (0 = disabled)",
+	}
+	OverrideMantleTokenFlag = cli.Uint64Flag{
+		Name:  "override.mantletoken",
+		Usage: "Manually specify the MantleToken upgrade layer2 block, overriding the bundled setting (0 = disabled)",
+	}
+	OverrideUpdateGasLimitFlag = cli.Uint64Flag{
+		Name:  "override.updategaslimit",
+		Usage: "Manually specify the UpdateGasLimit upgrade layer2 block, overriding the bundled setting (0 = disabled)",
+	}
+	OverrideEigenDaFlag = cli.Uint64Flag{
+		Name:  "override.eigenda",
+		Usage: "Manually specify the EigenDa upgrade layer2 block, overriding the bundled setting (0 = disabled)",
+	}
+	OverrideMockUpgradeL1Flag = cli.Uint64Flag{
+		Name:  "override.mockupgradel1",
+		Usage: "Manually specify the mock upgrade layer1 block, overriding the bundled setting (0 = disabled)",
+	}
 	// Light server and client settings
 	LightLegacyServFlag = cli.IntFlag{ // Deprecated in favor of light.serve, remove in 2021
 		Name:  "lightserv",
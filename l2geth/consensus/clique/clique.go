@@ -585,7 +585,7 @@ func (c *Clique) FinalizeAndAssemble(chain consensus.ChainReader, header *types.
 	//if UpdateGasLimitBlock not set , will not changed, for mainnet.
 	//if UpdateGasLimitBlock = 0, from the genesis block
 	//if UpdateGasLimitBlock = x, from the x
-	mantleUpgradeConfig := upgrade.NewMantleUpgradeConfig(chain.Config().ChainID)
+	mantleUpgradeConfig := upgrade.NewUpgradeConfig(chain.Config())
 	if !mantleUpgradeConfig.IsUpdateGasLimitBlock(header.Number) && chain.Config().ChainID == params.MantleTestnetChainID {
 		//for testnet, when the UpdateGasLimitBlock  is actived, we must update the gaslimit for all of block
 		//which is after the "updategaslimit" block
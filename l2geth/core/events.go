@@ -27,6 +27,9 @@ type NewTxsEvent struct {
 	ErrCh chan error
 }
 
+// RollbackEvent is posted when the sequencer rewinds the chain.
+type RollbackEvent struct{ Journal *types.RollbackJournal }
+
 // NewMinedBlockEvent is posted when a block has been imported.
 type NewMinedBlockEvent struct{ Block *types.Block }
 
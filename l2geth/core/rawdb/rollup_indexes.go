@@ -3,8 +3,10 @@ package rawdb
 import (
 	"math/big"
 
+	"github.com/mantlenetworkio/mantle/l2geth/core/types"
 	"github.com/mantlenetworkio/mantle/l2geth/ethdb"
 	"github.com/mantlenetworkio/mantle/l2geth/log"
+	"github.com/mantlenetworkio/mantle/l2geth/rlp"
 )
 
 // ReadHeadIndex will read the known tip of the CTC
@@ -49,6 +51,14 @@ func WriteHeadQueueIndex(db ethdb.KeyValueWriter, index uint64) {
 	}
 }
 
+// DeleteHeadQueueIndex will delete the known tip of the queue, used when
+// every processed enqueue was rolled back
+func DeleteHeadQueueIndex(db ethdb.KeyValueWriter) {
+	if err := db.Delete(headQueueIndexKey); err != nil {
+		log.Crit("Failed to delete queue index", "err", err)
+	}
+}
+
 // ReadHeadVerifiedIndex will read the known tip of the batched transactions
 func ReadHeadVerifiedIndex(db ethdb.KeyValueReader) *uint64 {
 	data, _ := db.Get(headVerifiedIndexKey)
@@ -118,3 +128,50 @@ func ReadEigenBatchIndex(db ethdb.KeyValueReader) *uint64 {
 	ret := new(big.Int).SetBytes(data).Uint64()
 	return &ret
 }
+
+// WriteRollbackJournal will append the journal of a sequencer rollback,
+// the id of the journal is assigned here
+func WriteRollbackJournal(db ethdb.KeyValueStore, journal *types.RollbackJournal) {
+	var count uint64
+	if data, _ := db.Get(rollbackJournalCountKey); len(data) != 0 {
+		count = new(big.Int).SetBytes(data).Uint64()
+	}
+	journal.Id = count
+	UpdateRollbackJournal(db, journal)
+	if err := db.Put(rollbackJournalCountKey, new(big.Int).SetUint64(count+1).Bytes()); err != nil {
+		log.Crit("Failed to store rollback journal count", "err", err)
+	}
+}
+
+// UpdateRollbackJournal will overwrite the journal with the id of the given
+// journal
+func UpdateRollbackJournal(db ethdb.KeyValueWriter, journal *types.RollbackJournal) {
+	data, err := rlp.EncodeToBytes(journal)
+	if err != nil {
+		log.Crit("Failed to encode rollback journal", "err", err)
+	}
+	if err := db.Put(rollbackJournalKey(journal.Id), data); err != nil {
+		log.Crit("Failed to store rollback journal", "err", err)
+	}
+}
+
+// ReadRollbackJournals will read the journals of the sequencer rollbacks,
+// oldest first
+func ReadRollbackJournals(db ethdb.Iteratee) []*types.RollbackJournal {
+	it := db.NewIteratorWithPrefix(rollbackJournalPrefix)
+	defer it.Release()
+
+	var journals []*types.RollbackJournal
+	for it.Next() {
+		if len(it.Key()) != len(rollbackJournalPrefix)+8 {
+			continue
+		}
+		journal := new(types.RollbackJournal)
+		if err := rlp.DecodeBytes(it.Value(), journal); err != nil {
+			log.Error("Invalid rollback journal RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		journals = append(journals, journal)
+	}
+	return journals
+}
@@ -2,6 +2,9 @@ package rawdb
 
 import (
 	"testing"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/core/types"
 )
 
 func TestReadWriteHeadIndex(t *testing.T) {
@@ -38,4 +41,43 @@ func TestReadWriteHeadQueueIndex(t *testing.T) {
 			t.Fatal("Header height mismatch")
 		}
 	}
+	DeleteHeadQueueIndex(db)
+	if got := ReadHeadQueueIndex(db); got != nil {
+		t.Fatalf("Unexpected queue index %d", *got)
+	}
+}
+
+func TestReadWriteRollbackJournals(t *testing.T) {
+	db := NewMemoryDatabase()
+	if journals := ReadRollbackJournals(db); len(journals) != 0 {
+		t.Fatalf("Unexpected rollback journals: %d", len(journals))
+	}
+	targets := []uint64{10, 7, 1 << 32}
+	for _, target := range targets {
+		WriteRollbackJournal(db, &types.RollbackJournal{
+			Target:   target,
+			Reason:   "enqueue reorged",
+			Replayed: []common.Hash{common.HexToHash("0x01")},
+			Requeued: []common.Hash{},
+		})
+	}
+	journals := ReadRollbackJournals(db)
+	if len(journals) != len(targets) {
+		t.Fatalf("Rollback journals mismatch: have %d, want %d", len(journals), len(targets))
+	}
+	for i, journal := range journals {
+		if journal.Id != uint64(i) || journal.Target != targets[i] {
+			t.Fatalf("Rollback journal %d mismatch: id %d, target %d", i, journal.Id, journal.Target)
+		}
+		if len(journal.Replayed) != 1 || journal.Reason != "enqueue reorged" {
+			t.Fatal("Rollback journal content mismatch")
+		}
+	}
+
+	journals[1].Status = types.RollbackDone
+	UpdateRollbackJournal(db, journals[1])
+	journals = ReadRollbackJournals(db)
+	if len(journals) != len(targets) || journals[1].Status != types.RollbackDone {
+		t.Fatal("Rollback journal not updated")
+	}
 }
@@ -68,6 +68,10 @@ var (
 	// eigen da
 	eigenBatchKey = []byte("EigenBatch")
 
+	// rollbackJournalCountKey tracks the number of sequencer rollbacks
+	rollbackJournalCountKey = []byte("RollbackJournalCount")
+	rollbackJournalPrefix   = []byte("rj") // rollbackJournalPrefix + id (uint64 big endian) -> rollback journal
+
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
@@ -178,6 +182,11 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// rollbackJournalKey = rollbackJournalPrefix + id (uint64 big endian)
+func rollbackJournalKey(id uint64) []byte {
+	return append(rollbackJournalPrefix, encodeBlockNumber(id)...)
+}
+
 // preimageKey = preimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(preimagePrefix, hash.Bytes()...)
@@ -99,7 +99,7 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	// about the transaction and calling mechanisms.
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 
-	upgrade.CheckUpgrade(config.ChainID, vmenv.StateDB, bc.ChainDb(), header.Number, tx.L1BlockNumber())
+	upgrade.CheckUpgrade(config, vmenv.StateDB, bc.ChainDb(), header.Number, tx.L1BlockNumber())
 
 	// UsingBVM
 	// Compute the fee related information that is to be included
@@ -521,6 +521,13 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// ResetHead resets the pool to the current head of the chain and blocks until
+// it is done. It is needed after the chain was rewound, setting the head sends
+// no chain head event.
+func (pool *TxPool) ResetHead() {
+	<-pool.requestReset(nil, pool.chain.CurrentBlock().Header())
+}
+
 func (pool *TxPool) ValidateTx(tx *types.Transaction) error {
 	return pool.validateTx(tx, false)
 }
@@ -828,6 +835,7 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 			nilSlot++
 		}
 		errs[nilSlot] = err
+		nilSlot++
 	}
 	// Reorg the pool internals if needed and return
 	done := pool.requestPromoteExecutables(dirtyAddrs)
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+)
+
+// Status of a sequencer rollback. The journal is written as pending before
+// the chain is rewound, a pending journal left behind means the node stopped
+// in the middle of the rollback.
+const (
+	RollbackPending  = "pending"
+	RollbackDone     = "done"
+	RollbackReverted = "reverted"
+)
+
+// RollbackJournal records a sequencer rollback for audit. The transactions
+// of the rewound blocks are either replayed on top of the target, dropped
+// when their enqueue no longer exists on L1, or requeued into the txpool when
+// they no longer apply.
+type RollbackJournal struct {
+	Id          uint64        `json:"id"`
+	Status      string        `json:"status"`
+	Target      uint64        `json:"target"`
+	Reason      string        `json:"reason"`
+	OldHead     uint64        `json:"oldHead"`
+	OldHeadHash common.Hash   `json:"oldHeadHash"`
+	NewHead     uint64        `json:"newHead"`
+	NewHeadHash common.Hash   `json:"newHeadHash"`
+	Replayed    []common.Hash `json:"replayed"`
+	Dropped     []common.Hash `json:"dropped"`
+	Requeued    []common.Hash `json:"requeued"`
+	Timestamp   uint64        `json:"timestamp"`
+}
@@ -64,6 +64,7 @@ type Batch struct {
 	BlockNumber       uint64         `json:"blockNumber"`
 	Timestamp         uint64         `json:"timestamp"`
 	Submitter         common.Address `json:"submitter"`
+	L1TransactionHash *common.Hash   `json:"l1TransactionHash,omitempty"`
 }
 
 type Datastore struct {
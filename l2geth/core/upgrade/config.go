@@ -3,6 +3,7 @@ package upgrade
 import (
 	"math/big"
 
+	"github.com/mantlenetworkio/mantle/l2geth/ethdb"
 	"github.com/mantlenetworkio/mantle/l2geth/params"
 )
 
@@ -90,6 +91,31 @@ func NewMantleUpgradeConfig(chainID *big.Int) *Config {
 	}
 }
 
+// NewUpgradeConfig returns the upgrade schedule of the chain, which is the
+// schedule bundled for its chain ID with the heights of the chain config
+// MantleUpgrades on top
+func NewUpgradeConfig(chainConfig *params.ChainConfig) *Config {
+	bundled := NewMantleUpgradeConfig(chainConfig.ChainID)
+	overrides := chainConfig.MantleUpgrades
+	if overrides == nil {
+		return bundled
+	}
+	c := *bundled
+	c.tssRewardL2Block = overrideBlock(c.tssRewardL2Block, overrides.TssRewardL2Block)
+	c.mantleTokenL2Block = overrideBlock(c.mantleTokenL2Block, overrides.MantleTokenL2Block)
+	c.updateGasLimitL2Block = overrideBlock(c.updateGasLimitL2Block, overrides.UpdateGasLimitL2Block)
+	c.eigenDaL2Block = overrideBlock(c.eigenDaL2Block, overrides.EigenDaL2Block)
+	c.mockUpgradeL1Block = overrideBlock(c.mockUpgradeL1Block, overrides.MockUpgradeL1Block)
+	return &c
+}
+
+func overrideBlock(bundled, override *big.Int) *big.Int {
+	if override == nil {
+		return bundled
+	}
+	return override
+}
+
 // IsTssReward returns whether num is either equal to the TssReward fork block or greater.
 // Compare with L2 BlockNumber
 func (c *Config) IsTssReward(num *big.Int) bool {
@@ -120,6 +146,31 @@ func (c *Config) IsMockUpgradeBasedOnL1BlockNumber(num *big.Int) bool {
 	return isBlockForked(c.mockUpgradeL1Block, num)
 }
 
+// Activation is the schedule of an upgrade and whether it took place
+type Activation struct {
+	Name string
+	// L1 is set when the upgrade height is a Layer1 block height
+	L1 bool
+	// Exact is set when the upgrade runs once at the height instead of
+	// switching the logic from the height on
+	Exact     bool
+	Block     *big.Int
+	Activated bool
+}
+
+// Activations returns the upgrades of the chain with their activation status
+// at the given Layer2 and Layer1 heads. Upgrades without a height are never
+// activated.
+func (c *Config) Activations(db ethdb.Reader, l2Head, l1Head *big.Int) []Activation {
+	return []Activation{
+		{Name: "tssReward", Exact: true, Block: c.tssRewardL2Block, Activated: isBlockForked(c.tssRewardL2Block, l2Head)},
+		{Name: "mantleToken", Exact: true, Block: c.mantleTokenL2Block, Activated: isBlockForked(c.mantleTokenL2Block, l2Head)},
+		{Name: "eigenDa", Exact: true, Block: c.eigenDaL2Block, Activated: isBlockForked(c.eigenDaL2Block, l2Head)},
+		{Name: "updateGasLimit", Block: c.updateGasLimitL2Block, Activated: c.IsUpdateGasLimitBlock(l2Head)},
+		{Name: "mockUpgrade", L1: true, Block: c.mockUpgradeL1Block, Activated: existUpgradeFlag(db, mockUpgradeFlag)},
+	}
+}
+
 // isBlockForked returns whether a fork scheduled at block s is active at the
 // given head block.
 // isBlockForked is used to compare with Layer1 block height
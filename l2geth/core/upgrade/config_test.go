@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/mantlenetworkio/mantle/l2geth/core/rawdb"
 	"github.com/mantlenetworkio/mantle/l2geth/params"
 )
 
@@ -104,3 +105,50 @@ func TestConfig_IsUpdateGasLimitBlock(t *testing.T) {
 	result = upgradeConfig.IsUpdateGasLimitBlock(big.NewInt(222_072))
 	require.Equal(t, result, false, "update gaslimit upgrade height does not reach to")
 }
+
+func TestConfig_Activations(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	upgradeConfig := NewMantleUpgradeConfig(params.MantleTestnetChainID)
+
+	activated := func(activations []Activation) map[string]bool {
+		ret := make(map[string]bool)
+		for _, activation := range activations {
+			ret[activation.Name] = activation.Activated
+		}
+		return ret
+	}
+	status := activated(upgradeConfig.Activations(db, big.NewInt(8_280_000), big.NewInt(1)))
+	require.True(t, status["eigenDa"])
+	require.True(t, status["updateGasLimit"])
+	require.False(t, status["tssReward"])
+	require.False(t, status["mockUpgrade"])
+
+	// the exact upgrades stay activated once their height passed
+	status = activated(upgradeConfig.Activations(db, big.NewInt(11_000_001), big.NewInt(1)))
+	require.True(t, status["tssReward"])
+	require.True(t, status["mantleToken"])
+
+	require.NoError(t, writeUpgradeFlag(db, mockUpgradeFlag, big.NewInt(1).Bytes()))
+	status = activated(upgradeConfig.Activations(db, big.NewInt(1), big.NewInt(1)))
+	require.True(t, status["mockUpgrade"])
+}
+
+func TestNewUpgradeConfig(t *testing.T) {
+	chainConfig := &params.ChainConfig{ChainID: params.MantleTestnetChainID}
+	require.Equal(t, TestnetConfig, NewUpgradeConfig(chainConfig))
+
+	chainConfig.MantleUpgrades = &params.MantleUpgradesConfig{
+		TssRewardL2Block:   big.NewInt(100),
+		EigenDaL2Block:     big.NewInt(0),
+		MockUpgradeL1Block: big.NewInt(50),
+	}
+	upgradeConfig := NewUpgradeConfig(chainConfig)
+	require.True(t, upgradeConfig.IsTssReward(big.NewInt(100)))
+	require.False(t, upgradeConfig.IsTssReward(big.NewInt(11_000_000)))
+	require.False(t, upgradeConfig.IsEigenDa(big.NewInt(8_280_000)))
+	// Upgrades without an override keep the bundled height
+	require.True(t, upgradeConfig.IsMantleToken(big.NewInt(11_000_000)))
+	require.True(t, upgradeConfig.IsMockUpgradeBasedOnL1BlockNumber(big.NewInt(60)))
+	// The bundled config is left untouched
+	require.True(t, TestnetConfig.IsTssReward(big.NewInt(11_000_000)))
+}
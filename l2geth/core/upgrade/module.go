@@ -9,6 +9,7 @@ import (
 	"github.com/mantlenetworkio/mantle/l2geth/core/vm"
 	"github.com/mantlenetworkio/mantle/l2geth/ethdb"
 	"github.com/mantlenetworkio/mantle/l2geth/log"
+	"github.com/mantlenetworkio/mantle/l2geth/params"
 	"github.com/mantlenetworkio/mantle/l2geth/rollup/rcfg"
 )
 
@@ -45,9 +46,9 @@ var (
 )
 
 // CheckUpgrade used to execute upgrade logic
-func CheckUpgrade(chainID *big.Int, statedb vm.StateDB, ethdb ethdb.Database, l2BlockNumber *big.Int, l1BlockNumber *big.Int) {
-	log.Info("CheckUpgrade", "chain id", chainID)
-	upgradeConfig := NewMantleUpgradeConfig(chainID)
+func CheckUpgrade(chainConfig *params.ChainConfig, statedb vm.StateDB, ethdb ethdb.Database, l2BlockNumber *big.Int, l1BlockNumber *big.Int) {
+	log.Info("CheckUpgrade", "chain id", chainConfig.ChainID)
+	upgradeConfig := NewUpgradeConfig(chainConfig)
 
 	// upgrade based on layer2 block height
 	l2TssRewardUpgrade(statedb, upgradeConfig, l2BlockNumber)
@@ -89,6 +89,24 @@ func (b *EthAPIBackend) GetRollupContext() (uint64, uint64, uint64) {
 	return index, queueIndex, verifiedIndex
 }
 
+// GetBatchContext returns the latest processed batch index and eigen batch
+// index, nil when none was processed yet
+func (b *EthAPIBackend) GetBatchContext() (*uint64, *uint64) {
+	return b.eth.syncService.GetLatestBatchIndex(), b.eth.syncService.GetLatestEigenBatchIndex()
+}
+
+// GetFeeOracleContext returns the BVM_GasPriceOracle values cached by the
+// sync service
+func (b *EthAPIBackend) GetFeeOracleContext() (*big.Int, *big.Float, *big.Int, *big.Int) {
+	overhead, _ := b.rollupGpo.SuggestOverhead(context.Background())
+	scalar, _ := b.rollupGpo.SuggestScalar(context.Background())
+	return overhead, scalar, b.rollupGpo.Charge(), b.rollupGpo.DASwitch()
+}
+
+func (b *EthAPIBackend) GetL1SyncLag() (uint64, error) {
+	return b.eth.syncService.L1SyncLag()
+}
+
 // ChainConfig returns the active chain configuration.
 func (b *EthAPIBackend) ChainConfig() *params.ChainConfig {
 	return b.eth.blockchain.Config()
@@ -393,6 +411,18 @@ func (b *EthAPIBackend) SetL2GasPrice(ctx context.Context, gasPrice *big.Int) er
 	return b.rollupGpo.SetL2GasPrice(gasPrice)
 }
 
+func (b *EthAPIBackend) SequencerRollback(ctx context.Context, target uint64, reason string) (*types.RollbackJournal, error) {
+	return b.eth.syncService.SequencerRollback(target, reason)
+}
+
+func (b *EthAPIBackend) GetRollbackJournals() []*types.RollbackJournal {
+	return b.eth.syncService.RollbackJournals()
+}
+
+func (b *EthAPIBackend) SubscribeRollbackEvent(ch chan<- core.RollbackEvent) event.Subscription {
+	return b.eth.syncService.SubscribeRollbackEvent(ch)
+}
+
 func (b *EthAPIBackend) ChainDb() ethdb.Database {
 	return b.eth.ChainDb()
 }
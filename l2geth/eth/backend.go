@@ -144,6 +144,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
 		return nil, genesisErr
 	}
+	if config.OverrideMantleUpgrades != nil {
+		chainConfig.MantleUpgrades = chainConfig.MantleUpgrades.Merge(config.OverrideMantleUpgrades)
+	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
 	eth := &Ethereum{
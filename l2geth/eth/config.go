@@ -181,6 +181,9 @@ type Config struct {
 	// MuirGlacier block override (TODO: remove after the fork)
 	OverrideMuirGlacier *big.Int
 
+	// Mantle upgrade heights overriding the ones of the chain config
+	OverrideMantleUpgrades *params.MantleUpgradesConfig `toml:",omitempty"`
+
 	// Mantle Rollup Config
 	Rollup rollup.Config
 }
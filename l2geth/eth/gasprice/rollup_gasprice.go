@@ -146,6 +146,13 @@ func (gpo *RollupOracle) SetIsBurning(isBurning *big.Int) error {
 	return nil
 }
 
+// Charge returns the cached charge value
+func (gpo *RollupOracle) Charge() *big.Int {
+	gpo.chargeLock.RLock()
+	defer gpo.chargeLock.RUnlock()
+	return gpo.charge
+}
+
 // SetCharge sets the charge value held in the BVM_GasPriceOracle
 func (gpo *RollupOracle) SetCharge(charge *big.Int) error {
 	gpo.chargeLock.Lock()
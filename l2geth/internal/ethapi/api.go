@@ -39,6 +39,7 @@ import (
 	"github.com/mantlenetworkio/mantle/l2geth/core"
 	"github.com/mantlenetworkio/mantle/l2geth/core/rawdb"
 	"github.com/mantlenetworkio/mantle/l2geth/core/types"
+	"github.com/mantlenetworkio/mantle/l2geth/core/upgrade"
 	"github.com/mantlenetworkio/mantle/l2geth/core/vm"
 	"github.com/mantlenetworkio/mantle/l2geth/crypto"
 	"github.com/mantlenetworkio/mantle/l2geth/ethclient"
@@ -2060,6 +2061,9 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 // functionality.
 type PublicRollupAPI struct {
 	b Backend
+
+	// txStatusSubs counts the open transaction status subscriptions
+	txStatusSubs int32
 }
 
 // NewPublicRollupAPI creates a new API definition for the rollup methods of the
@@ -2084,11 +2088,43 @@ type RollupContext struct {
 	VerifiedIndex uint64 `json:"verifiedIndex"`
 }
 
+// BatchContext represents the batches processed by the node.
+// BatchIndex is the last processed CanonicalTransactionChain batch index
+// EigenBatchIndex is the last processed EigenDA batch index
+type BatchContext struct {
+	BatchIndex      *hexutil.Uint64 `json:"batchIndex"`
+	EigenBatchIndex *hexutil.Uint64 `json:"eigenBatchIndex"`
+}
+
+// FeeOracleContext represents the BVM_GasPriceOracle values cached by the node
+type FeeOracleContext struct {
+	DASwitch *hexutil.Big `json:"daSwitch"`
+	Overhead *hexutil.Big `json:"overhead"`
+	Scalar   string       `json:"scalar"`
+	Charge   *hexutil.Big `json:"charge"`
+}
+
+// UpgradeStatus represents the schedule of an upgrade and whether it took place.
+// Basis is the chain the upgrade height refers to, l1 or l2. Upgrades without
+// a positive height are not scheduled.
+type UpgradeStatus struct {
+	Name      string       `json:"name"`
+	Basis     string       `json:"basis"`
+	Exact     bool         `json:"exact"`
+	Block     *hexutil.Big `json:"block"`
+	Scheduled bool         `json:"scheduled"`
+	Activated bool         `json:"activated"`
+}
+
 type rollupInfo struct {
-	Mode          string        `json:"mode"`
-	Syncing       bool          `json:"syncing"`
-	EthContext    EthContext    `json:"ethContext"`
-	RollupContext RollupContext `json:"rollupContext"`
+	Mode          string           `json:"mode"`
+	Syncing       bool             `json:"syncing"`
+	EthContext    EthContext       `json:"ethContext"`
+	RollupContext RollupContext    `json:"rollupContext"`
+	BatchContext  BatchContext     `json:"batchContext"`
+	FeeOracle     FeeOracleContext `json:"feeOracle"`
+	L1SyncLag     *hexutil.Uint64  `json:"l1SyncLag"`
+	Upgrades      []UpgradeStatus  `json:"upgrades"`
 }
 
 func (api *PublicRollupAPI) GetInfo(ctx context.Context) rollupInfo {
@@ -2099,8 +2135,10 @@ func (api *PublicRollupAPI) GetInfo(ctx context.Context) rollupInfo {
 	syncing := api.b.IsSyncing()
 	bn, ts := api.b.GetEthContext()
 	index, queueIndex, verifiedIndex := api.b.GetRollupContext()
+	batchIndex, eigenBatchIndex := api.b.GetBatchContext()
+	overhead, scalar, charge, daSwitch := api.b.GetFeeOracleContext()
 
-	return rollupInfo{
+	info := rollupInfo{
 		Mode:    mode,
 		Syncing: syncing,
 		EthContext: EthContext{
@@ -2112,7 +2150,55 @@ func (api *PublicRollupAPI) GetInfo(ctx context.Context) rollupInfo {
 			QueueIndex:    queueIndex,
 			VerifiedIndex: verifiedIndex,
 		},
+		BatchContext: BatchContext{
+			BatchIndex:      (*hexutil.Uint64)(batchIndex),
+			EigenBatchIndex: (*hexutil.Uint64)(eigenBatchIndex),
+		},
+		FeeOracle: FeeOracleContext{
+			DASwitch: (*hexutil.Big)(daSwitch),
+			Overhead: (*hexutil.Big)(overhead),
+			Charge:   (*hexutil.Big)(charge),
+		},
+	}
+	if scalar != nil {
+		info.FeeOracle.Scalar = scalar.String()
+	}
+	// The lag needs the data transport layer, leave it out when it is unreachable
+	if lag, err := api.b.GetL1SyncLag(); err == nil {
+		info.L1SyncLag = (*hexutil.Uint64)(&lag)
+	} else {
+		log.Debug("Cannot get L1 sync lag", "err", err)
+	}
+	info.Upgrades = api.upgrades(new(big.Int).SetUint64(bn))
+	return info
+}
+
+// GetUpgrades returns the upgrade schedule of the chain, with the heights
+// overridden by the chain config, and whether each upgrade took place
+func (api *PublicRollupAPI) GetUpgrades(ctx context.Context) []UpgradeStatus {
+	bn, _ := api.b.GetEthContext()
+	return api.upgrades(new(big.Int).SetUint64(bn))
+}
+
+func (api *PublicRollupAPI) upgrades(l1Head *big.Int) []UpgradeStatus {
+	current := api.b.CurrentBlock()
+	upgradeConfig := upgrade.NewUpgradeConfig(api.b.ChainConfig())
+	var upgrades []UpgradeStatus
+	for _, activation := range upgradeConfig.Activations(api.b.ChainDb(), current.Number(), l1Head) {
+		basis := "l2"
+		if activation.L1 {
+			basis = "l1"
+		}
+		upgrades = append(upgrades, UpgradeStatus{
+			Name:      activation.Name,
+			Basis:     basis,
+			Exact:     activation.Exact,
+			Block:     (*hexutil.Big)(activation.Block),
+			Scheduled: activation.Block != nil && activation.Block.Sign() > 0,
+			Activated: activation.Activated,
+		})
 	}
+	return upgrades
 }
 
 type gasPrices struct {
@@ -2136,6 +2222,37 @@ func (api *PublicRollupAPI) GasPrices(ctx context.Context) (*gasPrices, error) {
 	}, nil
 }
 
+// Rollbacks sends a notification with the journal each time the sequencer
+// rolls back the chain
+func (api *PublicRollupAPI) Rollbacks(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		rollbacks := make(chan core.RollbackEvent, 16)
+		rollbackSub := api.b.SubscribeRollbackEvent(rollbacks)
+
+		for {
+			select {
+			case ev := <-rollbacks:
+				notifier.Notify(rpcSub.ID, ev.Journal)
+			case <-rpcSub.Err():
+				rollbackSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				rollbackSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // PrivatelRollupAPI provides private RPC methods to control the sequencer.
 // These methods can be abused by external users and must be considered insecure for use by untrusted users.
 type PrivateRollupAPI struct {
@@ -2159,6 +2276,17 @@ func (api *PrivateRollupAPI) SetL2GasPrice(ctx context.Context, gasPrice hexutil
 	return api.b.SetL2GasPrice(ctx, (*big.Int)(&gasPrice))
 }
 
+// SequencerRollback rewinds the sequencer to the target L2 height and returns
+// the journal of the rollback
+func (api *PrivateRollupAPI) SequencerRollback(ctx context.Context, target hexutil.Uint64, reason string) (*types.RollbackJournal, error) {
+	return api.b.SequencerRollback(ctx, uint64(target), reason)
+}
+
+// GetRollbackJournals returns the journals of the sequencer rollbacks, oldest first
+func (api *PrivateRollupAPI) GetRollbackJournals(ctx context.Context) []*types.RollbackJournal {
+	return api.b.GetRollbackJournals()
+}
+
 // PublicDebugAPI is the collection of Ethereum APIs exposed over the public
 // debugging endpoint.
 type PublicDebugAPI struct {
@@ -91,6 +91,9 @@ type Backend interface {
 	IsSyncing() bool
 	GetEthContext() (uint64, uint64)
 	GetRollupContext() (uint64, uint64, uint64)
+	GetBatchContext() (*uint64, *uint64)
+	GetFeeOracleContext() (overhead *big.Int, scalar *big.Float, charge *big.Int, daSwitch *big.Int)
+	GetL1SyncLag() (uint64, error)
 	GasLimit() uint64
 	SuggestL1GasPrice(ctx context.Context) (*big.Int, error)
 	SuggestDAGasPrice(ctx context.Context) (*big.Int, error)
@@ -99,6 +102,9 @@ type Backend interface {
 	SetL2GasPrice(context.Context, *big.Int) error
 	IngestTransactions([]*types.Transaction) error
 	SequencerClientHttp() string
+	SequencerRollback(ctx context.Context, target uint64, reason string) (*types.RollbackJournal, error)
+	GetRollbackJournals() []*types.RollbackJournal
+	SubscribeRollbackEvent(ch chan<- core.RollbackEvent) event.Subscription
 }
 
 func GetAPIs(apiBackend Backend) []rpc.API {
@@ -0,0 +1,245 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
+	"github.com/mantlenetworkio/mantle/l2geth/log"
+	"github.com/mantlenetworkio/mantle/l2geth/rpc"
+)
+
+// TxFinalityStage is how far a transaction has progressed towards finality
+// on layer1, every stage implies the ones before it.
+type TxFinalityStage uint
+
+const (
+	// TxStageSequenced means the transaction is included in a layer2 block
+	TxStageSequenced TxFinalityStage = iota
+	// TxStageDAPosted means the transaction data is posted to EigenDA
+	TxStageDAPosted
+	// TxStageStateCommitted means the state root of the transaction is
+	// committed to the state commitment chain
+	TxStageStateCommitted
+	// TxStageFinalized means the challenge window of the state root elapsed
+	TxStageFinalized
+)
+
+var txFinalityStageNames = []string{"sequenced", "daPosted", "stateCommitted", "finalized"}
+
+func (s TxFinalityStage) String() string {
+	if int(s) < len(txFinalityStageNames) {
+		return txFinalityStageNames[s]
+	}
+	return fmt.Sprintf("TxFinalityStage(%d)", uint(s))
+}
+
+func (s TxFinalityStage) MarshalText() ([]byte, error) {
+	if int(s) >= len(txFinalityStageNames) {
+		return nil, fmt.Errorf("unknown finality stage %d", uint(s))
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *TxFinalityStage) UnmarshalText(input []byte) error {
+	for i, name := range txFinalityStageNames {
+		if name == string(input) {
+			*s = TxFinalityStage(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown finality stage %s", input)
+}
+
+// TxStageInfo is when and where a transaction reached a finality stage. The
+// layer1 transaction hash is absent for the stages that have none.
+type TxStageInfo struct {
+	L1TransactionHash *common.Hash    `json:"l1TransactionHash,omitempty"`
+	L1BlockNumber     *hexutil.Uint64 `json:"l1BlockNumber,omitempty"`
+	Timestamp         *hexutil.Uint64 `json:"timestamp,omitempty"`
+}
+
+// RPCTransactionStatus is the finality stage of a transaction together with
+// the stages it reached so far
+type RPCTransactionStatus struct {
+	TransactionHash common.Hash     `json:"transactionHash"`
+	BlockHash       common.Hash     `json:"blockHash"`
+	BlockNumber     hexutil.Uint64  `json:"blockNumber"`
+	Stage           TxFinalityStage `json:"stage"`
+	Sequenced       *TxStageInfo    `json:"sequenced"`
+	DAPosted        *TxStageInfo    `json:"daPosted,omitempty"`
+	StateCommitted  *TxStageInfo    `json:"stateCommitted,omitempty"`
+	Finalized       *TxStageInfo    `json:"finalized,omitempty"`
+	CurrentL1Height *hexutil.Uint64 `json:"currentL1Height,omitempty"`
+	ChallengeBlocks *hexutil.Uint64 `json:"challengeBlocks,omitempty"`
+}
+
+const (
+	// maxTxStatusSubscriptions bounds the transaction status subscriptions,
+	// each of them polls the data transport layer once per layer1 block
+	maxTxStatusSubscriptions = 1000
+	// txStatusUnknownPolls is how many times the status of a transaction the
+	// node does not know is polled before the subscription gives up
+	txStatusUnknownPolls = 25
+)
+
+var (
+	errTxNotFound          = errors.New("transaction not found")
+	errTooManyTxStatusSubs = errors.New("too many transaction status subscriptions")
+)
+
+// GetTransactionStatus returns the finality stage of the transaction, the
+// stages come from the status the data transport layer keeps for it
+func (api *PublicRollupAPI) GetTransactionStatus(ctx context.Context, txHash common.Hash) (*RPCTransactionStatus, error) {
+	tx, blockHash, blockNumber, _, err := api.b.GetTransaction(ctx, txHash)
+	if err != nil || tx == nil {
+		return nil, errTxNotFound
+	}
+	status := &RPCTransactionStatus{
+		TransactionHash: txHash,
+		BlockHash:       blockHash,
+		BlockNumber:     hexutil.Uint64(blockNumber),
+		Stage:           TxStageSequenced,
+		Sequenced:       &TxStageInfo{},
+	}
+	if l1BlockNumber := tx.L1BlockNumber(); l1BlockNumber != nil {
+		status.Sequenced.L1BlockNumber = newUint64(l1BlockNumber.Uint64())
+	}
+	if block, _ := api.b.BlockByNumber(ctx, rpc.BlockNumber(blockNumber)); block != nil {
+		status.Sequenced.Timestamp = newUint64(block.Time())
+	}
+
+	txStatus, err := api.b.GetTxStatusByHash(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the layer1 status of the transaction: %w", err)
+	}
+	if txStatus == nil {
+		// The transaction is not known to layer1 yet
+		return status, nil
+	}
+	if txStatus.CurrentL1Height > 0 {
+		status.CurrentL1Height = newUint64(uint64(txStatus.CurrentL1Height))
+	}
+	if ds := txStatus.Datastore; ds != nil && ds.InitTxHash != "" {
+		hash := common.HexToHash(ds.InitTxHash)
+		status.DAPosted = &TxStageInfo{
+			L1TransactionHash: &hash,
+			L1BlockNumber:     parseUint64(ds.InitBlockNumber),
+			Timestamp:         parseUint64(ds.InitTime),
+		}
+		status.Stage = TxStageDAPosted
+	}
+	if txStatus.StateRoot == nil || txStatus.Batch == nil {
+		return status, nil
+	}
+	batch := txStatus.Batch
+	status.StateCommitted = &TxStageInfo{
+		L1BlockNumber: newUint64(batch.BlockNumber),
+		Timestamp:     newUint64(batch.Timestamp),
+	}
+	if batch.L1TransactionHash != nil {
+		hash := *batch.L1TransactionHash
+		status.StateCommitted.L1TransactionHash = &hash
+	}
+	status.Stage = TxStageStateCommitted
+
+	// The fraud proof window is in seconds, the challenge is over once as
+	// many layer1 blocks passed on top of the state batch
+	challengeBlocks := uint64(txStatus.Fraudproofwindow / l1BlockInterval)
+	status.ChallengeBlocks = newUint64(challengeBlocks)
+	if txStatus.CurrentL1Height-int64(batch.BlockNumber) >= int64(challengeBlocks) {
+		status.Finalized = &TxStageInfo{
+			L1BlockNumber: newUint64(batch.BlockNumber + challengeBlocks),
+			Timestamp:     newUint64(batch.Timestamp + uint64(txStatus.Fraudproofwindow)),
+		}
+		status.Stage = TxStageFinalized
+	}
+	return status, nil
+}
+
+// TransactionStatus sends a notification with the status of the transaction
+// each time it reaches a new finality stage, the subscription ends once the
+// transaction is finalized or when the node does not know the transaction
+func (api *PublicRollupAPI) TransactionStatus(ctx context.Context, txHash common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if atomic.AddInt32(&api.txStatusSubs, 1) > maxTxStatusSubscriptions {
+		atomic.AddInt32(&api.txStatusSubs, -1)
+		return &rpc.Subscription{}, errTooManyTxStatusSubs
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer atomic.AddInt32(&api.txStatusSubs, -1)
+		// The stages advance with layer1, poll once per layer1 block
+		api.watchTransactionStatus(txHash, time.Duration(l1BlockInterval)*time.Second, func(status *RPCTransactionStatus) {
+			notifier.Notify(rpcSub.ID, status)
+		}, rpcSub.Err(), notifier.Closed())
+	}()
+
+	return rpcSub, nil
+}
+
+// watchTransactionStatus polls the status of the transaction and passes it to
+// notify whenever the stage advances. It returns once the transaction is
+// finalized, after txStatusUnknownPolls polls that did not find it, or when
+// the subscription is gone.
+func (api *PublicRollupAPI) watchTransactionStatus(txHash common.Hash, interval time.Duration, notify func(*RPCTransactionStatus), unsubscribed <-chan error, closed <-chan interface{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	notified := false
+	unknown := 0
+	var last TxFinalityStage
+	for {
+		status, err := api.GetTransactionStatus(context.Background(), txHash)
+		switch {
+		case errors.Is(err, errTxNotFound):
+			unknown++
+			if unknown >= txStatusUnknownPolls {
+				log.Debug("Giving up on the status of an unknown transaction", "hash", txHash)
+				return
+			}
+		case err != nil:
+			// The data transport layer may be back at the next poll
+			log.Debug("Cannot get transaction status", "hash", txHash, "err", err)
+		case !notified || status.Stage > last:
+			unknown = 0
+			notify(status)
+			notified = true
+			last = status.Stage
+			if last == TxStageFinalized {
+				return
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-unsubscribed:
+			return
+		case <-closed:
+			return
+		}
+	}
+}
+
+func newUint64(n uint64) *hexutil.Uint64 {
+	ret := hexutil.Uint64(n)
+	return &ret
+}
+
+// parseUint64 parses the decimal numbers of the datastore, nil when absent
+func parseUint64(s string) *hexutil.Uint64 {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return newUint64(n)
+}
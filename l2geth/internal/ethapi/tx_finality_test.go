@@ -0,0 +1,298 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/core/types"
+	"github.com/mantlenetworkio/mantle/l2geth/rpc"
+)
+
+// txStatusBackend serves a single transaction in block 5, every call to
+// GetTxStatusByHash returns the next of the statuses, the last one repeats
+type txStatusBackend struct {
+	Backend
+
+	mu       sync.Mutex
+	unknown  bool
+	statuses []*types.TxStatusResponse
+	err      error
+	calls    int
+}
+
+func (b *txStatusBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls++
+	if b.unknown {
+		return nil, common.Hash{}, 0, 0, nil
+	}
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	tx.SetTransactionMeta(types.NewTransactionMeta(big.NewInt(90), 400, nil, types.QueueOriginSequencer, nil, nil, nil))
+	return tx, common.HexToHash("0xb5"), 5, 0, nil
+}
+
+func (b *txStatusBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(number)), Time: 500}), nil
+}
+
+func (b *txStatusBackend) GetTxStatusByHash(ctx context.Context, blockNumber uint64) (*types.TxStatusResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.statuses) == 0 {
+		return nil, nil
+	}
+	status := b.statuses[0]
+	if len(b.statuses) > 1 {
+		b.statuses = b.statuses[1:]
+	}
+	return status, nil
+}
+
+var testDatastore = &types.Datastore{InitTxHash: "0xda", InitBlockNumber: "100", InitTime: "1000"}
+
+func testCommittedStatus(currentL1Height int64) *types.TxStatusResponse {
+	return &types.TxStatusResponse{
+		StateRoot:        &types.StateRoot{Index: 4},
+		Batch:            &types.Batch{BlockNumber: 110, Timestamp: 2000},
+		CurrentL1Height:  currentL1Height,
+		Datastore:        testDatastore,
+		Fraudproofwindow: 120,
+	}
+}
+
+func TestGetTransactionStatus(t *testing.T) {
+	daHash := common.HexToHash("0xda")
+	sequenced := &TxStageInfo{L1BlockNumber: newUint64(90), Timestamp: newUint64(500)}
+	daPosted := &TxStageInfo{L1TransactionHash: &daHash, L1BlockNumber: newUint64(100), Timestamp: newUint64(1000)}
+	committed := &TxStageInfo{L1BlockNumber: newUint64(110), Timestamp: newUint64(2000)}
+
+	tests := []struct {
+		name   string
+		status *types.TxStatusResponse
+		want   *RPCTransactionStatus
+	}{
+		{
+			name: "sequenced",
+			want: &RPCTransactionStatus{Stage: TxStageSequenced, Sequenced: sequenced},
+		},
+		{
+			name:   "da posted",
+			status: &types.TxStatusResponse{Datastore: testDatastore, CurrentL1Height: 105},
+			want: &RPCTransactionStatus{
+				Stage:           TxStageDAPosted,
+				Sequenced:       sequenced,
+				DAPosted:        daPosted,
+				CurrentL1Height: newUint64(105),
+			},
+		},
+		{
+			name:   "state committed",
+			status: testCommittedStatus(119),
+			want: &RPCTransactionStatus{
+				Stage:           TxStageStateCommitted,
+				Sequenced:       sequenced,
+				DAPosted:        daPosted,
+				StateCommitted:  committed,
+				CurrentL1Height: newUint64(119),
+				ChallengeBlocks: newUint64(10),
+			},
+		},
+		{
+			name:   "finalized",
+			status: testCommittedStatus(120),
+			want: &RPCTransactionStatus{
+				Stage:           TxStageFinalized,
+				Sequenced:       sequenced,
+				DAPosted:        daPosted,
+				StateCommitted:  committed,
+				Finalized:       &TxStageInfo{L1BlockNumber: newUint64(120), Timestamp: newUint64(2120)},
+				CurrentL1Height: newUint64(120),
+				ChallengeBlocks: newUint64(10),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &txStatusBackend{}
+			if tt.status != nil {
+				b.statuses = []*types.TxStatusResponse{tt.status}
+			}
+			status, err := NewPublicRollupAPI(b).GetTransactionStatus(context.Background(), common.Hash{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.want.BlockHash = common.HexToHash("0xb5")
+			tt.want.BlockNumber = 5
+			if !reflect.DeepEqual(status, tt.want) {
+				have, _ := json.Marshal(status)
+				want, _ := json.Marshal(tt.want)
+				t.Fatalf("status mismatch\nhave %s\nwant %s", have, want)
+			}
+		})
+	}
+}
+
+func TestGetTransactionStatusErrors(t *testing.T) {
+	api := NewPublicRollupAPI(&txStatusBackend{unknown: true})
+	if _, err := api.GetTransactionStatus(context.Background(), common.Hash{}); err != errTxNotFound {
+		t.Fatalf("expected %v, got %v", errTxNotFound, err)
+	}
+
+	// The status must not fall back to sequenced when the data transport
+	// layer cannot be reached
+	dtlErr := errors.New("connection refused")
+	api = NewPublicRollupAPI(&txStatusBackend{err: dtlErr})
+	if _, err := api.GetTransactionStatus(context.Background(), common.Hash{}); !errors.Is(err, dtlErr) {
+		t.Fatalf("expected %v, got %v", dtlErr, err)
+	}
+}
+
+// dtlDAPostedResponse is what the data transport layer serves on
+// /tx/status/index/:index once the datastore is posted but before the state
+// roots of the transaction are committed
+const dtlDAPostedResponse = `{
+	"batch": null,
+	"stateRoots": null,
+	"daBatchIndex": 3,
+	"currentL1BlockNumber": 105,
+	"datastore": {
+		"dataStoreId": "7",
+		"storeNumber": "7",
+		"durationDataStoreId": "2",
+		"index": "1",
+		"dataCommitment": "0x01",
+		"msgHash": "0x02",
+		"stakesFromBlockNumber": "99",
+		"initTime": "1000",
+		"expireTime": "2000",
+		"duration": 1,
+		"numSys": "8",
+		"numPar": "4",
+		"degree": "64",
+		"storePeriodLength": "1000",
+		"fee": "0",
+		"confirmer": "0x03",
+		"header": "0x04",
+		"initTxHash": "0x00000000000000000000000000000000000000000000000000000000000000da",
+		"initGasUsed": "21000",
+		"initBlockNumber": "100",
+		"confirmed": false,
+		"ethSigned": "0",
+		"eigenSigned": "0",
+		"nonSignerPubKeyHashes": [],
+		"signatoryRecord": "0x",
+		"confirmTxHash": "",
+		"confirmGasUsed": "0"
+	},
+	"fraudProofWindow": 120
+}`
+
+func TestGetTransactionStatusDTLResponse(t *testing.T) {
+	var txStatus types.TxStatusResponse
+	if err := json.Unmarshal([]byte(dtlDAPostedResponse), &txStatus); err != nil {
+		t.Fatal(err)
+	}
+	b := &txStatusBackend{statuses: []*types.TxStatusResponse{&txStatus}}
+	status, err := NewPublicRollupAPI(b).GetTransactionStatus(context.Background(), common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Stage != TxStageDAPosted {
+		t.Fatalf("stage mismatch: have %s, want %s", status.Stage, TxStageDAPosted)
+	}
+	if status.StateCommitted != nil {
+		t.Fatalf("state committed before the state roots: %+v", status.StateCommitted)
+	}
+	daHash := common.HexToHash("0xda")
+	want := &TxStageInfo{L1TransactionHash: &daHash, L1BlockNumber: newUint64(100), Timestamp: newUint64(1000)}
+	if !reflect.DeepEqual(status.DAPosted, want) {
+		t.Fatalf("da posted mismatch: have %+v, want %+v", status.DAPosted, want)
+	}
+}
+
+func TestTxStageInfoJSON(t *testing.T) {
+	data, err := json.Marshal(&TxStageInfo{L1BlockNumber: newUint64(110), Timestamp: newUint64(2000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"l1BlockNumber":"0x6e","timestamp":"0x7d0"}`; string(data) != want {
+		t.Fatalf("have %s, want %s", data, want)
+	}
+}
+
+func TestWatchTransactionStatus(t *testing.T) {
+	b := &txStatusBackend{statuses: []*types.TxStatusResponse{
+		nil,
+		{Datastore: testDatastore},
+		{Datastore: testDatastore},
+		testCommittedStatus(115),
+		testCommittedStatus(120),
+	}}
+	var stages []TxFinalityStage
+	NewPublicRollupAPI(b).watchTransactionStatus(common.Hash{}, time.Millisecond, func(status *RPCTransactionStatus) {
+		stages = append(stages, status.Stage)
+	}, nil, nil)
+
+	// Every stage is sent once and the watch ends with the finalized one
+	want := []TxFinalityStage{TxStageSequenced, TxStageDAPosted, TxStageStateCommitted, TxStageFinalized}
+	if !reflect.DeepEqual(stages, want) {
+		t.Fatalf("have stages %v, want %v", stages, want)
+	}
+}
+
+func TestWatchUnknownTransactionStatus(t *testing.T) {
+	b := &txStatusBackend{unknown: true}
+	done := make(chan struct{})
+	go func() {
+		NewPublicRollupAPI(b).watchTransactionStatus(common.Hash{}, time.Millisecond, func(*RPCTransactionStatus) {
+			t.Error("unexpected notification")
+		}, nil, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not give up on an unknown transaction")
+	}
+	if b.calls != txStatusUnknownPolls {
+		t.Fatalf("polled %d times, want %d", b.calls, txStatusUnknownPolls)
+	}
+}
+
+func TestTransactionStatusSubscriptionLimit(t *testing.T) {
+	api := NewPublicRollupAPI(&txStatusBackend{})
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("rollup", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	api.txStatusSubs = maxTxStatusSubscriptions
+	ch := make(chan *RPCTransactionStatus)
+	if _, err := client.Subscribe(context.Background(), "rollup", ch, "transactionStatus", common.Hash{}); err == nil {
+		t.Fatal("subscription over the limit should fail")
+	}
+
+	api.txStatusSubs = 0
+	sub, err := client.Subscribe(context.Background(), "rollup", ch, "transactionStatus", common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	if status := <-ch; status.Stage != TxStageSequenced {
+		t.Fatalf("unexpected stage %v", status.Stage)
+	}
+}
@@ -61,6 +61,18 @@ func (b *LesApiBackend) GetRollupContext() (uint64, uint64, uint64) {
 	return 0, 0, 0
 }
 
+func (b *LesApiBackend) GetBatchContext() (*uint64, *uint64) {
+	return nil, nil
+}
+
+func (b *LesApiBackend) GetFeeOracleContext() (*big.Int, *big.Float, *big.Int, *big.Int) {
+	return nil, nil, nil, nil
+}
+
+func (b *LesApiBackend) GetL1SyncLag() (uint64, error) {
+	return 0, errors.New("GetL1SyncLag is not implemented")
+}
+
 func (b *LesApiBackend) IsSyncing() bool {
 	return false
 }
@@ -309,6 +321,22 @@ func (b *LesApiBackend) SetL2GasPrice(ctx context.Context, gasPrice *big.Int) er
 	panic("SetExecutionPrice is not implemented")
 }
 
+// NB: Light clients do not sequence and cannot roll back.
+func (b *LesApiBackend) SequencerRollback(ctx context.Context, target uint64, reason string) (*types.RollbackJournal, error) {
+	return nil, errors.New("SequencerRollback is not implemented")
+}
+
+func (b *LesApiBackend) GetRollbackJournals() []*types.RollbackJournal {
+	return nil
+}
+
+func (b *LesApiBackend) SubscribeRollbackEvent(ch chan<- core.RollbackEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
 func (b *LesApiBackend) ChainDb() ethdb.Database {
 	return b.eth.chainDb
 }
@@ -78,6 +78,9 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 	if _, isCompat := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !isCompat {
 		return nil, genesisErr
 	}
+	if config.OverrideMantleUpgrades != nil {
+		chainConfig.MantleUpgrades = chainConfig.MantleUpgrades.Merge(config.OverrideMantleUpgrades)
+	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
 	peers := newPeerSet()
@@ -223,14 +223,14 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(108), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(108), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil, nil}
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(420), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}}
-	TestChainConfig          = &ChainConfig{big.NewInt(1), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(420), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil}
+	TestChainConfig          = &ChainConfig{big.NewInt(1), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(EthashConfig), nil, nil}
 	TestRules                = TestChainConfig.Rules(new(big.Int))
 
 	// OpMainnetChainID is the ID of Mantle's mainnet chain.
@@ -320,6 +320,48 @@ type ChainConfig struct {
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
+
+	// MantleUpgrades overrides the bundled mantle upgrade heights of the chain
+	MantleUpgrades *MantleUpgradesConfig `json:"mantleUpgrades,omitempty"`
+}
+
+// MantleUpgradesConfig is the activation schedule of the mantle upgrades. A nil
+// height keeps the height bundled for the chain, a height of zero disables the
+// upgrade.
+type MantleUpgradesConfig struct {
+	TssRewardL2Block      *big.Int `json:"tssRewardL2Block,omitempty"`      // TssReward contract code upgrade, run once at the layer2 block
+	MantleTokenL2Block    *big.Int `json:"mantleTokenL2Block,omitempty"`    // Mantle token name and symbol upgrade, run once at the layer2 block
+	UpdateGasLimitL2Block *big.Int `json:"updateGasLimitL2Block,omitempty"` // Block gas limit switch from the layer2 block on
+	EigenDaL2Block        *big.Int `json:"eigenDaL2Block,omitempty"`        // GasPriceOracle code upgrade, run once at the layer2 block
+	MockUpgradeL1Block    *big.Int `json:"mockUpgradeL1Block,omitempty"`    // Mock upgrade, run once from the layer1 block on
+}
+
+// Merge returns a copy of the schedule with the heights set in o taking
+// precedence, either side may be nil.
+func (c *MantleUpgradesConfig) Merge(o *MantleUpgradesConfig) *MantleUpgradesConfig {
+	merged := new(MantleUpgradesConfig)
+	if c != nil {
+		*merged = *c
+	}
+	if o == nil {
+		return merged
+	}
+	if o.TssRewardL2Block != nil {
+		merged.TssRewardL2Block = o.TssRewardL2Block
+	}
+	if o.MantleTokenL2Block != nil {
+		merged.MantleTokenL2Block = o.MantleTokenL2Block
+	}
+	if o.UpdateGasLimitL2Block != nil {
+		merged.UpdateGasLimitL2Block = o.UpdateGasLimitL2Block
+	}
+	if o.EigenDaL2Block != nil {
+		merged.EigenDaL2Block = o.EigenDaL2Block
+	}
+	if o.MockUpgradeL1Block != nil {
+		merged.MockUpgradeL1Block = o.MockUpgradeL1Block
+	}
+	return merged
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
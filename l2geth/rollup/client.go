@@ -542,13 +542,15 @@ func (c *Client) GetTxStatusResponse(index uint64, backend Backend) (*types.TxSt
 	if err != nil {
 		return nil, fmt.Errorf("cannot fetch transaction: %w", err)
 	}
+	if response.IsError() {
+		return nil, fmt.Errorf("cannot fetch tx status with index %d: %s", index, response.Status())
+	}
 	res, ok := response.Result().(*types.TxStatusResponse)
 	if !ok {
 		return nil, fmt.Errorf("could not get tx with index %d", index)
 	}
-	if res.StateRoot == nil {
-		return nil, errElementNotFound
-	}
+	// The state root is nil until the transaction is committed on layer1,
+	// the datastore is set on its own once the batch is posted to eigenda
 	return res, nil
 }
 
@@ -2,7 +2,6 @@ package eigenda
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	gresty "github.com/go-resty/resty/v2"
 	common2 "github.com/mantlenetworkio/mantle/l2geth/common"
@@ -81,23 +80,19 @@ func (c *Client) GetBatchTransactionByDataStoreId(storeNumber uint32, l1MsgSende
 	}
 	if response.StatusCode() == 200 {
 		var retTxList []*types.Transaction
-		batchTxn := new([]BatchTx)
-		batchRlpStream := l2rlp.NewStream(bytes.NewBuffer(TxListBuf), 0)
-		err = batchRlpStream.Decode(batchTxn)
+		version, newBatchTxn, err := DecodeBatch(TxListBuf)
 		if err != nil {
 			return nil, fmt.Errorf("decode batch tx fail: %w", err)
 		}
-		newBatchTxn := *batchTxn
 		for i := 0; i < len(newBatchTxn); i++ {
 			var l2Tx types.Transaction
 			rlpStream := l2rlp.NewStream(bytes.NewBuffer(newBatchTxn[i].RawTx), 0)
 			if err := l2Tx.DecodeRLP(rlpStream); err != nil {
 				log.Error("Decode RLP fail")
 			}
-			txDecodeMetaData := new(TransactionMeta)
-			err := json.Unmarshal(newBatchTxn[i].TxMeta, txDecodeMetaData)
+			txDecodeMetaData, err := DecodeTxMeta(version, newBatchTxn[i].TxMeta, newBatchTxn[i].RawTx)
 			if err != nil {
-				log.Error("Unmarshal json fail")
+				return nil, fmt.Errorf("decode tx meta fail: %w", err)
 			}
 			var queueOrigin types.QueueOrigin
 			var l1MessageSender *common2.Address
@@ -0,0 +1,354 @@
+package eigenda
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/core/types"
+	l2rlp "github.com/mantlenetworkio/mantle/l2geth/rlp"
+)
+
+// Batch versions. Legacy batches have no version byte, they are a bare rlp
+// list of BatchTx with json encoded TxMeta and always start with an rlp list
+// prefix (>= 0xc0), so any smaller leading byte is a version.
+const (
+	BatchVersionLegacy byte = 0x00
+	BatchVersionV1     byte = 0x01
+)
+
+// batchHeaderLen is version(1) + compression(1) + payload length(4)
+const batchHeaderLen = 6
+
+// maxPayloadSize bounds the size of a decompressed batch payload, so a small
+// compressed batch cannot exhaust the memory of the nodes decoding it
+var maxPayloadSize = 64 << 20
+
+type Compression byte
+
+const (
+	CompressionNone Compression = iota
+	CompressionZlib
+	CompressionZstd
+)
+
+var compressionNames = map[Compression]string{
+	CompressionNone: "none",
+	CompressionZlib: "zlib",
+	CompressionZstd: "zstd",
+}
+
+func (c Compression) String() string {
+	if name, ok := compressionNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", byte(c))
+}
+
+// ParseCompression parses the name of a compression algorithm
+func ParseCompression(name string) (Compression, error) {
+	for c, n := range compressionNames {
+		if n == name {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown batch compression %q", name)
+}
+
+var (
+	errEmptyBatch        = errors.New("empty batch")
+	errTruncatedBatch    = errors.New("truncated batch")
+	errTruncatedTxMeta   = errors.New("truncated tx meta")
+	errUnsupportedFormat = errors.New("unsupported batch version")
+	errPayloadTooLarge   = errors.New("decompressed batch payload too large")
+)
+
+// BatchHeader describes the framing of a batch retrieved from eigen da
+type BatchHeader struct {
+	Version     byte
+	Compression Compression
+	// PayloadOffset and PayloadLen locate the, possibly compressed, rlp
+	// encoded []BatchTx inside the retrieved data
+	PayloadOffset int
+	PayloadLen    int
+}
+
+// ParseBatchHeader reads the framing of a batch. Legacy batches are reported
+// as an uncompressed payload spanning the whole data, padding included.
+func ParseBatchHeader(data []byte) (*BatchHeader, error) {
+	if len(data) == 0 {
+		return nil, errEmptyBatch
+	}
+	if data[0] >= 0xc0 {
+		return &BatchHeader{
+			Version:       BatchVersionLegacy,
+			Compression:   CompressionNone,
+			PayloadOffset: 0,
+			PayloadLen:    len(data),
+		}, nil
+	}
+	if data[0] != BatchVersionV1 {
+		return nil, fmt.Errorf("%w: %d", errUnsupportedFormat, data[0])
+	}
+	if len(data) < batchHeaderLen {
+		return nil, errTruncatedBatch
+	}
+	compression := Compression(data[1])
+	if _, ok := compressionNames[compression]; !ok {
+		return nil, fmt.Errorf("unknown batch compression %d", data[1])
+	}
+	payloadLen := int(binary.BigEndian.Uint32(data[2:batchHeaderLen]))
+	if len(data)-batchHeaderLen < payloadLen {
+		return nil, errTruncatedBatch
+	}
+	return &BatchHeader{
+		Version:       data[0],
+		Compression:   compression,
+		PayloadOffset: batchHeaderLen,
+		PayloadLen:    payloadLen,
+	}, nil
+}
+
+// Payload returns the decompressed rlp encoded []BatchTx
+func (h *BatchHeader) Payload(data []byte) ([]byte, error) {
+	payload := data[h.PayloadOffset : h.PayloadOffset+h.PayloadLen]
+	switch h.Compression {
+	case CompressionNone:
+		return payload, nil
+	case CompressionZlib:
+		r, err := zlib.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return readPayload(r)
+	case CompressionZstd:
+		r, err := zstd.NewReader(bytes.NewReader(payload), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return readPayload(r)
+	default:
+		return nil, fmt.Errorf("unknown batch compression %d", h.Compression)
+	}
+}
+
+// readPayload reads a decompressed payload of at most maxPayloadSize bytes
+func readPayload(r io.Reader) ([]byte, error) {
+	payload, err := io.ReadAll(io.LimitReader(r, int64(maxPayloadSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) > maxPayloadSize {
+		return nil, errPayloadTooLarge
+	}
+	return payload, nil
+}
+
+// EncodeBatch rlp encodes the batch txs, compresses them and prefixes the
+// result with a v1 batch header. TxMeta is expected to be encoded with
+// EncodeTxMeta.
+func EncodeBatch(batchTxs []BatchTx, compression Compression) ([]byte, error) {
+	payload, err := l2rlp.EncodeToBytes(batchTxs)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	switch compression {
+	case CompressionNone:
+		buf.Write(payload)
+	case CompressionZlib:
+		w, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	case CompressionZstd:
+		w, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(w.EncodeAll(payload, nil))
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown batch compression %d", compression)
+	}
+	data := make([]byte, batchHeaderLen, batchHeaderLen+buf.Len())
+	data[0] = BatchVersionV1
+	data[1] = byte(compression)
+	binary.BigEndian.PutUint32(data[2:batchHeaderLen], uint32(buf.Len()))
+	return append(data, buf.Bytes()...), nil
+}
+
+// DecodeBatch decodes a legacy or versioned batch, the returned version must
+// be passed to DecodeTxMeta
+func DecodeBatch(data []byte) (byte, []BatchTx, error) {
+	header, err := ParseBatchHeader(data)
+	if err != nil {
+		return 0, nil, err
+	}
+	payload, err := header.Payload(data)
+	if err != nil {
+		return 0, nil, fmt.Errorf("decompress batch: %w", err)
+	}
+	var batchTxs []BatchTx
+	stream := l2rlp.NewStream(bytes.NewReader(payload), uint64(len(payload)))
+	if err := stream.Decode(&batchTxs); err != nil {
+		return 0, nil, fmt.Errorf("decode batch tx: %w", err)
+	}
+	return header.Version, batchTxs, nil
+}
+
+// Compact tx meta flags, set when the matching optional field is present
+const (
+	txMetaL1BlockNumber byte = 1 << iota
+	txMetaL1MessageSender
+	txMetaIndex
+	txMetaQueueIndex
+)
+
+// EncodeTxMeta encodes the tx meta in the compact binary format used by v1
+// batches:
+//
+//	flags | [l1BlockNumber] | l1Timestamp | [l1MessageSender] | [index] | [queueIndex]
+//
+// where integers are uvarints and bracketed fields are only present when the
+// matching flag is set. RawTransaction is left out, it is rebuilt from the
+// RawTx of the BatchTx on decode.
+func EncodeTxMeta(meta *TransactionMeta) ([]byte, error) {
+	var flags byte
+	buf := make([]byte, 1, 64)
+	if meta.L1BlockNumber != nil {
+		if !meta.L1BlockNumber.IsUint64() {
+			return nil, fmt.Errorf("l1 block number %v out of range", meta.L1BlockNumber)
+		}
+		flags |= txMetaL1BlockNumber
+		buf = appendUvarint(buf, meta.L1BlockNumber.Uint64())
+	}
+	buf = appendUvarint(buf, meta.L1Timestamp)
+	if meta.L1MessageSender != nil {
+		flags |= txMetaL1MessageSender
+		buf = append(buf, meta.L1MessageSender.Bytes()...)
+	}
+	if meta.Index != nil {
+		flags |= txMetaIndex
+		buf = appendUvarint(buf, *meta.Index)
+	}
+	if meta.QueueIndex != nil {
+		flags |= txMetaQueueIndex
+		buf = appendUvarint(buf, *meta.QueueIndex)
+	}
+	buf[0] = flags
+	return buf, nil
+}
+
+// DecodeTxMeta decodes the TxMeta of a BatchTx of the given batch version,
+// rawTx is the RawTx of the same BatchTx
+func DecodeTxMeta(version byte, data, rawTx []byte) (*TransactionMeta, error) {
+	switch version {
+	case BatchVersionLegacy:
+		meta := new(TransactionMeta)
+		if err := json.Unmarshal(data, meta); err != nil {
+			return nil, err
+		}
+		return meta, nil
+	case BatchVersionV1:
+		meta, err := decodeCompactTxMeta(data)
+		if err != nil {
+			return nil, err
+		}
+		if meta.RawTransaction, err = rawTransaction(meta, rawTx); err != nil {
+			return nil, err
+		}
+		return meta, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnsupportedFormat, version)
+	}
+}
+
+func decodeCompactTxMeta(data []byte) (*TransactionMeta, error) {
+	r := bytes.NewReader(data)
+	flags, err := r.ReadByte()
+	if err != nil {
+		return nil, errTruncatedTxMeta
+	}
+	readUint := func() (uint64, error) {
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return 0, errTruncatedTxMeta
+		}
+		return v, nil
+	}
+	meta := new(TransactionMeta)
+	if flags&txMetaL1BlockNumber != 0 {
+		v, err := readUint()
+		if err != nil {
+			return nil, err
+		}
+		meta.L1BlockNumber = new(big.Int).SetUint64(v)
+	}
+	if meta.L1Timestamp, err = readUint(); err != nil {
+		return nil, err
+	}
+	if flags&txMetaL1MessageSender != 0 {
+		var sender common.Address
+		if _, err := io.ReadFull(r, sender[:]); err != nil {
+			return nil, errTruncatedTxMeta
+		}
+		meta.L1MessageSender = &sender
+	}
+	if flags&txMetaIndex != 0 {
+		v, err := readUint()
+		if err != nil {
+			return nil, err
+		}
+		meta.Index = &v
+	}
+	if flags&txMetaQueueIndex != 0 {
+		v, err := readUint()
+		if err != nil {
+			return nil, err
+		}
+		meta.QueueIndex = &v
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("unexpected trailing bytes in tx meta")
+	}
+	return meta, nil
+}
+
+// rawTransaction rebuilds the RawTransaction of a tx meta from the rlp
+// encoded tx, it is the calldata of enqueued txs and the encoded tx itself
+// for sequencer txs
+func rawTransaction(meta *TransactionMeta, rawTx []byte) ([]byte, error) {
+	if meta.QueueIndex == nil {
+		return common.CopyBytes(rawTx), nil
+	}
+	var tx types.Transaction
+	if err := l2rlp.DecodeBytes(rawTx, &tx); err != nil {
+		return nil, fmt.Errorf("decode enqueued tx: %w", err)
+	}
+	return tx.Data(), nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
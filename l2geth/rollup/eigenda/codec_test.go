@@ -0,0 +1,176 @@
+package eigenda
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/core/types"
+	l2rlp "github.com/mantlenetworkio/mantle/l2geth/rlp"
+)
+
+// testTxMetas returns tx metas with the RawTx of their BatchTx
+func testTxMetas() ([]*TransactionMeta, [][]byte) {
+	index0, index1, queueIndex := uint64(0), uint64(1), uint64(7)
+	sender := common.HexToAddress("0x4200000000000000000000000000000000000007")
+	sequencerTx, _ := l2rlp.EncodeToBytes(types.NewTransaction(3, sender, big.NewInt(1), 21000, big.NewInt(1), []byte{0x01}))
+	calldata := bytes.Repeat([]byte{0xab}, 300)
+	enqueuedTx, _ := l2rlp.EncodeToBytes(types.NewTransaction(queueIndex, sender, big.NewInt(0), 100000, big.NewInt(0), calldata))
+	return []*TransactionMeta{
+		{
+			L1BlockNumber:  big.NewInt(16000000),
+			L1Timestamp:    1680000000,
+			Index:          &index0,
+			RawTransaction: sequencerTx,
+		},
+		{
+			L1BlockNumber:   big.NewInt(16000001),
+			L1Timestamp:     1680000012,
+			L1MessageSender: &sender,
+			Index:           &index1,
+			QueueIndex:      &queueIndex,
+			RawTransaction:  calldata,
+		},
+		{RawTransaction: []byte{}},
+	}, [][]byte{
+		sequencerTx,
+		enqueuedTx,
+		{},
+	}
+}
+
+func TestTxMetaRoundTrip(t *testing.T) {
+	metas, rawTxs := testTxMetas()
+	for i, meta := range metas {
+		encoded, err := EncodeTxMeta(meta)
+		if err != nil {
+			t.Fatalf("meta %d: cannot encode: %v", i, err)
+		}
+		if len(rawTxs[i]) > 0 && bytes.Contains(encoded, rawTxs[i]) {
+			t.Fatalf("meta %d: raw tx is stored in the tx meta", i)
+		}
+		decoded, err := DecodeTxMeta(BatchVersionV1, encoded, rawTxs[i])
+		if err != nil {
+			t.Fatalf("meta %d: cannot decode: %v", i, err)
+		}
+		if !reflect.DeepEqual(meta, decoded) {
+			t.Fatalf("meta %d: mismatch, got %+v want %+v", i, decoded, meta)
+		}
+		if _, err := DecodeTxMeta(BatchVersionV1, encoded[:len(encoded)-1], rawTxs[i]); err == nil {
+			t.Fatalf("meta %d: expected error decoding truncated meta", i)
+		}
+	}
+	// the calldata of an enqueued tx is rebuilt from its raw tx
+	encoded, _ := EncodeTxMeta(metas[1])
+	if _, err := DecodeTxMeta(BatchVersionV1, encoded, []byte{0x01}); err == nil {
+		t.Fatal("expected error decoding an invalid enqueued raw tx")
+	}
+}
+
+func TestBatchRoundTrip(t *testing.T) {
+	var batchTxs []BatchTx
+	metas, rawTxs := testTxMetas()
+	for i, meta := range metas {
+		encoded, err := EncodeTxMeta(meta)
+		if err != nil {
+			t.Fatalf("meta %d: cannot encode: %v", i, err)
+		}
+		batchTxs = append(batchTxs, BatchTx{
+			BlockNumber: big.NewInt(int64(100 + i)).Bytes(),
+			TxMeta:      encoded,
+			RawTx:       rawTxs[i],
+		})
+	}
+	for _, compression := range []Compression{CompressionNone, CompressionZlib, CompressionZstd} {
+		data, err := EncodeBatch(batchTxs, compression)
+		if err != nil {
+			t.Fatalf("%s: cannot encode batch: %v", compression, err)
+		}
+		// eigen da batches are padded to a minimum size
+		data = append(data, make([]byte, 62)...)
+		version, decoded, err := DecodeBatch(data)
+		if err != nil {
+			t.Fatalf("%s: cannot decode batch: %v", compression, err)
+		}
+		if version != BatchVersionV1 {
+			t.Fatalf("%s: unexpected version %d", compression, version)
+		}
+		if !reflect.DeepEqual(batchTxs, decoded) {
+			t.Fatalf("%s: batch mismatch", compression)
+		}
+	}
+}
+
+func TestDecodeLegacyBatch(t *testing.T) {
+	var batchTxs []BatchTx
+	metas, _ := testTxMetas()
+	for i, meta := range metas {
+		encoded, err := json.Marshal(meta)
+		if err != nil {
+			t.Fatalf("meta %d: cannot marshal: %v", i, err)
+		}
+		batchTxs = append(batchTxs, BatchTx{
+			BlockNumber: big.NewInt(int64(100 + i)).Bytes(),
+			TxMeta:      encoded,
+			RawTx:       []byte{byte(i)},
+		})
+	}
+	data, err := l2rlp.EncodeToBytes(batchTxs)
+	if err != nil {
+		t.Fatalf("cannot encode legacy batch: %v", err)
+	}
+	data = append(data, make([]byte, 62)...)
+	version, decoded, err := DecodeBatch(data)
+	if err != nil {
+		t.Fatalf("cannot decode legacy batch: %v", err)
+	}
+	if version != BatchVersionLegacy {
+		t.Fatalf("unexpected version %d", version)
+	}
+	if !reflect.DeepEqual(batchTxs, decoded) {
+		t.Fatal("legacy batch mismatch")
+	}
+	meta, err := DecodeTxMeta(version, decoded[1].TxMeta, decoded[1].RawTx)
+	if err != nil {
+		t.Fatalf("cannot decode legacy tx meta: %v", err)
+	}
+	if *meta.QueueIndex != 7 || meta.L1Timestamp != 1680000012 {
+		t.Fatalf("unexpected legacy tx meta %+v", meta)
+	}
+}
+
+func TestParseBatchHeaderErrors(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"empty":       {},
+		"version":     {0x7f, 0, 0, 0, 0, 0},
+		"compression": {BatchVersionV1, 9, 0, 0, 0, 0},
+		"truncated":   {BatchVersionV1, 0, 0, 0, 0, 10, 1},
+	} {
+		if _, err := ParseBatchHeader(data); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDecodeBatchPayloadTooLarge(t *testing.T) {
+	defer func(size int) { maxPayloadSize = size }(maxPayloadSize)
+	maxPayloadSize = 256
+
+	batchTxs := []BatchTx{{BlockNumber: []byte{1}, TxMeta: bytes.Repeat([]byte{0}, 1024)}}
+	for _, compression := range []Compression{CompressionZlib, CompressionZstd} {
+		data, err := EncodeBatch(batchTxs, compression)
+		if err != nil {
+			t.Fatalf("%v: cannot encode batch: %v", compression, err)
+		}
+		if len(data) > maxPayloadSize {
+			t.Fatalf("%v: compressed batch of %d bytes not below the limit", compression, len(data))
+		}
+		if _, _, err := DecodeBatch(data); !errors.Is(err, errPayloadTooLarge) {
+			t.Fatalf("%v: expected %v, got %v", compression, errPayloadTooLarge, err)
+		}
+	}
+}
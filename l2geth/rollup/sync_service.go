@@ -55,6 +55,7 @@ type SyncService struct {
 	db                             ethdb.Database
 	scope                          event.SubscriptionScope
 	txFeed                         event.Feed
+	rollbackFeed                   event.Feed
 	txLock                         sync.Mutex
 	loopLock                       sync.Mutex
 	enable                         bool
@@ -179,6 +180,15 @@ func NewSyncService(ctx context.Context, cfg Config, txpool *core.TxPool, bc *co
 	// of additional transactions by the SyncService.
 	service.chainHeadSub = service.bc.SubscribeChainHeadEvent(service.chainHeadCh)
 
+	// A pending rollback journal means the node stopped in the middle of a
+	// sequencer rollback, the chain may be left partially rewound
+	if journals := rawdb.ReadRollbackJournals(db); len(journals) != 0 {
+		if last := journals[len(journals)-1]; last.Status == types.RollbackPending {
+			log.Error("Unfinished sequencer rollback", "id", last.Id, "target", last.Target,
+				"old-head", last.OldHead, "old-head-hash", last.OldHeadHash.Hex(), "current", bc.CurrentBlock().NumberU64())
+		}
+	}
+
 	// Initial sync service setup if it is enabled. This code depends on
 	// a remote server that indexes the layer one contracts. Place this
 	// code behind this if statement so that this can run without the
@@ -740,6 +750,20 @@ func (s *SyncService) updateL1BlockNumber() error {
 	return nil
 }
 
+// L1SyncLag returns how many L1 blocks the data transport layer has seen
+// that are not yet reflected in the execution context
+func (s *SyncService) L1SyncLag() (uint64, error) {
+	context, err := s.client.GetLatestEthContext()
+	if err != nil {
+		return 0, fmt.Errorf("Cannot get eth context: %w", err)
+	}
+	latest := s.GetLatestL1BlockNumber()
+	if context.BlockNumber <= latest {
+		return 0, nil
+	}
+	return context.BlockNumber - latest, nil
+}
+
 // Methods for safely accessing and storing the latest
 // L1 blocknumber and timestamp. These are held in memory.
 
@@ -941,10 +965,209 @@ func (s *SyncService) SchedulerRollback(start uint64) error {
 	return nil
 }
 
-func (s *SyncService) SequencerRollback() error {
+// SequencerRollback rewinds the sequencer to the target L2 height. It is used
+// when an enqueue the chain was built on is reorged out of L1 or a rollback of
+// the state batches was approved. The enqueue transactions that L1 still knows
+// about and the sequencer transactions that still apply are replayed on top of
+// the target, the sequencer transactions that no longer apply are requeued
+// into the txpool. The rollback is journaled before the chain is rewound and
+// announced to subscribers once it is done. If the replay fails the rewound
+// blocks are restored, so the chain is either fully rolled back or untouched.
+func (s *SyncService) SequencerRollback(target uint64, reason string) (*types.RollbackJournal, error) {
+	if s.verifier {
+		return nil, errors.New("Verifier cannot roll back the sequencer")
+	}
+	// Same lock order as the sequencer loop, the rollback must not race
+	// with incoming sequencer transactions or the queue sync
+	s.txLock.Lock()
+	defer s.txLock.Unlock()
+	s.loopLock.Lock()
+	defer s.loopLock.Unlock()
+	s.applyLock.Lock()
+	defer s.applyLock.Unlock()
+
+	oldHead := s.bc.CurrentBlock()
+	latest := oldHead.Number().Uint64()
+	if target == 0 || target >= latest {
+		return nil, fmt.Errorf("invalid rollback target:%v,currentBlock number:%v", target, latest)
+	}
+	journal := &types.RollbackJournal{
+		Status:      types.RollbackPending,
+		Target:      target,
+		Reason:      reason,
+		OldHead:     latest,
+		OldHeadHash: oldHead.Hash(),
+		Timestamp:   uint64(time.Now().Unix()),
+	}
+
+	// Resolve the enqueue transactions against L1 before touching the chain
+	var oldBlocks types.Blocks
+	var replay []*types.Transaction
+	var firstQueueIndex *uint64
+	for i := target + 1; i <= latest; i++ {
+		block := s.bc.GetBlockByNumber(i)
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", i)
+		}
+		oldBlocks = append(oldBlocks, block)
+		for _, tx := range block.Transactions() {
+			if tx.QueueOrigin() != types.QueueOriginL1ToL2 {
+				replay = append(replay, tx)
+				continue
+			}
+			queueIndex := tx.GetMeta().QueueIndex
+			if queueIndex == nil {
+				return nil, fmt.Errorf("no queue index found in enqueue transaction %s", tx.Hash().Hex())
+			}
+			if firstQueueIndex == nil {
+				firstQueueIndex = queueIndex
+			}
+			enqueueTx, err := s.client.GetEnqueue(*queueIndex)
+			if errors.Is(err, errElementNotFound) {
+				log.Info("Dropping reorged enqueue", "queue-index", *queueIndex, "hash", tx.Hash().Hex())
+				journal.Dropped = append(journal.Dropped, tx.Hash())
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if tx.Hash() != enqueueTx.Hash() {
+				log.Info("Enqueue replaced on L1", "queue-index", *queueIndex, "new", enqueueTx.Hash().Hex(), "old", tx.Hash().Hex())
+				journal.Dropped = append(journal.Dropped, tx.Hash())
+			}
+			replay = append(replay, enqueueTx)
+		}
+	}
+
+	log.Info("Sequencer rollback start", "target", target, "currentBlockNumber", latest, "reason", reason)
+	rawdb.WriteRollbackJournal(s.db, journal)
+	restore := s.rollbackCheckpoint()
+	requeue, err := s.replayRollback(target, firstQueueIndex, replay, journal)
+	if err != nil {
+		log.Error("Sequencer rollback failed, restoring the rewound blocks", "id", journal.Id, "error", err)
+		if restoreErr := s.restoreRollback(target, oldBlocks, restore); restoreErr != nil {
+			return nil, fmt.Errorf("cannot restore rewound blocks: %v, rollback: %w", restoreErr, err)
+		}
+		journal.Status = types.RollbackReverted
+		journal.Replayed = nil
+		rawdb.UpdateRollbackJournal(s.db, journal)
+		return nil, err
+	}
+
+	for _, tx := range requeue {
+		tx.GetMeta().Index = nil
+	}
+	// The pool still holds the state of the old head
+	s.txpool.ResetHead()
+	for i, err := range s.txpool.AddLocals(requeue) {
+		if err != nil {
+			log.Warn("Cannot requeue evicted transaction", "hash", requeue[i].Hash().Hex(), "error", err)
+			journal.Dropped = append(journal.Dropped, requeue[i].Hash())
+			continue
+		}
+		journal.Requeued = append(journal.Requeued, requeue[i].Hash())
+	}
+
+	newHead := s.bc.CurrentBlock()
+	journal.Status = types.RollbackDone
+	journal.NewHead = newHead.Number().Uint64()
+	journal.NewHeadHash = newHead.Hash()
+	rawdb.UpdateRollbackJournal(s.db, journal)
+	s.rollbackFeed.Send(core.RollbackEvent{Journal: journal})
+	log.Info("Sequencer rollback end", "id", journal.Id, "currentBlockNumber", journal.NewHead,
+		"replayed", len(journal.Replayed), "dropped", len(journal.Dropped), "requeued", len(journal.Requeued))
+	return journal, nil
+}
+
+// replayRollback rewinds the chain to the target and replays the transactions
+// on top of it. It returns the sequencer transactions that no longer apply.
+func (s *SyncService) replayRollback(target uint64, firstQueueIndex *uint64, replay []*types.Transaction, journal *types.RollbackJournal) ([]*types.Transaction, error) {
+	if err := s.SetHead(target); err != nil {
+		return nil, fmt.Errorf("cannot rewind to %d: %w", target, err)
+	}
+	if firstQueueIndex != nil {
+		if *firstQueueIndex == 0 {
+			rawdb.DeleteHeadQueueIndex(s.db)
+		} else {
+			queueIndex := *firstQueueIndex - 1
+			s.SetLatestEnqueueIndex(&queueIndex)
+		}
+	}
+
+	var requeue []*types.Transaction
+	for _, tx := range replay {
+		tx.SetIndex(s.GetNextIndex())
+		if tx.QueueOrigin() == types.QueueOriginSequencer {
+			err := s.verifyFee(tx)
+			if err == nil {
+				err = s.verifyNonce(tx)
+			}
+			if err != nil {
+				log.Info("Evicting sequencer transaction", "hash", tx.Hash().Hex(), "error", err)
+				requeue = append(requeue, tx)
+				continue
+			}
+		}
+		if err := s.applyIndexedTransaction(tx); err != nil {
+			if tx.QueueOrigin() == types.QueueOriginL1ToL2 {
+				return nil, fmt.Errorf("cannot replay enqueue %d: %w", *tx.GetMeta().QueueIndex, err)
+			}
+			log.Info("Evicting sequencer transaction", "hash", tx.Hash().Hex(), "error", err)
+			requeue = append(requeue, tx)
+			continue
+		}
+		journal.Replayed = append(journal.Replayed, tx.Hash())
+	}
+	return requeue, nil
+}
+
+// rollbackCheckpoint holds the sync state to return to when a rollback fails
+type rollbackCheckpoint struct {
+	index         *uint64
+	queueIndex    *uint64
+	l1Timestamp   uint64
+	l1BlockNumber uint64
+}
+
+func (s *SyncService) rollbackCheckpoint() *rollbackCheckpoint {
+	return &rollbackCheckpoint{
+		index:         s.GetLatestIndex(),
+		queueIndex:    s.GetLatestEnqueueIndex(),
+		l1Timestamp:   s.GetLatestL1Timestamp(),
+		l1BlockNumber: s.GetLatestL1BlockNumber(),
+	}
+}
+
+// restoreRollback rewinds the blocks replayed by a failed rollback and
+// reinserts the blocks that were rolled back
+func (s *SyncService) restoreRollback(target uint64, oldBlocks types.Blocks, checkpoint *rollbackCheckpoint) error {
+	if err := s.bc.SetHead(target); err != nil {
+		return err
+	}
+	if _, err := s.bc.InsertChain(oldBlocks); err != nil {
+		return err
+	}
+	// Inserting the blocks sent a chain head event nobody waits for
+	select {
+	case <-s.chainHeadCh:
+	default:
+	}
+	s.SetLatestIndex(checkpoint.index)
+	if checkpoint.queueIndex == nil {
+		rawdb.DeleteHeadQueueIndex(s.db)
+	} else {
+		s.SetLatestEnqueueIndex(checkpoint.queueIndex)
+	}
+	s.SetLatestL1Timestamp(checkpoint.l1Timestamp)
+	s.SetLatestL1BlockNumber(checkpoint.l1BlockNumber)
 	return nil
 }
 
+// RollbackJournals returns the journals of the sequencer rollbacks, oldest first
+func (s *SyncService) RollbackJournals() []*types.RollbackJournal {
+	return rawdb.ReadRollbackJournals(s.db)
+}
+
 // applyTransaction is a higher level API for applying a transaction
 func (s *SyncService) applyTransaction(tx *types.Transaction) error {
 	s.applyLock.Lock()
@@ -1185,6 +1408,25 @@ func (s *SyncService) applyBatchedTransaction(tx *types.Transaction) error {
 	return nil
 }
 
+// verifyNonce will verify that the nonce of a transaction is the next nonce
+// of its sender in the state of the current head.
+func (s *SyncService) verifyNonce(tx *types.Transaction) error {
+	state, err := s.bc.State()
+	if err != nil {
+		return err
+	}
+	from, err := types.Sender(s.signer, tx)
+	if err != nil {
+		return fmt.Errorf("invalid transaction: %w", core.ErrInvalidSender)
+	}
+	if nonce := state.GetNonce(from); nonce > tx.Nonce() {
+		return core.ErrNonceTooLow
+	} else if nonce < tx.Nonce() {
+		return core.ErrNonceTooHigh
+	}
+	return nil
+}
+
 // verifyFee will verify that a valid fee is being paid.
 func (s *SyncService) verifyFee(tx *types.Transaction) error {
 	fee, err := fees.CalculateTotalFee(tx, s.RollupGpo)
@@ -1550,6 +1792,12 @@ func (s *SyncService) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Sub
 	return s.scope.Track(s.txFeed.Subscribe(ch))
 }
 
+// SubscribeRollbackEvent registers a subscription of RollbackEvent, posted
+// after each sequencer rollback
+func (s *SyncService) SubscribeRollbackEvent(ch chan<- core.RollbackEvent) event.Subscription {
+	return s.scope.Track(s.rollbackFeed.Subscribe(ch))
+}
+
 func stringify(i *uint64) string {
 	if i == nil {
 		return "<nil>"
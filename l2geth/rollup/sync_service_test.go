@@ -2,12 +2,14 @@ package rollup
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"crypto/rand"
 	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -937,6 +939,258 @@ func newTestSyncServiceDeps(isVerifier bool, alloc *common.Address) (Config, *co
 	return cfg, txPool, chain, db, chaincfg, nil
 }
 
+func TestSequencerRollbackInvalidTarget(t *testing.T) {
+	verifier, _, _, err := newTestSyncService(true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.SequencerRollback(1, "test"); err == nil {
+		t.Fatal("Verifier should not roll back")
+	}
+
+	service, _, _, err := newTestSyncService(false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The chain only holds the genesis block, there is nothing to roll back
+	for _, target := range []uint64{0, 1} {
+		if _, err := service.SequencerRollback(target, "test"); err == nil {
+			t.Fatalf("Rollback to %d should fail", target)
+		}
+	}
+	if journals := service.RollbackJournals(); len(journals) != 0 {
+		t.Fatalf("Unexpected rollback journals: %d", len(journals))
+	}
+}
+
+// rollbackTestChain is a sequencer chain built by a stand in for the miner
+type rollbackTestChain struct {
+	service *SyncService
+	key     *ecdsa.PrivateKey
+	// failing makes the miner reject the enqueue transactions
+	failing int32
+	quit    chan struct{}
+}
+
+func newRollbackTestChain(t *testing.T) *rollbackTestChain {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	service, txCh, sub, err := newTestSyncService(false, &sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &rollbackTestChain{service: service, key: key, quit: make(chan struct{})}
+	t.Cleanup(func() {
+		close(c.quit)
+		sub.Unsubscribe()
+	})
+	go c.mine(txCh)
+	return c
+}
+
+// mine inserts a block for every transaction the sync service sends
+func (c *rollbackTestChain) mine(txCh chan core.NewTxsEvent) {
+	for {
+		select {
+		case event := <-txCh:
+			tx := event.Txs[0]
+			if atomic.LoadInt32(&c.failing) != 0 && tx.QueueOrigin() == types.QueueOriginL1ToL2 {
+				event.ErrCh <- errors.New("enqueue rejected")
+				continue
+			}
+			block, err := c.buildBlock(tx)
+			if err == nil {
+				_, err = c.service.bc.InsertChain(types.Blocks{block})
+			}
+			if err != nil {
+				event.ErrCh <- err
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+func (c *rollbackTestChain) buildBlock(tx *types.Transaction) (block *types.Block, err error) {
+	// The block generator panics on transactions that do not apply
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	bc := c.service.bc
+	blocks, _ := core.GenerateChain(bc.Config(), bc.CurrentBlock(), bc.Engine(), c.service.db, 1, func(i int, b *core.BlockGen) {
+		b.AddTxWithChain(bc, tx)
+	})
+	return blocks[0], nil
+}
+
+func (c *rollbackTestChain) enqueue(t *testing.T, queueIndex uint64) *types.Transaction {
+	sender := common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	tx := types.NewTransaction(0, common.HexToAddress("0x04668ec2f57cc15c381b461b9fedab5d451c8f7f"), big.NewInt(0), 100000, big.NewInt(0), nil)
+	tx.SetTransactionMeta(types.NewTransactionMeta(big.NewInt(1), 1, &sender, types.QueueOriginL1ToL2, nil, &queueIndex, nil))
+	c.apply(t, tx)
+	return tx
+}
+
+func (c *rollbackTestChain) sequence(t *testing.T, nonce uint64) *types.Transaction {
+	tx, err := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0x04668ec2f57cc15c381b461b9fedab5d451c8f7f"), big.NewInt(0), 21000, big.NewInt(0), nil), c.service.signer, c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.SetTransactionMeta(types.NewTransactionMeta(nil, 0, nil, types.QueueOriginSequencer, nil, nil, nil))
+	c.apply(t, tx)
+	return tx
+}
+
+// mockEnqueues sets the enqueues L1 returns, nil for the ones reorged out
+func (c *rollbackTestChain) mockEnqueues(txs ...*types.Transaction) {
+	setupMockClient(c.service, map[string]interface{}{
+		"GetEnqueue": txs,
+	})
+	c.service.RollupGpo.SetDASwitch(new(big.Int))
+}
+
+func (c *rollbackTestChain) apply(t *testing.T, tx *types.Transaction) {
+	if err := c.service.applyTransactionToTip(tx); err != nil {
+		t.Fatalf("Cannot apply transaction: %v", err)
+	}
+}
+
+func TestSequencerRollbackReplay(t *testing.T) {
+	c := newRollbackTestChain(t)
+	c.enqueue(t, 0)
+	s0 := c.sequence(t, 0)
+	q1 := c.enqueue(t, 1)
+	s1 := c.sequence(t, 1)
+	oldHead := c.service.bc.CurrentBlock()
+
+	// L1 still holds the enqueue, every transaction is replayed
+	c.mockEnqueues(q1)
+	rollbackCh := make(chan core.RollbackEvent, 1)
+	sub := c.service.SubscribeRollbackEvent(rollbackCh)
+	defer sub.Unsubscribe()
+
+	journal, err := c.service.SequencerRollback(1, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if journal.Status != types.RollbackDone || journal.NewHead != 4 || journal.NewHeadHash != oldHead.Hash() {
+		t.Fatalf("Unexpected rollback journal %+v", journal)
+	}
+	replayed := []common.Hash{s0.Hash(), q1.Hash(), s1.Hash()}
+	if !reflect.DeepEqual(journal.Replayed, replayed) || len(journal.Dropped) != 0 || len(journal.Requeued) != 0 {
+		t.Fatalf("Unexpected rollback journal %+v", journal)
+	}
+	if *c.service.GetLatestIndex() != 3 || *c.service.GetLatestEnqueueIndex() != 1 {
+		t.Fatal("Indices not replayed")
+	}
+	if event := <-rollbackCh; event.Journal != journal {
+		t.Fatal("Rollback event mismatch")
+	}
+	journals := c.service.RollbackJournals()
+	if len(journals) != 1 || journals[0].Status != types.RollbackDone || len(journals[0].Replayed) != 3 {
+		t.Fatalf("Unexpected rollback journals %+v", journals)
+	}
+}
+
+func TestSequencerRollbackRequeue(t *testing.T) {
+	c := newRollbackTestChain(t)
+	head := c.enqueue(t, 0)
+	s0 := c.sequence(t, 0)
+	q1 := c.enqueue(t, 1)
+	s1 := c.sequence(t, 1)
+	// Let the pool catch up with the chain, so the head events of the
+	// rewound blocks are not handled after the rollback
+	sender := crypto.PubkeyToAddress(c.key.PublicKey)
+	for c.service.txpool.Nonce(sender) != 2 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The enqueue was reorged out of L1 and the zero gas price sequencer
+	// transactions are no longer accepted
+	c.mockEnqueues(nil)
+	c.service.enforceFees = true
+
+	journal, err := c.service.SequencerRollback(1, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if journal.NewHead != 1 || len(journal.Replayed) != 0 {
+		t.Fatalf("Unexpected rollback journal %+v", journal)
+	}
+	// The pool only takes the next nonce of a sender, later ones are dropped
+	if !reflect.DeepEqual(journal.Dropped, []common.Hash{q1.Hash(), s1.Hash()}) {
+		t.Fatalf("Unexpected dropped transactions %v", journal.Dropped)
+	}
+	if !reflect.DeepEqual(journal.Requeued, []common.Hash{s0.Hash()}) {
+		t.Fatalf("Unexpected requeued transactions %v", journal.Requeued)
+	}
+	if c.service.txpool.Get(s0.Hash()) == nil {
+		t.Fatal("Transaction not requeued")
+	}
+	if *c.service.GetLatestIndex() != *head.GetMeta().Index || *c.service.GetLatestEnqueueIndex() != 0 {
+		t.Fatal("Indices not rolled back")
+	}
+}
+
+func TestSequencerRollbackFirstEnqueue(t *testing.T) {
+	c := newRollbackTestChain(t)
+	c.sequence(t, 0)
+	q0 := c.enqueue(t, 0)
+
+	c.mockEnqueues(nil)
+	journal, err := c.service.SequencerRollback(1, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(journal.Dropped, []common.Hash{q0.Hash()}) {
+		t.Fatalf("Unexpected dropped transactions %v", journal.Dropped)
+	}
+	// Every enqueue was rolled back, the queue is synced from the start
+	if index := c.service.GetLatestEnqueueIndex(); index != nil {
+		t.Fatalf("Unexpected latest enqueue index %d", *index)
+	}
+	if c.service.GetNextEnqueueIndex() != 0 {
+		t.Fatal("Next enqueue index not reset")
+	}
+}
+
+func TestSequencerRollbackRestore(t *testing.T) {
+	c := newRollbackTestChain(t)
+	c.enqueue(t, 0)
+	c.sequence(t, 0)
+	q1 := c.enqueue(t, 1)
+	c.sequence(t, 1)
+	oldHead := c.service.bc.CurrentBlock()
+	timestamp := c.service.GetLatestL1Timestamp()
+
+	c.mockEnqueues(q1)
+	atomic.StoreInt32(&c.failing, 1)
+	if _, err := c.service.SequencerRollback(1, "test"); err == nil {
+		t.Fatal("Rollback should fail when an enqueue cannot be replayed")
+	}
+	atomic.StoreInt32(&c.failing, 0)
+
+	// The rewound blocks are back and the sync state is restored
+	if head := c.service.bc.CurrentBlock(); head.Hash() != oldHead.Hash() {
+		t.Fatalf("Head not restored: have %d, want %d", head.NumberU64(), oldHead.NumberU64())
+	}
+	if *c.service.GetLatestIndex() != 3 || *c.service.GetLatestEnqueueIndex() != 1 || c.service.GetLatestL1Timestamp() != timestamp {
+		t.Fatal("Sync state not restored")
+	}
+	journals := c.service.RollbackJournals()
+	if len(journals) != 1 || journals[0].Status != types.RollbackReverted || len(journals[0].Replayed) != 0 {
+		t.Fatalf("Unexpected rollback journals %+v", journals)
+	}
+
+	// The sequencer keeps building on the restored chain
+	c.sequence(t, 2)
+	if head := c.service.bc.CurrentBlock(); head.NumberU64() != 5 || head.ParentHash() != oldHead.Hash() {
+		t.Fatal("Cannot extend the restored chain")
+	}
+}
+
 func newTestSyncService(isVerifier bool, alloc *common.Address) (*SyncService, chan core.NewTxsEvent, event.Subscription, error) {
 	cfg, txPool, chain, db, chainConfig, err := newTestSyncServiceDeps(isVerifier, alloc)
 	if err != nil {
@@ -1021,6 +1275,9 @@ func (m *mockClient) GetEnqueue(index uint64) (*types.Transaction, error) {
 	if m.getEnqueueCallCount < len(m.getEnqueue) {
 		tx := m.getEnqueue[m.getEnqueueCallCount]
 		m.getEnqueueCallCount++
+		if tx == nil {
+			return nil, errElementNotFound
+		}
 		return tx, nil
 	}
 	return nil, errors.New("")
@@ -38,6 +38,9 @@ type Config struct {
 	BlockOffset               uint64
 	RollUpMinTxn              uint64
 	RollUpMaxSize             uint64
+	BatchVersion              uint
+	BatchCompression          string
+	BlockFetchConcurrency     int
 	EigenLayerNode            int
 	EigenLogConfig            logging.Config
 	MetricsServerEnable       bool
@@ -54,8 +57,10 @@ type Config struct {
 	SafeAbortNonceTooLowCount uint64
 	EchoDebug                 bool
 	MtlBatcherEnable          bool
-	FeeSizeSec                string
 	FeePerBytePerTime         uint64
+	FeeWindowSize             int
+	FeeSmoothing              float64
+	FeeSignificanceFactor     float64
 	FeeModelEnable            bool
 	DisableHTTP2              bool
 	DbPath                    string
@@ -101,6 +106,9 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 		BlockOffset:               ctx.GlobalUint64(flags.BlockOffsetFlag.Name),
 		RollUpMinTxn:              ctx.GlobalUint64(flags.RollUpMinTxnFlag.Name),
 		RollUpMaxSize:             ctx.GlobalUint64(flags.RollUpMaxSizeFlag.Name),
+		BatchVersion:              ctx.GlobalUint(flags.BatchVersionFlag.Name),
+		BatchCompression:          ctx.GlobalString(flags.BatchCompressionFlag.Name),
+		BlockFetchConcurrency:     ctx.GlobalInt(flags.BlockFetchConcurrencyFlag.Name),
 		EigenLayerNode:            ctx.GlobalInt(flags.EigenLayerNodeFlag.Name),
 		EigenLogConfig:            logging.ReadCLIConfig(ctx),
 		RetrieverTimeout:          ctx.GlobalDuration(flags.RetrieverTimeoutFlag.Name),
@@ -115,8 +123,10 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 		SentryEnable:              ctx.GlobalBool(flags.SentryEnableFlag.Name),
 		SentryDsn:                 ctx.GlobalString(flags.SentryDsnFlag.Name),
 		SentryTraceRate:           ctx.GlobalDuration(flags.SentryTraceRateFlag.Name),
-		FeeSizeSec:                ctx.GlobalString(flags.FeeSizeSecFlag.Name),
 		FeePerBytePerTime:         ctx.GlobalUint64(flags.FeePerBytePerTimeFlag.Name),
+		FeeWindowSize:             ctx.GlobalInt(flags.FeeWindowSizeFlag.Name),
+		FeeSmoothing:              ctx.GlobalFloat64(flags.FeeSmoothingFlag.Name),
+		FeeSignificanceFactor:     ctx.GlobalFloat64(flags.FeeSignificanceFactorFlag.Name),
 		FeeModelEnable:            ctx.GlobalBool(flags.FeeModelEnableFlags.Name),
 		DisableHTTP2:              ctx.GlobalBool(flags.HTTP2DisableFlag.Name),
 		EchoDebug:                 ctx.GlobalBool(flags.EchoDebugFlag.Name),
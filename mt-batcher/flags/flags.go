@@ -131,24 +131,62 @@ var (
 		Value:  500,
 		EnvVar: prefixEnvVar(envVarPrefix, "ROLLUP_MIN_TXN"),
 	}
+	// FeeSizeSecFlag is deprecated and ignored. The da fee is now estimated
+	// in wei per byte from the cost of the recent batches, the flag is only
+	// kept so existing deployments still start.
 	FeeSizeSecFlag = cli.StringFlag{
 		Name:   "fee-size-sec",
-		Usage:  "Rollup transaction fee size",
-		Value:  "102400", //
+		Usage:  "Deprecated: ignored, the da fee is estimated over the recent batches",
 		EnvVar: prefixEnvVar(envVarPrefix, "FEE_SIZE_SEC"),
 	}
 	FeePerBytePerTimeFlag = cli.Uint64Flag{
 		Name:   "fee-per-byte-time",
-		Usage:  "Fee per byte time",
+		Usage:  "Eigen da cost in wei of storing a byte for one unit of store duration",
 		Value:  1,
 		EnvVar: prefixEnvVar(envVarPrefix, "FEE_PER_BYTE_TIME"),
 	}
+	FeeWindowSizeFlag = cli.IntFlag{
+		Name:   "fee-window-size",
+		Usage:  "Number of recent batches the da fee is estimated over",
+		Value:  20,
+		EnvVar: prefixEnvVar(envVarPrefix, "FEE_WINDOW_SIZE"),
+	}
+	FeeSmoothingFlag = cli.Float64Flag{
+		Name:   "fee-smoothing",
+		Usage:  "Weight of the newest da fee estimate in its moving average, 1 disables smoothing",
+		Value:  0.3,
+		EnvVar: prefixEnvVar(envVarPrefix, "FEE_SMOOTHING"),
+	}
+	FeeSignificanceFactorFlag = cli.Float64Flag{
+		Name:   "fee-significance-factor",
+		Usage:  "Relative change of the da fee estimate required to update the fee on chain",
+		Value:  0.05,
+		EnvVar: prefixEnvVar(envVarPrefix, "FEE_SIGNIFICANCE_FACTOR"),
+	}
 	RollUpMaxSizeFlag = cli.Uint64Flag{
 		Name:   "rollup-max-size",
 		Usage:  "Rollup transaction max size data for eigen da",
 		Value:  31600, // ktz for order is 3000
 		EnvVar: prefixEnvVar(envVarPrefix, "ROLLUP_MAX_SIZE"),
 	}
+	BatchVersionFlag = cli.UintFlag{
+		Name:   "batch-version",
+		Usage:  "Eigen da batch encoding version, 0 for legacy batches and 1 for compressed versioned batches",
+		Value:  0,
+		EnvVar: prefixEnvVar(envVarPrefix, "BATCH_VERSION"),
+	}
+	BatchCompressionFlag = cli.StringFlag{
+		Name:   "batch-compression",
+		Usage:  "Compression of versioned eigen da batches, one of none, zlib or zstd",
+		Value:  "zlib",
+		EnvVar: prefixEnvVar(envVarPrefix, "BATCH_COMPRESSION"),
+	}
+	BlockFetchConcurrencyFlag = cli.IntFlag{
+		Name:   "block-fetch-concurrency",
+		Usage:  "Max number of l2 blocks fetched concurrently while aggregating a batch",
+		Value:  8,
+		EnvVar: prefixEnvVar(envVarPrefix, "BLOCK_FETCH_CONCURRENCY"),
+	}
 	EigenLayerNodeFlag = cli.IntFlag{
 		Name:   "eigen-layer-node",
 		Usage:  "The offset between the CTC contract start and the L2 geth blocks",
@@ -381,7 +419,6 @@ var requiredFlags = []cli.Flag{
 	RollupTimeoutFlag,
 	RollUpMinTxnFlag,
 	RollUpMaxSizeFlag,
-	FeeSizeSecFlag,
 	FeePerBytePerTimeFlag,
 	MainWorkerPollIntervalFlag,
 	CheckerWorkerPollIntervalFlag,
@@ -416,6 +453,13 @@ var optionalFlags = []cli.Flag{
 	HsmCredenFlag,
 	HsmFeeAddressFlag,
 	HsmFeeAPINameFlag,
+	BatchVersionFlag,
+	BatchCompressionFlag,
+	BlockFetchConcurrencyFlag,
+	FeeWindowSizeFlag,
+	FeeSmoothingFlag,
+	FeeSignificanceFactorFlag,
+	FeeSizeSecFlag,
 }
 
 func init() {
@@ -32,4 +32,8 @@ type MtBatchMetrics interface {
 	FeeTimeDuration() prometheus.Gauge
 
 	CheckerTimeDuration() prometheus.Gauge
+
+	TxAggregatorErrors() *prometheus.CounterVec
+
+	TxAggregatorFailedBlock() prometheus.Gauge
 }
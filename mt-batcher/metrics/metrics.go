@@ -6,21 +6,23 @@ import (
 )
 
 type MtBatchBase struct {
-	mtBatchBalanceETH      prometheus.Gauge
-	mtFeeBalanceETH        prometheus.Gauge
-	batchSizeBytes         prometheus.Summary
-	numTxnPerBatch         prometheus.Summary
-	l2StoredBlockNumber    prometheus.Gauge
-	l2ConfirmedBlockNumber prometheus.Gauge
-	rollUpBatchIndex       prometheus.Gauge
-	reRollUpBatchIndex     prometheus.Gauge
-	eigenUserFee           prometheus.Gauge
-	mtFeeNonce             prometheus.Gauge
-	mtBatchNonce           prometheus.Gauge
-	numEigenNode           prometheus.Gauge
-	rollupTimeDuration     prometheus.Gauge
-	checkerTimeDuration    prometheus.Gauge
-	feeTimeDuration        prometheus.Gauge
+	mtBatchBalanceETH       prometheus.Gauge
+	mtFeeBalanceETH         prometheus.Gauge
+	batchSizeBytes          prometheus.Summary
+	numTxnPerBatch          prometheus.Summary
+	l2StoredBlockNumber     prometheus.Gauge
+	l2ConfirmedBlockNumber  prometheus.Gauge
+	rollUpBatchIndex        prometheus.Gauge
+	reRollUpBatchIndex      prometheus.Gauge
+	eigenUserFee            prometheus.Gauge
+	mtFeeNonce              prometheus.Gauge
+	mtBatchNonce            prometheus.Gauge
+	numEigenNode            prometheus.Gauge
+	rollupTimeDuration      prometheus.Gauge
+	checkerTimeDuration     prometheus.Gauge
+	feeTimeDuration         prometheus.Gauge
+	txAggregatorErrors      *prometheus.CounterVec
+	txAggregatorFailedBlock prometheus.Gauge
 }
 
 func NewMtBatchBase() *MtBatchBase {
@@ -110,6 +112,16 @@ func NewMtBatchBase() *MtBatchBase {
 			Help:      "time duration for fee submitter",
 			Subsystem: "mtbatcher",
 		}),
+		txAggregatorErrors: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "tx_aggregator_errors",
+			Help:      "Count of l2 block ranges that could not be aggregated into a batch",
+			Subsystem: "mtbatcher",
+		}, []string{"reason"}),
+		txAggregatorFailedBlock: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "tx_aggregator_failed_block",
+			Help:      "l2 block number the last aggregation failed at, 0 once a batch is aggregated",
+			Subsystem: "mtbatcher",
+		}),
 	}
 }
 
@@ -172,3 +184,11 @@ func (mbb *MtBatchBase) FeeTimeDuration() prometheus.Gauge {
 func (mbb *MtBatchBase) CheckerTimeDuration() prometheus.Gauge {
 	return mbb.checkerTimeDuration
 }
+
+func (mbb *MtBatchBase) TxAggregatorErrors() *prometheus.CounterVec {
+	return mbb.txAggregatorErrors
+}
+
+func (mbb *MtBatchBase) TxAggregatorFailedBlock() prometheus.Gauge {
+	return mbb.txAggregatorFailedBlock
+}
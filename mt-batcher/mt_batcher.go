@@ -3,6 +3,7 @@ package mt_batcher
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -11,7 +12,9 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/rollup/eigenda"
 	"github.com/mantlenetworkio/mantle/mt-batcher/bindings"
+	"github.com/mantlenetworkio/mantle/mt-batcher/flags"
 	"github.com/mantlenetworkio/mantle/mt-batcher/l1l2client"
 	"github.com/mantlenetworkio/mantle/mt-batcher/metrics"
 	common2 "github.com/mantlenetworkio/mantle/mt-batcher/services/common"
@@ -28,6 +31,9 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 		if err != nil {
 			return err
 		}
+		if cliCtx.GlobalIsSet(flags.FeeSizeSecFlag.Name) {
+			log.Warn("fee-size-sec is deprecated and ignored, the da fee is now estimated in wei per byte over the recent batches")
+		}
 
 		mantleBatch, err := NewMantleBatch(cfg)
 		if err != nil {
@@ -134,6 +140,14 @@ func NewMantleBatch(cfg Config) (*MantleBatch, error) {
 		l1Client,
 	)
 
+	if cfg.BatchVersion > uint(eigenda.BatchVersionV1) {
+		return nil, fmt.Errorf("unsupported batch version %d", cfg.BatchVersion)
+	}
+	batchCompression, err := eigenda.ParseCompression(cfg.BatchCompression)
+	if err != nil {
+		return nil, err
+	}
+
 	driverConfig := &sequencer.DriverConfig{
 		L1Client:                  l1Client,
 		L2Client:                  l2Client,
@@ -144,14 +158,19 @@ func NewMantleBatch(cfg Config) (*MantleBatch, error) {
 		EigenFeeContract:          eigenFeeContract,
 		RawEigenFeeContract:       rawEigenFeeContract,
 		FeeModelEnable:            cfg.FeeModelEnable,
-		FeeSizeSec:                cfg.FeeSizeSec,
 		FeePerBytePerTime:         cfg.FeePerBytePerTime,
+		FeeWindowSize:             cfg.FeeWindowSize,
+		FeeSmoothing:              cfg.FeeSmoothing,
+		FeeSignificanceFactor:     cfg.FeeSignificanceFactor,
 		Logger:                    logger,
 		PrivKey:                   sequencerPrivKey,
 		FeePrivKey:                mtFeePrivateKey,
 		BlockOffset:               cfg.BlockOffset,
 		RollUpMinTxn:              cfg.RollUpMinTxn,
 		RollUpMaxSize:             cfg.RollUpMaxSize,
+		BlockFetchConcurrency:     cfg.BlockFetchConcurrency,
+		BatchVersion:              byte(cfg.BatchVersion),
+		BatchCompression:          batchCompression,
 		EigenLayerNode:            cfg.EigenLayerNode,
 		DataStoreDuration:         uint64(cfg.DataStoreDuration),
 		DataStoreTimeout:          cfg.DataStoreTimeout,
@@ -195,6 +214,7 @@ func NewMantleBatch(cfg Config) (*MantleBatch, error) {
 		GraphProvider:   cfg.GraphProvider,
 		DaServicePort:   cfg.EigenDaHttpPort,
 		EigenLayerNode:  cfg.EigenLayerNode,
+		L2Client:        l2Client,
 	}
 	daService, err := restorer.NewDaService(ctx, daServiceConfig)
 	if err != nil {
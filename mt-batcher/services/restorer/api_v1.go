@@ -0,0 +1,479 @@
+package restorer
+
+import (
+	"bytes"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	gecho "github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+	"google.golang.org/grpc"
+
+	"github.com/Layr-Labs/datalayr/common/graphView"
+	pb "github.com/Layr-Labs/datalayr/common/interfaces/interfaceRetrieverServer"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+
+	common2 "github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/l2geth/core/types"
+	l2rlp "github.com/mantlenetworkio/mantle/l2geth/rlp"
+	"github.com/mantlenetworkio/mantle/l2geth/rollup/eigenda"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+	// batchCacheSize is the number of decoded data stores kept for paging
+	// through their transactions
+	batchCacheSize = 16
+)
+
+// Error codes of the v1 api
+const (
+	ErrCodeInvalidParam  = "INVALID_PARAM"
+	ErrCodeNotFound      = "NOT_FOUND"
+	ErrCodeUpstream      = "UPSTREAM_ERROR"
+	ErrCodeInvalidBatch  = "INVALID_BATCH"
+	ErrCodeNotConfigured = "NOT_CONFIGURED"
+)
+
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Page is the envelope of every paginated v1 response, NextCursor is empty on
+// the last page
+type Page struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// RollupBatchResponse is a rollup batch, its l2 block range [start, end) is
+// the one of the origin data store, a re-rollup replaces the data store of the
+// batch but not the blocks it covers
+type RollupBatchResponse struct {
+	BatchIndex         uint64 `json:"batch_index"`
+	DataStoreId        uint32 `json:"data_store_id"`
+	OriginDataStoreId  uint32 `json:"origin_data_store_id"`
+	ConfirmAt          uint32 `json:"confirm_at"`
+	Status             uint8  `json:"status"`
+	StartL2BlockNumber string `json:"start_l2_block_number"`
+	EndL2BlockNumber   string `json:"end_l2_block_number"`
+
+	start, end *big.Int
+}
+
+// decodedBatch is a data store retrieved from eigen da and decoded
+type decodedBatch struct {
+	version  byte
+	batchTxs []eigenda.BatchTx
+}
+
+type DataStoreResponse struct {
+	DataStore          graphView.DataStoreGql `json:"data_store"`
+	StartL2BlockNumber string                 `json:"start_l2_block_number"`
+	EndL2BlockNumber   string                 `json:"end_l2_block_number"`
+	IsReRollup         bool                   `json:"is_re_rollup"`
+}
+
+type BatchTransactionResponse struct {
+	Index         int                   `json:"index"`
+	L2BlockNumber string                `json:"l2_block_number"`
+	TxHash        string                `json:"tx_hash"`
+	TxMeta        types.TransactionMeta `json:"tx_meta"`
+}
+
+type DataStoreLookupResponse struct {
+	L2BlockNumber string `json:"l2_block_number"`
+	TxHash        string `json:"tx_hash,omitempty"`
+	BatchIndex    uint64 `json:"batch_index"`
+	DataStoreId   uint32 `json:"data_store_id"`
+}
+
+func (s *DaService) routesV1() {
+	routes := s.apiRoutesV1()
+	s.openAPISpec = newOpenAPISpec(routes)
+	v1 := s.echo.Group("/v1")
+	for _, route := range routes {
+		v1.GET(route.Path, route.Handler)
+	}
+}
+
+func (s *DaService) apiRoutesV1() []apiRoute {
+	storeNumberParam := apiParam{Name: "store_number", In: "path", Description: "Data store id", Pattern: "^[0-9]+$"}
+	return []apiRoute{
+		{
+			Path:    "/openapi.json",
+			Summary: "This document",
+			Handler: s.getOpenAPISpec,
+		},
+		{
+			Path:    "/rollup-batches",
+			Summary: "List rollup batches in batch index order",
+			Params: []apiParam{
+				cursorParam,
+				limitParam,
+				{Name: "from_l2_block", In: "query", Description: "Only return batches ending after this l2 block, ignored when a cursor is given", Pattern: "^[0-9]+$"},
+				{Name: "to_l2_block", In: "query", Description: "Only return batches starting at or before this l2 block", Pattern: "^[0-9]+$"},
+			},
+			Response: RollupBatchResponse{},
+			Paged:    true,
+			Errors:   []int{http.StatusBadRequest, http.StatusBadGateway},
+			Handler:  s.listRollupBatches,
+		},
+		{
+			Path:     "/data-stores/:store_number",
+			Summary:  "Get a data store and the l2 block range it rolls up",
+			Params:   []apiParam{storeNumberParam},
+			Response: DataStoreResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusBadGateway},
+			Handler:  s.getDataStore,
+		},
+		{
+			Path:     "/data-stores/:store_number/transactions",
+			Summary:  "List the l2 transactions of a data store in batch order",
+			Params:   []apiParam{storeNumberParam, cursorParam, limitParam},
+			Response: BatchTransactionResponse{},
+			Paged:    true,
+			Errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusBadGateway},
+			Handler:  s.listDataStoreTransactions,
+		},
+		{
+			Path:     "/l2-blocks/:number/data-store",
+			Summary:  "Find the data store an l2 block was rolled up in",
+			Params:   []apiParam{{Name: "number", In: "path", Description: "L2 block number", Pattern: "^[0-9]+$"}},
+			Response: DataStoreLookupResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusBadGateway},
+			Handler:  s.getDataStoreByL2Block,
+		},
+		{
+			Path:     "/transactions/:hash/data-store",
+			Summary:  "Find the data store an l2 transaction was rolled up in",
+			Params:   []apiParam{{Name: "hash", In: "path", Description: "L2 transaction hash", Pattern: "^0x[0-9a-fA-F]{64}$"}},
+			Response: DataStoreLookupResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotImplemented, http.StatusBadGateway},
+			Handler:  s.getDataStoreByTxHash,
+		},
+	}
+}
+
+func apiError(c gecho.Context, status int, code, message string) error {
+	return c.JSON(status, &ErrorResponse{Code: code, Message: message})
+}
+
+// parsePage reads the cursor and limit query params, an empty cursor is 0
+func parsePage(c gecho.Context) (uint64, int, error) {
+	var cursor uint64
+	if v := c.QueryParam("cursor"); v != "" {
+		var err error
+		if cursor, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return 0, 0, errors.New("cursor must be an unsigned integer")
+		}
+	}
+	limit := defaultPageLimit
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxPageLimit {
+			return 0, 0, errors.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		limit = n
+	}
+	return cursor, limit, nil
+}
+
+func parseBlockNumber(v string) (*big.Int, bool) {
+	if v == "" {
+		return nil, true
+	}
+	n, ok := new(big.Int).SetString(v, 10)
+	if !ok || n.Sign() < 0 {
+		return nil, false
+	}
+	return n, true
+}
+
+func (s *DaService) getOpenAPISpec(c gecho.Context) error {
+	return c.JSON(http.StatusOK, s.openAPISpec)
+}
+
+// rollupBatch reads a rollup batch and the l2 block range of its origin data
+// store, a batch slot without a data store is returned without a range
+func (s *DaService) rollupBatch(batchIndex uint64) (*RollupBatchResponse, error) {
+	rollupStore, err := s.Cfg.EigenContract.GetRollupStoreByRollupBatchIndex(&bind.CallOpts{}, new(big.Int).SetUint64(batchIndex))
+	if err != nil {
+		return nil, err
+	}
+	batch := &RollupBatchResponse{
+		BatchIndex:        batchIndex,
+		DataStoreId:       rollupStore.DataStoreId,
+		OriginDataStoreId: rollupStore.OriginDataStoreId,
+		ConfirmAt:         rollupStore.ConfirmAt,
+		Status:            rollupStore.Status,
+	}
+	if rollupStore.DataStoreId == 0 {
+		return batch, nil
+	}
+	rangeStoreId := rollupStore.OriginDataStoreId
+	if rangeStoreId == 0 {
+		rangeStoreId = rollupStore.DataStoreId
+	}
+	rollupBlock, err := s.Cfg.EigenContract.DataStoreIdToL2RollUpBlock(&bind.CallOpts{}, rangeStoreId)
+	if err != nil {
+		return nil, err
+	}
+	batch.StartL2BlockNumber = rollupBlock.StartL2BlockNumber.String()
+	batch.EndL2BlockNumber = rollupBlock.EndBL2BlockNumber.String()
+	batch.start, batch.end = rollupBlock.StartL2BlockNumber, rollupBlock.EndBL2BlockNumber
+	return batch, nil
+}
+
+// empty reports whether the batch slot has no data store
+func (b *RollupBatchResponse) empty() bool {
+	return b.start == nil
+}
+
+func (s *DaService) findRollupBatch(blockNumber *big.Int) (uint64, *RollupBatchResponse, error) {
+	count, err := s.Cfg.EigenContract.RollupBatchIndex(&bind.CallOpts{})
+	if err != nil {
+		return 0, nil, err
+	}
+	return searchRollupBatch(count.Uint64(), blockNumber, s.rollupBatch)
+}
+
+// searchRollupBatch binary searches the first count rollup batches for the
+// one whose l2 block range [start, end) contains blockNumber, the non empty
+// batches cover consecutive l2 block ranges in batch index order. When no
+// batch contains blockNumber the returned index is the first batch after it.
+func searchRollupBatch(count uint64, blockNumber *big.Int, getBatch func(uint64) (*RollupBatchResponse, error)) (uint64, *RollupBatchResponse, error) {
+	lo, hi := uint64(0), count
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		// an empty slot has no range, compare against the next batch with one
+		var batch *RollupBatchResponse
+		index := mid
+		for ; index < hi; index++ {
+			b, err := getBatch(index)
+			if err != nil {
+				return 0, nil, err
+			}
+			if !b.empty() {
+				batch = b
+				break
+			}
+		}
+		switch {
+		case batch == nil || blockNumber.Cmp(batch.start) < 0:
+			hi = mid
+		case blockNumber.Cmp(batch.end) >= 0:
+			lo = index + 1
+		default:
+			return index, batch, nil
+		}
+	}
+	return lo, nil, nil
+}
+
+func (s *DaService) listRollupBatches(c gecho.Context) error {
+	cursor, limit, err := parsePage(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, ErrCodeInvalidParam, err.Error())
+	}
+	fromBlock, ok := parseBlockNumber(c.QueryParam("from_l2_block"))
+	if !ok {
+		return apiError(c, http.StatusBadRequest, ErrCodeInvalidParam, "from_l2_block must be an unsigned integer")
+	}
+	toBlock, ok := parseBlockNumber(c.QueryParam("to_l2_block"))
+	if !ok {
+		return apiError(c, http.StatusBadRequest, ErrCodeInvalidParam, "to_l2_block must be an unsigned integer")
+	}
+	count, err := s.Cfg.EigenContract.RollupBatchIndex(&bind.CallOpts{})
+	if err != nil {
+		log.Error("get rollup batch index fail", "err", err)
+		return apiError(c, http.StatusBadGateway, ErrCodeUpstream, "fail to get rollup batch index")
+	}
+	if fromBlock != nil && c.QueryParam("cursor") == "" {
+		if cursor, _, err = s.findRollupBatch(fromBlock); err != nil {
+			log.Error("find rollup batch fail", "l2BlockNumber", fromBlock, "err", err)
+			return apiError(c, http.StatusBadGateway, ErrCodeUpstream, "fail to find rollup batch")
+		}
+	}
+	items := make([]*RollupBatchResponse, 0, limit)
+	next := cursor
+	for ; next < count.Uint64() && len(items) < limit; next++ {
+		batch, err := s.rollupBatch(next)
+		if err != nil {
+			log.Error("get rollup batch fail", "batchIndex", next, "err", err)
+			return apiError(c, http.StatusBadGateway, ErrCodeUpstream, "fail to get rollup batch")
+		}
+		if batch.empty() {
+			continue
+		}
+		if toBlock != nil && batch.start.Cmp(toBlock) > 0 {
+			next = count.Uint64()
+			break
+		}
+		if fromBlock != nil && batch.end.Cmp(fromBlock) <= 0 {
+			continue
+		}
+		items = append(items, batch)
+	}
+	page := &Page{Items: items}
+	if next < count.Uint64() {
+		page.NextCursor = strconv.FormatUint(next, 10)
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+func (s *DaService) getDataStore(c gecho.Context) error {
+	storeNumber, err := strconv.ParseUint(c.Param("store_number"), 10, 32)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, ErrCodeInvalidParam, "store_number must be an unsigned 32 bit integer")
+	}
+	var query struct {
+		DataStore graphView.DataStoreGql `graphql:"dataStore(id: $storeId)"`
+	}
+	variables := map[string]interface{}{
+		"storeId": graphql.String(strconv.FormatUint(storeNumber, 10)),
+	}
+	if err := s.GraphqlClient.Query(s.Ctx, &query, variables); err != nil {
+		log.Error("query data from graphql fail", "storeNumber", storeNumber, "err", err)
+		return apiError(c, http.StatusNotFound, ErrCodeNotFound, "data store not found")
+	}
+	rollupBlock, err := s.Cfg.EigenContract.DataStoreIdToL2RollUpBlock(&bind.CallOpts{}, uint32(storeNumber))
+	if err != nil {
+		log.Error("get l2 rollup block fail", "storeNumber", storeNumber, "err", err)
+		return apiError(c, http.StatusBadGateway, ErrCodeUpstream, "fail to get l2 block range of data store")
+	}
+	return c.JSON(http.StatusOK, &DataStoreResponse{
+		DataStore:          query.DataStore,
+		StartL2BlockNumber: rollupBlock.StartL2BlockNumber.String(),
+		EndL2BlockNumber:   rollupBlock.EndBL2BlockNumber.String(),
+		IsReRollup:         rollupBlock.IsReRollup,
+	})
+}
+
+// retrieveBatch retrieves a data store from the retriever and decodes it, data
+// stores never change so the decoded ones are cached for the following pages
+func (s *DaService) retrieveBatch(storeNumber uint32) (*decodedBatch, error) {
+	if cached, ok := s.batchCache.Get(storeNumber); ok {
+		return cached.(*decodedBatch), nil
+	}
+	conn, err := grpc.Dial(s.Cfg.RetrieverSocket, grpc.WithInsecure())
+	if err != nil {
+		return nil, errors.Wrap(err, "connect to retriever")
+	}
+	defer conn.Close()
+	client := pb.NewDataRetrievalClient(conn)
+	opt := grpc.MaxCallRecvMsgSize(maxCallReceiveMessageSize)
+	reply, err := client.RetrieveFramesAndData(s.Ctx, &pb.FramesAndDataRequest{DataStoreId: storeNumber}, opt)
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve frames and data")
+	}
+	if len(reply.GetData()) < 31*s.Cfg.EigenLayerNode {
+		return nil, errors.New("retrieved data is empty")
+	}
+	version, batchTxs, err := eigenda.DecodeBatch(reply.GetData())
+	if err != nil {
+		return nil, err
+	}
+	batch := &decodedBatch{version: version, batchTxs: batchTxs}
+	s.batchCache.Add(storeNumber, batch)
+	return batch, nil
+}
+
+func (s *DaService) listDataStoreTransactions(c gecho.Context) error {
+	storeNumber, err := strconv.ParseUint(c.Param("store_number"), 10, 32)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, ErrCodeInvalidParam, "store_number must be an unsigned 32 bit integer")
+	}
+	cursor, limit, err := parsePage(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, ErrCodeInvalidParam, err.Error())
+	}
+	batch, err := s.retrieveBatch(uint32(storeNumber))
+	if err != nil {
+		log.Error("retrieve batch fail", "storeNumber", storeNumber, "err", err)
+		return apiError(c, http.StatusBadGateway, ErrCodeUpstream, "fail to retrieve data store")
+	}
+	version, batchTxs := batch.version, batch.batchTxs
+	items := make([]*BatchTransactionResponse, 0, limit)
+	next := int(cursor)
+	for ; next < len(batchTxs) && len(items) < limit; next++ {
+		l2Tx := new(types.Transaction)
+		if err := l2Tx.DecodeRLP(l2rlp.NewStream(bytes.NewBuffer(batchTxs[next].RawTx), 0)); err != nil {
+			return apiError(c, http.StatusUnprocessableEntity, ErrCodeInvalidBatch, "decode rlp of batch tx fail")
+		}
+		txMeta, err := eigenda.DecodeTxMeta(version, batchTxs[next].TxMeta, batchTxs[next].RawTx)
+		if err != nil {
+			return apiError(c, http.StatusUnprocessableEntity, ErrCodeInvalidBatch, "decode tx meta of batch tx fail")
+		}
+		queueOrigin := types.QueueOriginSequencer
+		if txMeta.QueueIndex != nil {
+			queueOrigin = types.QueueOriginL1ToL2
+		}
+		items = append(items, &BatchTransactionResponse{
+			Index:         next,
+			L2BlockNumber: new(big.Int).SetBytes(batchTxs[next].BlockNumber).String(),
+			TxHash:        l2Tx.Hash().String(),
+			TxMeta: types.TransactionMeta{
+				L1BlockNumber:   txMeta.L1BlockNumber,
+				L1Timestamp:     txMeta.L1Timestamp,
+				L1MessageSender: txMeta.L1MessageSender,
+				QueueOrigin:     queueOrigin,
+				Index:           txMeta.Index,
+				QueueIndex:      txMeta.QueueIndex,
+				RawTransaction:  txMeta.RawTransaction,
+			},
+		})
+	}
+	page := &Page{Items: items}
+	if next < len(batchTxs) {
+		page.NextCursor = strconv.Itoa(next)
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+func (s *DaService) lookupDataStore(c gecho.Context, blockNumber *big.Int, txHash string) error {
+	_, batch, err := s.findRollupBatch(blockNumber)
+	if err != nil {
+		log.Error("find rollup batch fail", "l2BlockNumber", blockNumber, "err", err)
+		return apiError(c, http.StatusBadGateway, ErrCodeUpstream, "fail to find rollup batch")
+	}
+	if batch == nil {
+		return apiError(c, http.StatusNotFound, ErrCodeNotFound, "l2 block is not rolled up yet")
+	}
+	return c.JSON(http.StatusOK, &DataStoreLookupResponse{
+		L2BlockNumber: blockNumber.String(),
+		TxHash:        txHash,
+		BatchIndex:    batch.BatchIndex,
+		DataStoreId:   batch.DataStoreId,
+	})
+}
+
+func (s *DaService) getDataStoreByL2Block(c gecho.Context) error {
+	blockNumber, ok := parseBlockNumber(c.Param("number"))
+	if !ok {
+		return apiError(c, http.StatusBadRequest, ErrCodeInvalidParam, "number must be an unsigned integer")
+	}
+	return s.lookupDataStore(c, blockNumber, "")
+}
+
+func (s *DaService) getDataStoreByTxHash(c gecho.Context) error {
+	if s.Cfg.L2Client == nil {
+		return apiError(c, http.StatusNotImplemented, ErrCodeNotConfigured, "l2 client is not configured")
+	}
+	hash := c.Param("hash")
+	if len(common2.FromHex(hash)) != common2.HashLength {
+		return apiError(c, http.StatusBadRequest, ErrCodeInvalidParam, "hash must be a 32 byte hex string")
+	}
+	txHash := common2.HexToHash(hash)
+	receipt, err := s.Cfg.L2Client.TransactionReceipt(s.Ctx, txHash)
+	if err != nil || receipt == nil || receipt.BlockNumber == nil {
+		return apiError(c, http.StatusNotFound, ErrCodeNotFound, "transaction not found")
+	}
+	return s.lookupDataStore(c, receipt.BlockNumber, txHash.String())
+}
@@ -0,0 +1,137 @@
+package restorer
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	gecho "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePage(t *testing.T) {
+	tests := []struct {
+		query  string
+		cursor uint64
+		limit  int
+		err    bool
+	}{
+		{query: "", cursor: 0, limit: defaultPageLimit},
+		{query: "cursor=7&limit=10", cursor: 7, limit: 10},
+		{query: "limit=500", limit: maxPageLimit},
+		{query: "cursor=-1", err: true},
+		{query: "cursor=abc", err: true},
+		{query: "limit=0", err: true},
+		{query: "limit=501", err: true},
+		{query: "limit=ten", err: true},
+	}
+	e := gecho.New()
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c := e.NewContext(httptest.NewRequest("GET", "/v1/rollup-batches?"+tt.query, nil), httptest.NewRecorder())
+			cursor, limit, err := parsePage(c)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.cursor, cursor)
+			require.Equal(t, tt.limit, limit)
+		})
+	}
+}
+
+func TestParseBlockNumber(t *testing.T) {
+	n, ok := parseBlockNumber("")
+	require.True(t, ok)
+	require.Nil(t, n)
+
+	n, ok = parseBlockNumber("18446744073709551616")
+	require.True(t, ok)
+	require.Equal(t, "18446744073709551616", n.String())
+
+	for _, v := range []string{"-1", "0x10", "1.5", "abc"} {
+		_, ok = parseBlockNumber(v)
+		require.False(t, ok, v)
+	}
+}
+
+// testRollupBatches builds rollup batches from l2 block ranges, a nil range
+// is a batch slot without a data store
+func testRollupBatches(ranges ...[]int64) func(uint64) (*RollupBatchResponse, error) {
+	return func(index uint64) (*RollupBatchResponse, error) {
+		if index >= uint64(len(ranges)) {
+			return nil, errors.New("batch index out of range")
+		}
+		batch := &RollupBatchResponse{BatchIndex: index}
+		if r := ranges[index]; r != nil {
+			batch.start, batch.end = big.NewInt(r[0]), big.NewInt(r[1])
+		}
+		return batch, nil
+	}
+}
+
+func TestSearchRollupBatch(t *testing.T) {
+	getBatch := testRollupBatches(nil, []int64{1, 10}, nil, nil, []int64{10, 20}, []int64{25, 30}, nil)
+	tests := []struct {
+		block int64
+		index uint64
+		found bool
+	}{
+		{block: 0, index: 0},
+		{block: 1, index: 1, found: true},
+		{block: 9, index: 1, found: true},
+		{block: 10, index: 4, found: true},
+		{block: 19, index: 4, found: true},
+		{block: 22, index: 5},
+		{block: 29, index: 5, found: true},
+		{block: 30, index: 6},
+	}
+	for _, tt := range tests {
+		index, batch, err := searchRollupBatch(7, big.NewInt(tt.block), getBatch)
+		require.NoError(t, err)
+		require.Equal(t, tt.index, index, "block %d", tt.block)
+		require.Equal(t, tt.found, batch != nil, "block %d", tt.block)
+		if tt.found {
+			require.Equal(t, tt.index, batch.BatchIndex)
+		}
+	}
+
+	index, batch, err := searchRollupBatch(3, big.NewInt(5), testRollupBatches(nil, nil, nil))
+	require.NoError(t, err)
+	require.Nil(t, batch)
+	require.Equal(t, uint64(0), index)
+
+	_, _, err = searchRollupBatch(2, big.NewInt(5), testRollupBatches(nil))
+	require.Error(t, err)
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	routes := new(DaService).apiRoutesV1()
+	spec := newOpenAPISpec(routes)
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	var doc struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Len(t, doc.Paths, len(routes))
+	require.Contains(t, doc.Paths, "/data-stores/{store_number}/transactions")
+	require.Contains(t, doc.Paths["/l2-blocks/{number}/data-store"], "get")
+
+	// the schemas follow the json tags of the response types
+	batch := doc.Components.Schemas["RollupBatchResponse"].Properties
+	require.Contains(t, batch, "data_store_id")
+	require.Contains(t, batch, "end_l2_block_number")
+	require.NotContains(t, batch, "start")
+	require.Contains(t, doc.Components.Schemas["TransactionMeta"].Properties, "queueIndex")
+	require.JSONEq(t, `{"type":"string"}`, string(doc.Components.Schemas["BatchTransactionResponse"].Properties["tx_hash"]))
+}
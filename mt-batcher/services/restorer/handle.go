@@ -3,7 +3,6 @@ package restorer
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"math/big"
 	"net/http"
 	"strings"
@@ -18,7 +17,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/rlp"
 
 	common2 "github.com/mantlenetworkio/mantle/l2geth/common"
 	"github.com/mantlenetworkio/mantle/l2geth/core/types"
@@ -141,24 +139,20 @@ func (s *DaService) GetDtlBatchTransactionByDataStoreId(c gecho.Context) error {
 	if len(reply.GetData()) >= 31*s.Cfg.EigenLayerNode {
 		data := reply.GetData()
 
-		batchTxn := new([]eigenda.BatchTx)
-		batchRlpStream := rlp.NewStream(bytes.NewBuffer(data), uint64(len(data)))
-		err = batchRlpStream.Decode(batchTxn)
+		version, newBatchTxn, err := eigenda.DecodeBatch(data)
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, errors.New("decode data fail"))
 		}
 		var TxnRep []*TransactionInfoListResponse
-		newBatchTxn := *batchTxn
 		for i := 0; i < len(newBatchTxn); i++ {
 			l2Tx := new(types.Transaction)
-			txDecodeMetaData := new(eigenda.TransactionMeta)
 			if newBatchTxn[i].TxMeta == nil {
 				log.Error("Batch tx metadata shouldn't be nil")
 				return c.JSON(http.StatusBadRequest, errors.New("Batch tx metadata shouldn't be nil"))
 			}
-			err = json.Unmarshal(newBatchTxn[i].TxMeta, txDecodeMetaData)
+			txDecodeMetaData, err := eigenda.DecodeTxMeta(version, newBatchTxn[i].TxMeta, newBatchTxn[i].RawTx)
 			if err != nil {
-				return c.JSON(http.StatusBadRequest, errors.New("Unmarshal json fail"))
+				return c.JSON(http.StatusBadRequest, errors.New("decode tx meta fail"))
 			}
 			rlpStream := l2rlp.NewStream(bytes.NewBuffer(newBatchTxn[i].RawTx), 0)
 			if err := l2Tx.DecodeRLP(rlpStream); err != nil {
@@ -267,14 +261,11 @@ func (s *DaService) GetTransactionListByStoreNumber(c gecho.Context) error {
 	}
 	data := reply.GetData()
 	if len(data) >= 31*s.Cfg.EigenLayerNode {
-		batchTxn := new([]eigenda.BatchTx)
-		batchRlpStream := rlp.NewStream(bytes.NewBuffer(data), uint64(len(data)))
-		err = batchRlpStream.Decode(batchTxn)
+		_, newBatchTxn, err := eigenda.DecodeBatch(data)
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, errors.New("decode data fail"))
 		}
 		var TxnRep []*TransactionListResponse
-		newBatchTxn := *batchTxn
 		for i := 0; i < len(newBatchTxn); i++ {
 			l2Tx := new(types.Transaction)
 			rlpStream := l2rlp.NewStream(bytes.NewBuffer(newBatchTxn[i].RawTx), 0)
@@ -0,0 +1,215 @@
+package restorer
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	gecho "github.com/labstack/echo/v4"
+)
+
+// apiParam is a query or path parameter of a v1 route, every parameter is
+// passed as a string, Pattern restricts its format
+type apiParam struct {
+	Name        string
+	In          string
+	Description string
+	Pattern     string
+}
+
+// apiRoute is a GET route of the v1 api, the OpenAPI document is generated
+// from the same table the routes are registered from so the two never drift
+type apiRoute struct {
+	Path    string
+	Summary string
+	Params  []apiParam
+	// Response is a zero value of the response body, or of a single item
+	// when Paged is set
+	Response interface{}
+	Paged    bool
+	Errors   []int
+	Handler  gecho.HandlerFunc
+}
+
+var (
+	cursorParam = apiParam{Name: "cursor", In: "query", Description: "Opaque cursor of the page, next_cursor of the previous page"}
+	limitParam  = apiParam{Name: "limit", In: "query", Description: "Page size between 1 and " + strconv.Itoa(maxPageLimit) + ", " + strconv.Itoa(defaultPageLimit) + " by default", Pattern: "^[0-9]+$"}
+)
+
+var (
+	bigIntType        = reflect.TypeOf(big.Int{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+type openAPISpec map[string]interface{}
+
+// newOpenAPISpec describes the routes and the json schemas of their response
+// types as an OpenAPI 3 document
+func newOpenAPISpec(routes []apiRoute) openAPISpec {
+	schemas := map[string]interface{}{}
+	errorSchema := schemaOf(reflect.TypeOf(ErrorResponse{}), schemas)
+	schemas["ErrorResponse"].(map[string]interface{})["properties"].(map[string]interface{})["code"] = map[string]interface{}{
+		"type": "string",
+		"enum": []string{ErrCodeInvalidParam, ErrCodeNotFound, ErrCodeUpstream, ErrCodeInvalidBatch, ErrCodeNotConfigured},
+	}
+	pageSchema := schemaOf(reflect.TypeOf(Page{}), schemas)
+
+	paths := map[string]interface{}{}
+	for _, route := range routes {
+		var params []interface{}
+		for _, param := range route.Params {
+			schema := map[string]interface{}{"type": "string"}
+			if param.Pattern != "" {
+				schema["pattern"] = param.Pattern
+			}
+			params = append(params, map[string]interface{}{
+				"name":        param.Name,
+				"in":          param.In,
+				"description": param.Description,
+				"required":    param.In == "path",
+				"schema":      schema,
+			})
+		}
+
+		var body interface{} = map[string]interface{}{"type": "object"}
+		if route.Response != nil {
+			body = schemaOf(reflect.TypeOf(route.Response), schemas)
+		}
+		if route.Paged {
+			body = map[string]interface{}{
+				"allOf": []interface{}{
+					pageSchema,
+					map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"items": map[string]interface{}{"type": "array", "items": body},
+						},
+					},
+				},
+			}
+		}
+		responses := map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": http.StatusText(http.StatusOK),
+				"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": body}},
+			},
+		}
+		for _, status := range route.Errors {
+			responses[strconv.Itoa(status)] = map[string]interface{}{
+				"description": http.StatusText(status),
+				"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": errorSchema}},
+			}
+		}
+
+		operation := map[string]interface{}{"summary": route.Summary, "responses": responses}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		paths[openAPIPath(route.Path)] = map[string]interface{}{"get": operation}
+	}
+
+	return openAPISpec{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "mt-batcher restorer api",
+			"version": "1.0",
+			"description": "Read only access to the rollup batches stored on eigen da by mt-batcher. " +
+				"Paginated endpoints return a Page, pass next_cursor back as cursor to fetch the next page, " +
+				"it is omitted on the last page. Every error is returned as an ErrorResponse.",
+		},
+		"servers":    []interface{}{map[string]interface{}{"url": "/v1"}},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+// openAPIPath turns the echo path params into OpenAPI ones, /a/:b to /a/{b}
+func openAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// schemaOf returns the json schema of t as encoding/json marshals it, named
+// structs are added to schemas and referenced
+func schemaOf(t reflect.Type, schemas map[string]interface{}) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == bigIntType:
+		return map[string]interface{}{"type": "integer"}
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() != reflect.Struct && (t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType)):
+		// hex encoded addresses, hashes and numbers, structs with a generated
+		// marshaler keep the field names of their json tags
+		return map[string]interface{}{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		if _, ok := schemas[t.Name()]; !ok {
+			// registered before the fields so recursive types terminate
+			schemas[t.Name()] = map[string]interface{}{}
+			schemas[t.Name()] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaOf(field.Type, schemas)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -7,10 +7,12 @@ import (
 
 	"github.com/Layr-Labs/datalayr/common/graphView"
 	"github.com/ethereum/go-ethereum/log"
+	lru "github.com/hashicorp/golang-lru"
 	gecho "github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/shurcooL/graphql"
 
+	l2ethclient "github.com/mantlenetworkio/mantle/l2geth/ethclient"
 	"github.com/mantlenetworkio/mantle/mt-batcher/bindings"
 	"github.com/mantlenetworkio/mantle/mt-batcher/services/common"
 )
@@ -21,6 +23,7 @@ type DaServiceConfig struct {
 	GraphProvider   string
 	DaServicePort   int
 	EigenLayerNode  int
+	L2Client        *l2ethclient.Client
 }
 
 type DaService struct {
@@ -29,6 +32,8 @@ type DaService struct {
 	GraphClient   *graphView.GraphClient
 	GraphqlClient *graphql.Client
 	echo          *gecho.Echo
+	openAPISpec   openAPISpec
+	batchCache    *lru.Cache
 	cancel        func()
 	wg            sync.WaitGroup
 }
@@ -44,12 +49,17 @@ func NewDaService(ctx context.Context, cfg *DaServiceConfig) (*DaService, error)
 	e.Use(middleware.Recover())
 	graphClient := graphView.NewGraphClient(cfg.GraphProvider, nil)
 	graphqlClient := graphql.NewClient(graphClient.GetEndpoint(), nil)
+	batchCache, err := lru.New(batchCacheSize)
+	if err != nil {
+		return nil, err
+	}
 	server := &DaService{
 		Ctx:           ctx,
 		Cfg:           cfg,
 		GraphClient:   graphClient,
 		GraphqlClient: graphqlClient,
 		echo:          e,
+		batchCache:    batchCache,
 		cancel:        cancel,
 	}
 	server.routes()
@@ -64,6 +74,7 @@ func (s *DaService) routes() {
 	s.echo.POST("browser/getDataStoreList", s.GetDataStoreList)
 	s.echo.POST("browser/getDataStoreById", s.getDataStoreById)
 	s.echo.POST("browser/GetTransactionListByStoreNumber", s.GetTransactionListByStoreNumber)
+	s.routesV1()
 }
 
 func (s *DaService) Start() error {
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/Layr-Labs/datalayr/common/graphView"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	common2 "github.com/mantlenetworkio/mantle/mt-batcher/common"
+)
+
+type BatchStatus uint8
+
+const (
+	BatchAggregated BatchStatus = iota + 1
+	BatchDispersed
+	BatchGraphIndexed
+	BatchConfirmed
+)
+
+func (s BatchStatus) String() string {
+	switch s {
+	case BatchAggregated:
+		return "aggregated"
+	case BatchDispersed:
+		return "dispersed"
+	case BatchGraphIndexed:
+		return "graph-indexed"
+	case BatchConfirmed:
+		return "confirmed"
+	default:
+		return "unknown"
+	}
+}
+
+// RollupBatch is the batch currently moving through the rollup pipeline, it is
+// persisted after every transition so that a restarted batcher resumes from
+// the last finished step instead of dispersing the range again. DataStore is
+// the init data store event indexed by the graph node, the confirm calldata is
+// built from its StoreNumber and MsgHash. Attempts counts the failed graph
+// polls and confirms of the dispersed data store.
+type RollupBatch struct {
+	Status             BatchStatus          `json:"status"`
+	StartL2BlockNumber *big.Int             `json:"start_l2_block_number"`
+	EndL2BlockNumber   *big.Int             `json:"end_l2_block_number"`
+	Data               []byte               `json:"data"`
+	Params             common2.StoreParams  `json:"params"`
+	StoreTxHash        common.Hash          `json:"store_tx_hash"`
+	DataStore          *graphView.DataStore `json:"data_store"`
+	Attempts           int                  `json:"attempts"`
+	ConfirmTxHash      common.Hash          `json:"confirm_tx_hash"`
+	L1GasCost          *big.Int             `json:"l1_gas_cost"`
+}
+
+func (s *Store) GetRollupBatch() (*RollupBatch, bool) {
+	key := []byte("RollupBatch")
+	data, err := s.db.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	var batch RollupBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		log.Error("Could not decode rollup batch", "err", err)
+		return nil, false
+	}
+	return &batch, true
+}
+
+func (s *Store) SetRollupBatch(batch *RollupBatch) bool {
+	key := []byte("RollupBatch")
+	data, err := json.Marshal(batch)
+	if err != nil {
+		log.Error("Could not encode rollup batch", "err", err)
+		return false
+	}
+	err = s.db.Put(key, data)
+	return err == nil
+}
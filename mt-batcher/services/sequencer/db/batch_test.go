@@ -0,0 +1,76 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/datalayr/common/graphView"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollupBatchTransitions(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	_, ok := store.GetRollupBatch()
+	require.False(t, ok)
+
+	batch := &RollupBatch{
+		Status:             BatchAggregated,
+		StartL2BlockNumber: big.NewInt(10),
+		EndL2BlockNumber:   big.NewInt(20),
+		Data:               []byte{1, 2, 3},
+	}
+	require.True(t, store.SetRollupBatch(batch))
+	stored, ok := store.GetRollupBatch()
+	require.True(t, ok)
+	require.Equal(t, batch, stored)
+
+	// every step keeps the fields of the previous ones
+	stored.Status = BatchDispersed
+	stored.StoreTxHash = common.HexToHash("0x01")
+	stored.L1GasCost = big.NewInt(100)
+	require.True(t, store.SetRollupBatch(stored))
+	dispersed, ok := store.GetRollupBatch()
+	require.True(t, ok)
+	require.Equal(t, BatchDispersed, dispersed.Status)
+	require.Equal(t, batch.Data, dispersed.Data)
+	require.Equal(t, stored.StoreTxHash, dispersed.StoreTxHash)
+
+	// the data store indexed by the graph node is kept for the confirm
+	dispersed.Status = BatchGraphIndexed
+	dispersed.DataStore = &graphView.DataStore{StoreNumber: 7, MsgHash: [32]byte{1}, InitBlockNumber: big.NewInt(50), Fee: big.NewInt(10)}
+	dispersed.Attempts = 2
+	require.True(t, store.SetRollupBatch(dispersed))
+	indexed, ok := store.GetRollupBatch()
+	require.True(t, ok)
+	require.Equal(t, BatchGraphIndexed, indexed.Status)
+	require.Equal(t, dispersed.DataStore, indexed.DataStore)
+	require.Equal(t, 2, indexed.Attempts)
+
+	indexed.Status = BatchConfirmed
+	indexed.ConfirmTxHash = common.HexToHash("0x02")
+	indexed.L1GasCost = big.NewInt(300)
+	require.True(t, store.SetRollupBatch(indexed))
+	confirmed, ok := store.GetRollupBatch()
+	require.True(t, ok)
+	require.Equal(t, indexed, confirmed)
+
+	// the next batch replaces the confirmed one
+	next := &RollupBatch{Status: BatchAggregated, StartL2BlockNumber: big.NewInt(20), EndL2BlockNumber: big.NewInt(30)}
+	require.True(t, store.SetRollupBatch(next))
+	stored, ok = store.GetRollupBatch()
+	require.True(t, ok)
+	require.Equal(t, BatchAggregated, stored.Status)
+	require.Equal(t, big.NewInt(20), stored.StartL2BlockNumber)
+	require.Empty(t, stored.StoreTxHash)
+}
+
+func TestBatchStatusString(t *testing.T) {
+	require.Equal(t, "aggregated", BatchAggregated.String())
+	require.Equal(t, "dispersed", BatchDispersed.String())
+	require.Equal(t, "graph-indexed", BatchGraphIndexed.String())
+	require.Equal(t, "confirmed", BatchConfirmed.String())
+	require.Equal(t, "unknown", BatchStatus(0).String())
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	l2gethcommon "github.com/mantlenetworkio/mantle/l2geth/common"
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
 	l2ethclient "github.com/mantlenetworkio/mantle/l2geth/ethclient"
 	l2rlp "github.com/mantlenetworkio/mantle/l2geth/rlp"
 	common3 "github.com/mantlenetworkio/mantle/l2geth/rollup/eigenda"
@@ -44,6 +45,13 @@ var (
 	pollingInterval = 1000 * time.Millisecond
 )
 
+const (
+	blockFetchRetries = 5
+	blockFetchBackoff = 500 * time.Millisecond
+
+	maxConfirmAttempts = 5
+)
+
 type SignerFn func(context.Context, common.Address, *types.Transaction) (*types.Transaction, error)
 
 type DriverConfig struct {
@@ -61,6 +69,9 @@ type DriverConfig struct {
 	BlockOffset               uint64
 	RollUpMinTxn              uint64
 	RollUpMaxSize             uint64
+	BlockFetchConcurrency     int
+	BatchVersion              byte
+	BatchCompression          common3.Compression
 	EigenLayerNode            int
 	DataStoreDuration         uint64
 	DataStoreTimeout          uint64
@@ -77,8 +88,10 @@ type DriverConfig struct {
 	DbPath                    string
 	CheckerBatchIndex         uint64
 	CheckerEnable             bool
-	FeeSizeSec                string
 	FeePerBytePerTime         uint64
+	FeeWindowSize             int
+	FeeSmoothing              float64
+	FeeSignificanceFactor     float64
 	FeeModelEnable            bool
 	MinTimeoutRollupTxn       uint64
 	RollupTimeout             time.Duration
@@ -92,11 +105,6 @@ type DriverConfig struct {
 	HsmCreden     string
 }
 
-type FeePipline struct {
-	RollUpFee        *big.Int
-	EndL2BlockNumber *big.Int
-}
-
 type Driver struct {
 	Ctx           context.Context
 	Cfg           *DriverConfig
@@ -106,7 +114,8 @@ type Driver struct {
 	DtlClient     client.DtlClient
 	txMgr         txmgr.TxManager
 	LevelDBStore  *db.Store
-	FeeCh         chan *FeePipline
+	FeeEstimator  *FeeEstimator
+	feeSignal     chan struct{}
 	cancel        func()
 	wg            sync.WaitGroup
 }
@@ -150,8 +159,13 @@ func NewDriver(ctx context.Context, cfg *DriverConfig) (*Driver, error) {
 		DtlClient:     dtlClient,
 		txMgr:         txMgr,
 		LevelDBStore:  levelDBStore,
-		FeeCh:         make(chan *FeePipline),
-		cancel:        cancel,
+		FeeEstimator: NewFeeEstimator(FeeEstimatorConfig{
+			WindowSize:        cfg.FeeWindowSize,
+			Smoothing:         cfg.FeeSmoothing,
+			FeePerBytePerTime: cfg.FeePerBytePerTime,
+		}),
+		feeSignal: make(chan struct{}, 1),
+		cancel:    cancel,
 	}, nil
 }
 
@@ -240,24 +254,72 @@ func (d *Driver) GetBatchBlockRange(ctx context.Context) (*big.Int, *big.Int, er
 	return start, end, nil
 }
 
-func (d *Driver) TxAggregator(ctx context.Context, start, end *big.Int) (transactionData []byte, startL2BlockNumber *big.Int, endL2BlockNumber *big.Int) {
+// fetchBlock gets an l2 block, retrying with an exponential backoff so that a
+// short l2geth hiccup does not abort the whole batch
+func (d *Driver) fetchBlock(ctx context.Context, number *big.Int) (*l2types.Block, error) {
+	return retryBlockFetch(ctx, number, blockFetchBackoff, d.Cfg.L2Client.BlockByNumber)
+}
+
+// retryBlockFetch calls fetch up to blockFetchRetries times, doubling backoff
+// after every retryable failure
+func retryBlockFetch(ctx context.Context, number *big.Int, backoff time.Duration, fetch func(context.Context, *big.Int) (*l2types.Block, error)) (*l2types.Block, error) {
+	for attempt := 1; ; attempt++ {
+		block, err := fetch(ctx, number)
+		if err == nil {
+			return block, nil
+		}
+		if attempt >= blockFetchRetries || !isRetryableFetchError(err) {
+			return nil, err
+		}
+		log.Warn("MtBatcher get block from l2 fail, retrying", "blockNumber", number, "attempt", attempt, "backoff", backoff, "err", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// isRetryableFetchError reports whether fetching a block again may succeed, a
+// canceled or timed out fetch is given up on
+func isRetryableFetchError(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// TxAggregator builds a batch from the l2 blocks in [start, end), stopping
+// early once RollUpMaxSize is reached. It never skips a block, any block that
+// cannot be batched fails the whole range with an *AggregateError.
+func (d *Driver) TxAggregator(ctx context.Context, start, end *big.Int) ([]byte, *big.Int, *big.Int, error) {
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	blocks := prefetchBlocks(fetchCtx, start, end, d.Cfg.BlockFetchConcurrency, d.fetchBlock)
+
 	var batchTxList []common3.BatchTx
-	var transactionByte []byte
+	// contentSize is the length of the rlp encoded batch txs so far, tracked
+	// incrementally instead of re-encoding the whole list for every block
+	var contentSize uint64
 	for i := new(big.Int).Set(start); i.Cmp(end) < 0; i.Add(i, bigOne) {
-		block, err := d.Cfg.L2Client.BlockByNumber(ctx, i)
-		if err != nil {
-			log.Error("get blockNumber from l2 fail", "err", err)
-			continue
+		result, ok := <-blocks
+		if !ok {
+			return nil, nil, nil, newAggregateError(i, ErrFetchBlock, ctx.Err())
+		}
+		fetched := <-result
+		if fetched.err != nil {
+			return nil, nil, nil, newAggregateError(i, ErrFetchBlock, fetched.err)
+		}
+		block := fetched.block
+		if block.Number().Cmp(i) != 0 {
+			return nil, nil, nil, newAggregateError(i, ErrUnexpectedBlockNum, fmt.Errorf("got block %v", block.Number()))
 		}
 		txs := block.Transactions()
 		if len(txs) != 1 {
-			panic(fmt.Sprintf("MtBatcher attempting to create batch element from block %d, "+
-				"found %d txs instead of 1", block.Number(), len(txs)))
+			return nil, nil, nil, newAggregateError(i, ErrUnexpectedTxCount, fmt.Errorf("found %d txs", len(txs)))
 		}
-		log.Debug("MtBatcher origin transactions", "TxHash", txs[0].Hash().String(), "l2BlockNumber", block.Number(), "QueueOrigin", txs[0].QueueOrigin(), "Index", *txs[0].GetMeta().Index, "QueueIndex", txs[0].GetMeta().QueueIndex, "i", i)
+		log.Debug("MtBatcher origin transactions", "TxHash", txs[0].Hash().String(), "l2BlockNumber", block.Number(), "QueueOrigin", txs[0].QueueOrigin(), "Index", txs[0].GetMeta().Index, "QueueIndex", txs[0].GetMeta().QueueIndex, "i", i)
 		var txBuf bytes.Buffer
 		if err := txs[0].EncodeRLP(&txBuf); err != nil {
-			panic(fmt.Sprintf("MtBatcher Unable to encode tx: %v", err))
+			return nil, nil, nil, newAggregateError(i, ErrEncodeBatch, err)
 		}
 		var l1MessageSender *l2gethcommon.Address
 		if txs[0].GetMeta().QueueIndex != nil {
@@ -279,31 +341,33 @@ func (d *Driver) TxAggregator(ctx context.Context, start, end *big.Int) (transac
 			QueueIndex:      txs[0].GetMeta().QueueIndex,
 			RawTransaction:  txs[0].GetMeta().RawTransaction,
 		}
-		txMetaByte, err := json.Marshal(txMeta)
+		txMetaByte, err := d.encodeTxMeta(txMeta)
 		if err != nil {
-			log.Error("tx meta json marshal error", "err", err)
-			continue
+			return nil, nil, nil, newAggregateError(i, ErrEncodeBatch, err)
 		}
 		batchTx := common3.BatchTx{
 			BlockNumber: i.Bytes(),
 			TxMeta:      txMetaByte,
 			RawTx:       txBuf.Bytes(),
 		}
-		batchTxList = append(batchTxList, batchTx)
-		txnBufBytes, err := l2rlp.EncodeToBytes(batchTxList)
+		batchTxBytes, err := l2rlp.EncodeToBytes(batchTx)
 		if err != nil {
-			panic(fmt.Sprintf("MtBatcher Unable to encode txn: %v", err))
+			return nil, nil, nil, newAggregateError(i, ErrEncodeBatch, err)
 		}
-		if uint64(len(txnBufBytes)) >= d.Cfg.RollUpMaxSize {
+		if rlpListSize(contentSize+uint64(len(batchTxBytes))) >= d.Cfg.RollUpMaxSize {
 			log.Info("MtBatcher batch size more than RollUpMaxSize, real rollup data", "RollUpMaxSize", d.Cfg.RollUpMaxSize, "start", start, "end", i)
-			return transactionByte, start, i
-		} else {
-			transactionByte = txnBufBytes
+			end = new(big.Int).Set(i)
+			break
 		}
+		contentSize += uint64(len(batchTxBytes))
+		batchTxList = append(batchTxList, batchTx)
 	}
-	txnBufBytes, err := l2rlp.EncodeToBytes(batchTxList)
+	if len(batchTxList) == 0 {
+		return nil, nil, nil, newAggregateError(start, ErrEmptyBatch, nil)
+	}
+	txnBufBytes, err := d.encodeBatch(batchTxList)
 	if err != nil {
-		panic(fmt.Sprintf("MtBatcher Unable to encode txn: %v", err))
+		return nil, nil, nil, newAggregateError(end, ErrEncodeBatch, err)
 	}
 	var totalNode int
 	daNodes, err := d.GetEigenLayerNode()
@@ -315,13 +379,40 @@ func (d *Driver) TxAggregator(ctx context.Context, start, end *big.Int) (transac
 		totalNode = daNodes
 	}
 	d.Cfg.Metrics.NumEigenNode().Set(float64(daNodes))
-	if len(txnBufBytes) > 31*totalNode {
-		transactionByte = txnBufBytes
-	} else {
-		paddingBytes := make([]byte, (31*d.Cfg.EigenLayerNode)-len(txnBufBytes))
+	transactionByte := txnBufBytes
+	if len(txnBufBytes) <= 31*totalNode {
+		paddingBytes := make([]byte, (31*totalNode)-len(txnBufBytes))
 		transactionByte = append(txnBufBytes, paddingBytes...)
 	}
-	return transactionByte, start, end
+	return transactionByte, start, end, nil
+}
+
+// recordAggregateError exposes TxAggregator failures as metrics so that a
+// batcher stuck on a single l2 block can be alerted on
+func (d *Driver) recordAggregateError(err error) {
+	var aggErr *AggregateError
+	if !errors.As(err, &aggErr) {
+		return
+	}
+	log.Error("MtBatcher unable to aggregate l2 blocks", "blockNumber", aggErr.BlockNumber, "err", err)
+	d.Cfg.Metrics.TxAggregatorErrors().WithLabelValues(aggErr.reason()).Inc()
+	d.Cfg.Metrics.TxAggregatorFailedBlock().Set(float64(aggErr.BlockNumber.Uint64()))
+}
+
+// encodeTxMeta encodes the tx meta as json for legacy batches and in the
+// compact binary format for versioned batches
+func (d *Driver) encodeTxMeta(txMeta *common3.TransactionMeta) ([]byte, error) {
+	if d.Cfg.BatchVersion == common3.BatchVersionLegacy {
+		return json.Marshal(txMeta)
+	}
+	return common3.EncodeTxMeta(txMeta)
+}
+
+func (d *Driver) encodeBatch(batchTxList []common3.BatchTx) ([]byte, error) {
+	if d.Cfg.BatchVersion == common3.BatchVersionLegacy {
+		return l2rlp.EncodeToBytes(batchTxList)
+	}
+	return common3.EncodeBatch(batchTxList, d.Cfg.BatchCompression)
 }
 
 func (d *Driver) StoreData(ctx context.Context, uploadHeader []byte, duration uint8, blockNumber uint32, startL2BlockNumber *big.Int, endL2BlockNumber *big.Int, totalOperatorsIndex uint32, isReRollup bool) (*types.Transaction, error) {
@@ -465,7 +556,9 @@ func (d *Driver) GetEigenLayerNode() (int, error) {
 	return len(operators), nil
 }
 
-func (d *Driver) ConfirmStoredData(txHash []byte, params common2.StoreParams, startl2BlockNumber, endl2BlockNumber *big.Int, originDataStoreId uint32, reConfirmedBatchIndex *big.Int, isReRollup bool) (*types.Receipt, error) {
+// PollDataStore polls the graph node until it indexed the init data store
+// event of the store data transaction txHash
+func (d *Driver) PollDataStore(txHash []byte) (*graphView.DataStore, error) {
 	event, ok := d.GraphClient.PollingInitDataStore(
 		d.Ctx,
 		txHash[:],
@@ -476,6 +569,10 @@ func (d *Driver) ConfirmStoredData(txHash []byte, params common2.StoreParams, st
 		return nil, errors.New("MtBatcher could not get initDataStore")
 	}
 	log.Debug("PollingInitDataStore", "MsgHash", event.MsgHash, "StoreNumber", event.StoreNumber)
+	return event, nil
+}
+
+func (d *Driver) ConfirmStoredData(event *graphView.DataStore, params common2.StoreParams, startl2BlockNumber, endl2BlockNumber *big.Int, originDataStoreId uint32, reConfirmedBatchIndex *big.Int, isReRollup bool) (*types.Receipt, error) {
 	meta, err := d.callDisperse(
 		params.HeaderHash,
 		event.MsgHash[:],
@@ -603,18 +700,35 @@ func (d *Driver) callDisperse(headerHash []byte, messageHash []byte) (common2.Di
 	return meta, nil
 }
 
-func (d *Driver) CalcUserFeeByRules(rollupDateSize *big.Int) (*big.Int, error) {
-	seconds := new(big.Int).Div(big.NewInt(int64(d.Cfg.MainWorkerPollInterval)), big.NewInt(1000000000))
-	rollSizeSec := new(big.Int).Div(rollupDateSize, seconds)
-	feeSs, ok := new(big.Int).SetString(d.Cfg.FeeSizeSec, 10)
-	if !ok {
-		log.Error("FeeSizeSec from string to big.int fail")
-		return big.NewInt(0), nil
+// l1GasCost returns the wei paid for a mined l1 tx
+func (d *Driver) l1GasCost(ctx context.Context, receipt *types.Receipt) (*big.Int, error) {
+	tx, _, err := d.Cfg.L1Client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return nil, err
 	}
-	if rollSizeSec.Cmp(feeSs) < 0 {
-		return big.NewInt(0), nil
+	header, err := d.Cfg.L1Client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, err
 	}
-	return new(big.Int).Mul(new(big.Int).Div(rollSizeSec, feeSs), new(big.Int).SetUint64(d.Cfg.FeePerBytePerTime)), nil
+	gasPrice := tx.GasPrice()
+	if header.BaseFee != nil {
+		gasPrice = new(big.Int).Add(header.BaseFee, tx.EffectiveGasTipValue(header.BaseFee))
+	}
+	return new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed)), nil
+}
+
+// addL1GasCost adds the cost of a rollup tx to the batch, a failure only
+// makes the fee estimate less accurate so it is not fatal
+func (d *Driver) addL1GasCost(batch *db.RollupBatch, receipt *types.Receipt) {
+	cost, err := d.l1GasCost(d.Ctx, receipt)
+	if err != nil {
+		log.Warn("MtBatcher unable to get l1 gas cost of rollup tx", "txHash", receipt.TxHash, "err", err)
+		return
+	}
+	if batch.L1GasCost == nil {
+		batch.L1GasCost = new(big.Int)
+	}
+	batch.L1GasCost.Add(batch.L1GasCost, cost)
 }
 
 func (d *Driver) UpdateFee(ctx context.Context, l2Block, daFee *big.Int) (*types.Transaction, error) {
@@ -794,6 +908,140 @@ func (d *Driver) GetBatchBlockRangeWithTimeout(ctx context.Context) (*big.Int, *
 	}
 }
 
+// loadRollupBatch returns the unfinished batch persisted by a previous run, or
+// aggregates a new one from the next l2 block range
+func (d *Driver) loadRollupBatch() (*db.RollupBatch, error) {
+	batch, ok := d.LevelDBStore.GetRollupBatch()
+	if ok && batch.Status != db.BatchConfirmed {
+		confirmed, err := d.Cfg.EigenDaContract.GetL2ConfirmedBlockNumber(&bind.CallOpts{})
+		if err != nil {
+			return nil, err
+		}
+		if resumeRollupBatch(batch, confirmed) {
+			log.Info("MtBatcher resume rollup batch", "status", batch.Status, "start", batch.StartL2BlockNumber, "end", batch.EndL2BlockNumber)
+			return batch, nil
+		}
+		if batch.Status == db.BatchConfirmed {
+			log.Info("MtBatcher pending rollup batch already confirmed on chain", "start", batch.StartL2BlockNumber, "end", batch.EndL2BlockNumber)
+			d.LevelDBStore.SetRollupBatch(batch)
+		} else {
+			log.Warn("MtBatcher drop stale aggregated rollup batch", "start", batch.StartL2BlockNumber, "confirmed", confirmed)
+		}
+	}
+	start, end, err := d.GetBatchBlockRangeWithTimeout(d.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("MtBatcher get batch block range", "start", start, "end", end)
+	aggregateTxData, startL2BlockNumber, endL2BlockNumber, err := d.TxAggregator(
+		d.Ctx, start, end,
+	)
+	if err != nil {
+		d.recordAggregateError(err)
+		return nil, err
+	}
+	d.Cfg.Metrics.TxAggregatorFailedBlock().Set(0)
+	d.Cfg.Metrics.NumTxnPerBatch().Observe(float64((new(big.Int).Sub(endL2BlockNumber, startL2BlockNumber)).Uint64()))
+	d.Cfg.Metrics.BatchSizeBytes().Observe(float64(len(aggregateTxData)))
+	batch = &db.RollupBatch{
+		Status:             db.BatchAggregated,
+		StartL2BlockNumber: startL2BlockNumber,
+		EndL2BlockNumber:   endL2BlockNumber,
+		Data:               aggregateTxData,
+	}
+	if !d.LevelDBStore.SetRollupBatch(batch) {
+		return nil, errors.New("MtBatcher persist aggregated rollup batch fail")
+	}
+	return batch, nil
+}
+
+// resumeRollupBatch reports whether a persisted unfinished batch is still to
+// be rolled up once the l2 blocks up to confirmed are confirmed on chain. A
+// batch a previous run already confirmed is marked confirmed, an aggregated
+// batch that no longer starts at the confirmed block is stale.
+func resumeRollupBatch(batch *db.RollupBatch, confirmed *big.Int) bool {
+	switch {
+	case confirmed.Cmp(batch.EndL2BlockNumber) >= 0:
+		batch.Status = db.BatchConfirmed
+		return false
+	case batch.Status == db.BatchAggregated && confirmed.Cmp(batch.StartL2BlockNumber) != 0:
+		return false
+	default:
+		return true
+	}
+}
+
+// retryRollupBatch records a failed graph poll or confirm of a dispersed
+// batch. Once the data store failed maxConfirmAttempts times it is given up and
+// the batch is reset to aggregated so that its range is dispersed again, it
+// reports whether the batch was reset.
+func retryRollupBatch(batch *db.RollupBatch) bool {
+	batch.Attempts++
+	if batch.Attempts < maxConfirmAttempts {
+		return false
+	}
+	batch.Status = db.BatchAggregated
+	batch.Params = common2.StoreParams{}
+	batch.StoreTxHash = common.Hash{}
+	batch.DataStore = nil
+	batch.Attempts = 0
+	return true
+}
+
+// advanceRollupBatch moves the batch through disperse, graph indexing and
+// confirm, persisting it after every step
+func (d *Driver) advanceRollupBatch(batch *db.RollupBatch) error {
+	for batch.Status != db.BatchConfirmed {
+		switch batch.Status {
+		case db.BatchAggregated:
+			params, receipt, err := d.DisperseStoreData(batch.Data, batch.StartL2BlockNumber, batch.EndL2BlockNumber, false)
+			if err != nil {
+				return fmt.Errorf("disperse store data fail: %w", err)
+			}
+			batch.Params = params
+			batch.StoreTxHash = receipt.TxHash
+			d.addL1GasCost(batch, receipt)
+			batch.Status = db.BatchDispersed
+			d.Cfg.Metrics.L2StoredBlockNumber().Set(float64(batch.StartL2BlockNumber.Uint64()))
+		case db.BatchDispersed:
+			event, err := d.PollDataStore(batch.StoreTxHash.Bytes())
+			if err != nil {
+				return d.failRollupBatch(batch, fmt.Errorf("data store of tx %s is not indexed by graph node: %w", batch.StoreTxHash, err))
+			}
+			batch.DataStore = event
+			batch.Status = db.BatchGraphIndexed
+		case db.BatchGraphIndexed:
+			receipt, err := d.ConfirmStoredData(batch.DataStore, batch.Params, batch.StartL2BlockNumber, batch.EndL2BlockNumber, 0, big.NewInt(0), false)
+			if err != nil {
+				return d.failRollupBatch(batch, fmt.Errorf("confirm store data fail: %w", err))
+			}
+			batch.ConfirmTxHash = receipt.TxHash
+			d.addL1GasCost(batch, receipt)
+			batch.Status = db.BatchConfirmed
+			d.Cfg.Metrics.L2ConfirmedBlockNumber().Set(float64(batch.StartL2BlockNumber.Uint64()))
+		default:
+			return fmt.Errorf("unknown rollup batch status %d", batch.Status)
+		}
+		batch.Attempts = 0
+		if !d.LevelDBStore.SetRollupBatch(batch) {
+			return fmt.Errorf("persist %s rollup batch fail", batch.Status)
+		}
+		log.Info("MtBatcher rollup batch status updated", "status", batch.Status, "start", batch.StartL2BlockNumber, "end", batch.EndL2BlockNumber, "storeTxHash", batch.StoreTxHash)
+	}
+	return nil
+}
+
+// failRollupBatch persists the failed attempt of the batch and returns err
+func (d *Driver) failRollupBatch(batch *db.RollupBatch, err error) error {
+	if retryRollupBatch(batch) {
+		log.Warn("MtBatcher give up the data store, disperse the rollup batch again", "start", batch.StartL2BlockNumber, "end", batch.EndL2BlockNumber, "err", err)
+	}
+	if !d.LevelDBStore.SetRollupBatch(batch) {
+		log.Error("MtBatcher persist failed rollup batch fail", "status", batch.Status, "attempts", batch.Attempts)
+	}
+	return err
+}
+
 func (d *Driver) RollupMainWorker() {
 	defer d.wg.Done()
 	ticker := time.NewTicker(d.Cfg.MainWorkerPollInterval)
@@ -801,42 +1049,29 @@ func (d *Driver) RollupMainWorker() {
 	for {
 		select {
 		case <-ticker.C:
-			start, end, err := d.GetBatchBlockRangeWithTimeout(d.Ctx)
+			batch, err := d.loadRollupBatch()
 			if err != nil {
-				log.Warn("MtBatcher Sequencer unable to get block range", "err", err)
+				log.Warn("MtBatcher Sequencer unable to get rollup batch", "err", err)
 				continue
 			}
-			log.Info("MtBatcher get batch block range", "start", start, "end", end)
-			aggregateTxData, startL2BlockNumber, endL2BlockNumber := d.TxAggregator(
-				d.Ctx, start, end,
-			)
-			if err != nil {
-				log.Error("MtBatcher eigenDa sequencer unable to craft batch tx", "err", err)
-				continue
-			}
-			d.Cfg.Metrics.NumTxnPerBatch().Observe(float64((new(big.Int).Sub(endL2BlockNumber, startL2BlockNumber)).Uint64()))
-			d.Cfg.Metrics.BatchSizeBytes().Observe(float64(len(aggregateTxData)))
-			params, receipt, err := d.DisperseStoreData(aggregateTxData, startL2BlockNumber, endL2BlockNumber, false)
-			if err != nil {
-				log.Error("MtBatcher disperse store data fail", "err", err)
-				continue
-			}
-			d.Cfg.Metrics.L2StoredBlockNumber().Set(float64(start.Uint64()))
-			time.Sleep(10 * time.Second) // sleep for data into graph node
-			csdReceipt, err := d.ConfirmStoredData(receipt.TxHash.Bytes(), params, startL2BlockNumber, endL2BlockNumber, 0, big.NewInt(0), false)
-			if err != nil {
-				log.Error("MtBatcher confirm store data fail", "err", err)
+			if err := d.advanceRollupBatch(batch); err != nil {
+				log.Error("MtBatcher advance rollup batch fail", "status", batch.Status, "err", err)
 				continue
 			}
-			log.Debug("MtBatcher confirm store data success", "txHash", csdReceipt.TxHash.String())
-			d.Cfg.Metrics.L2ConfirmedBlockNumber().Set(float64(start.Uint64()))
+			log.Debug("MtBatcher confirm store data success", "txHash", batch.ConfirmTxHash.String())
 			if d.Cfg.FeeModelEnable {
-				daFee, _ := d.CalcUserFeeByRules(big.NewInt(int64(len(aggregateTxData))))
-				feePip := &FeePipline{
-					RollUpFee:        daFee,
-					EndL2BlockNumber: endL2BlockNumber,
+				d.FeeEstimator.Add(DaCostSample{
+					EndL2BlockNumber: batch.EndL2BlockNumber,
+					DataSize:         uint64(len(batch.Data)),
+					Duration:         d.Cfg.DataStoreDuration,
+					L1GasCost:        batch.L1GasCost,
+				})
+				// never block the rollup loop on the fee worker, a pending
+				// signal already makes it read the latest estimate
+				select {
+				case d.feeSignal <- struct{}{}:
+				default:
 				}
-				d.FeeCh <- feePip
 			}
 			batchIndex, _ := d.Cfg.EigenDaContract.RollupBatchIndex(&bind.CallOpts{})
 			d.Cfg.Metrics.RollUpBatchIndex().Set(float64(batchIndex.Uint64()))
@@ -855,24 +1090,9 @@ func (d *Driver) RollUpFeeWorker() {
 	for {
 		select {
 		case <-ticker.C:
-			if d.Cfg.FeeModelEnable {
-				chainFee, err := d.Cfg.EigenFeeContract.GetRollupFee(&bind.CallOpts{})
-				if err != nil {
-					log.Error("MtBatcher RollUpFeeWorker get chain fee fail", "err", err)
-					continue
-				}
-				daFee := <-d.FeeCh
-				log.Debug("MtBatcher RollUpFeeWorker chainFee and daFee", "chainFee", chainFee, "daFee", *daFee)
-				if chainFee.Cmp(daFee.RollUpFee) != 0 {
-					txfRpt, err := d.UpdateUserDaFee(daFee.EndL2BlockNumber, daFee.RollUpFee)
-					if err != nil {
-						log.Error("MtBatcher RollUpFeeWorker update user da fee fail", "err", err)
-						continue
-					}
-					d.Cfg.Metrics.EigenUserFee().Set(float64(daFee.RollUpFee.Uint64()))
-					log.Debug("MtBatcher RollUpFeeWorker update user fee success", "Hash", txfRpt.TxHash.String())
-				}
-			}
+			d.updateRollupFee()
+		case <-d.feeSignal:
+			d.updateRollupFee()
 		case err := <-d.Ctx.Done():
 			log.Error("MtBatcher RollUpFeeWorker eigenDa sequencer service shutting down", "err", err)
 			return
@@ -880,6 +1100,31 @@ func (d *Driver) RollUpFeeWorker() {
 	}
 }
 
+// updateRollupFee pushes the current fee estimate on chain when it differs
+// significantly from the fee users are charged now
+func (d *Driver) updateRollupFee() {
+	daFee, endL2BlockNumber, ok := d.FeeEstimator.Estimate()
+	if !ok {
+		return
+	}
+	chainFee, err := d.Cfg.EigenFeeContract.GetRollupFee(&bind.CallOpts{})
+	if err != nil {
+		log.Error("MtBatcher RollUpFeeWorker get chain fee fail", "err", err)
+		return
+	}
+	log.Debug("MtBatcher RollUpFeeWorker chainFee and daFee", "chainFee", chainFee, "daFee", daFee, "endL2BlockNumber", endL2BlockNumber)
+	if !isDifferenceSignificant(chainFee, daFee, d.Cfg.FeeSignificanceFactor) {
+		return
+	}
+	txfRpt, err := d.UpdateUserDaFee(endL2BlockNumber, daFee)
+	if err != nil {
+		log.Error("MtBatcher RollUpFeeWorker update user da fee fail", "err", err)
+		return
+	}
+	d.Cfg.Metrics.EigenUserFee().Set(float64(daFee.Uint64()))
+	log.Debug("MtBatcher RollUpFeeWorker update user fee success", "Hash", txfRpt.TxHash.String())
+}
+
 func (d *Driver) CheckConfirmedWorker() {
 	defer d.wg.Done()
 	ticker := time.NewTicker(d.Cfg.CheckerWorkerPollInterval)
@@ -926,10 +1171,11 @@ func (d *Driver) CheckConfirmedWorker() {
 					}
 					log.Debug("Checker DataStoreIdToL2RollUpBlock", "rollupBlock.StartL2BlockNumber", rollupBlock.StartL2BlockNumber, "rollupBlock.EndBL2BlockNumber", rollupBlock.EndBL2BlockNumber)
 
-					aggregateTxData, startL2BlockNumber, endL2BlockNumber := d.TxAggregator(
+					aggregateTxData, startL2BlockNumber, endL2BlockNumber, err := d.TxAggregator(
 						d.Ctx, rollupBlock.StartL2BlockNumber, rollupBlock.EndBL2BlockNumber,
 					)
 					if err != nil {
+						d.recordAggregateError(err)
 						log.Error("Checker eigenDa sequencer unable to craft batch tx", "err", err)
 						continue
 					}
@@ -940,8 +1186,12 @@ func (d *Driver) CheckConfirmedWorker() {
 						log.Error("Checker disperse store data fail", "err", err)
 						continue
 					}
-					time.Sleep(10 * time.Second) // sleep for data into graph node
-					csdReceipt, err := d.ConfirmStoredData(receipt.TxHash.Bytes(), params, startL2BlockNumber, endL2BlockNumber, rollupStore.DataStoreId, reConfirmedBatchIndex, true)
+					event, err := d.PollDataStore(receipt.TxHash.Bytes())
+					if err != nil {
+						log.Error("Checker data store is not indexed by graph node", "txHash", receipt.TxHash, "err", err)
+						continue
+					}
+					csdReceipt, err := d.ConfirmStoredData(event, params, startL2BlockNumber, endL2BlockNumber, rollupStore.DataStoreId, reConfirmedBatchIndex, true)
 					if err != nil {
 						log.Error("Checker confirm store data fail", "err", err)
 						continue
@@ -0,0 +1,63 @@
+package sequencer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/datalayr/common/graphView"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mantlenetworkio/mantle/mt-batcher/services/sequencer/db"
+)
+
+func TestResumeRollupBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    db.BatchStatus
+		confirmed int64
+		resume    bool
+		after     db.BatchStatus
+	}{
+		{name: "aggregated batch is resumed", status: db.BatchAggregated, confirmed: 10, resume: true, after: db.BatchAggregated},
+		{name: "stale aggregated batch is dropped", status: db.BatchAggregated, confirmed: 8, after: db.BatchAggregated},
+		{name: "dispersed batch is resumed", status: db.BatchDispersed, confirmed: 10, resume: true, after: db.BatchDispersed},
+		{name: "dispersed batch behind the chain is resumed", status: db.BatchDispersed, confirmed: 8, resume: true, after: db.BatchDispersed},
+		{name: "graph indexed batch is resumed", status: db.BatchGraphIndexed, confirmed: 10, resume: true, after: db.BatchGraphIndexed},
+		{name: "batch confirmed on chain", status: db.BatchDispersed, confirmed: 20, after: db.BatchConfirmed},
+		{name: "chain ahead of the batch", status: db.BatchAggregated, confirmed: 25, after: db.BatchConfirmed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := &db.RollupBatch{Status: tt.status, StartL2BlockNumber: big.NewInt(10), EndL2BlockNumber: big.NewInt(20)}
+			require.Equal(t, tt.resume, resumeRollupBatch(batch, big.NewInt(tt.confirmed)))
+			require.Equal(t, tt.after, batch.Status)
+		})
+	}
+}
+
+func TestRetryRollupBatch(t *testing.T) {
+	batch := &db.RollupBatch{
+		Status:             db.BatchGraphIndexed,
+		StartL2BlockNumber: big.NewInt(10),
+		EndL2BlockNumber:   big.NewInt(20),
+		Data:               []byte{1, 2, 3},
+		StoreTxHash:        common.HexToHash("0x01"),
+		DataStore:          &graphView.DataStore{StoreNumber: 7},
+	}
+	for i := 1; i < maxConfirmAttempts; i++ {
+		require.False(t, retryRollupBatch(batch))
+		require.Equal(t, i, batch.Attempts)
+		require.Equal(t, db.BatchGraphIndexed, batch.Status)
+	}
+
+	// the data store is given up, the range is dispersed again
+	require.True(t, retryRollupBatch(batch))
+	require.Equal(t, db.BatchAggregated, batch.Status)
+	require.Zero(t, batch.Attempts)
+	require.Nil(t, batch.DataStore)
+	require.Empty(t, batch.StoreTxHash)
+	require.Equal(t, []byte{1, 2, 3}, batch.Data)
+	require.Equal(t, big.NewInt(10), batch.StartL2BlockNumber)
+}
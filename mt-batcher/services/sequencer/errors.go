@@ -0,0 +1,62 @@
+package sequencer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	ErrFetchBlock         = errors.New("unable to fetch l2 block")
+	ErrUnexpectedTxCount  = errors.New("l2 block does not contain exactly one tx")
+	ErrEncodeBatch        = errors.New("unable to encode batch")
+	ErrEmptyBatch         = errors.New("batch contains no tx")
+	ErrUnexpectedBlockNum = errors.New("l2 returned a block with unexpected number")
+)
+
+// aggregateErrorReasons are the metric labels of the TxAggregator errors
+var aggregateErrorReasons = map[error]string{
+	ErrFetchBlock:         "fetch_block",
+	ErrUnexpectedTxCount:  "unexpected_tx_count",
+	ErrEncodeBatch:        "encode_batch",
+	ErrEmptyBatch:         "empty_batch",
+	ErrUnexpectedBlockNum: "unexpected_block_number",
+}
+
+// AggregateError is returned by TxAggregator when no batch can be built for
+// the requested range, BlockNumber is the l2 block it stopped at
+type AggregateError struct {
+	BlockNumber *big.Int
+	Kind        error
+	Cause       error
+}
+
+func (e *AggregateError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("l2 block %v: %v: %v", e.BlockNumber, e.Kind, e.Cause)
+	}
+	return fmt.Sprintf("l2 block %v: %v", e.BlockNumber, e.Kind)
+}
+
+func (e *AggregateError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *AggregateError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *AggregateError) reason() string {
+	if reason, ok := aggregateErrorReasons[e.Kind]; ok {
+		return reason
+	}
+	return "unknown"
+}
+
+func newAggregateError(blockNumber *big.Int, kind, cause error) *AggregateError {
+	return &AggregateError{
+		BlockNumber: new(big.Int).Set(blockNumber),
+		Kind:        kind,
+		Cause:       cause,
+	}
+}
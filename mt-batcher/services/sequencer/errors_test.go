@@ -0,0 +1,102 @@
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
+)
+
+func TestAggregateError(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []struct {
+		name   string
+		kind   error
+		cause  error
+		msg    string
+		reason string
+	}{
+		{name: "fetch block", kind: ErrFetchBlock, cause: cause, msg: "l2 block 7: unable to fetch l2 block: connection refused", reason: "fetch_block"},
+		{name: "tx count", kind: ErrUnexpectedTxCount, cause: fmt.Errorf("found 2 txs"), msg: "l2 block 7: l2 block does not contain exactly one tx: found 2 txs", reason: "unexpected_tx_count"},
+		{name: "encode", kind: ErrEncodeBatch, cause: cause, msg: "l2 block 7: unable to encode batch: connection refused", reason: "encode_batch"},
+		{name: "empty batch", kind: ErrEmptyBatch, msg: "l2 block 7: batch contains no tx", reason: "empty_batch"},
+		{name: "block number", kind: ErrUnexpectedBlockNum, cause: fmt.Errorf("got block 8"), msg: "l2 block 7: l2 returned a block with unexpected number: got block 8", reason: "unexpected_block_number"},
+		{name: "unknown kind", kind: errors.New("other"), msg: "l2 block 7: other", reason: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			number := big.NewInt(7)
+			err := newAggregateError(number, tt.kind, tt.cause)
+			number.SetInt64(8)
+			require.Equal(t, big.NewInt(7), err.BlockNumber)
+			require.EqualError(t, err, tt.msg)
+			require.Equal(t, tt.reason, err.reason())
+
+			wrapped := fmt.Errorf("rollup: %w", err)
+			require.ErrorIs(t, wrapped, tt.kind)
+			if tt.cause != nil {
+				require.ErrorIs(t, wrapped, tt.cause)
+			}
+			for kind := range aggregateErrorReasons {
+				if kind != tt.kind {
+					require.False(t, errors.Is(wrapped, kind), "%v is %v", tt.kind, kind)
+				}
+			}
+			var aggErr *AggregateError
+			require.True(t, errors.As(wrapped, &aggErr))
+			require.Same(t, err, aggErr)
+		})
+	}
+}
+
+func TestRetryBlockFetch(t *testing.T) {
+	block := l2types.NewBlockWithHeader(&l2types.Header{Number: big.NewInt(7)})
+	unavailable := errors.New("l2geth unavailable")
+	tests := []struct {
+		name     string
+		errs     []error
+		attempts int
+		err      error
+	}{
+		{name: "first attempt", attempts: 1},
+		{name: "transient errors", errs: []error{unavailable, unavailable}, attempts: 3},
+		{name: "retries exhausted", errs: []error{unavailable, unavailable, unavailable, unavailable, unavailable}, attempts: blockFetchRetries, err: unavailable},
+		{name: "canceled", errs: []error{context.Canceled}, attempts: 1, err: context.Canceled},
+		{name: "timed out", errs: []error{unavailable, fmt.Errorf("rpc: %w", context.DeadlineExceeded)}, attempts: 2, err: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			fetch := func(ctx context.Context, number *big.Int) (*l2types.Block, error) {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return nil, tt.errs[attempts-1]
+				}
+				return block, nil
+			}
+			got, err := retryBlockFetch(context.Background(), big.NewInt(7), time.Millisecond, fetch)
+			require.Equal(t, tt.attempts, attempts)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				require.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.Same(t, block, got)
+		})
+	}
+
+	// the backoff is cut short once the context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := retryBlockFetch(ctx, big.NewInt(7), time.Hour, func(context.Context, *big.Int) (*l2types.Block, error) {
+		return nil, unavailable
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,111 @@
+package sequencer
+
+import (
+	"math"
+	"math/big"
+	"sync"
+)
+
+// DaCostSample is what rolling up a single batch cost
+type DaCostSample struct {
+	EndL2BlockNumber *big.Int
+	DataSize         uint64
+	Duration         uint64
+	// L1GasCost is the wei spent on the StoreData and ConfirmData txs
+	L1GasCost *big.Int
+}
+
+type FeeEstimatorConfig struct {
+	// WindowSize is the number of recent batches the estimate is taken over
+	WindowSize int
+	// Smoothing is the weight of the newest window estimate in the
+	// exponential moving average, 1 disables smoothing
+	Smoothing float64
+	// FeePerBytePerTime is the eigen da cost in wei of storing a byte for one
+	// unit of store duration
+	FeePerBytePerTime uint64
+}
+
+// FeeEstimator estimates the da fee in wei per byte over a moving window of
+// recent batches. It is fed by the rollup loop and read by the fee worker, so every
+// method is safe for concurrent use.
+type FeeEstimator struct {
+	cfg FeeEstimatorConfig
+
+	mu       sync.Mutex
+	samples  []DaCostSample
+	smoothed float64
+	endBlock *big.Int
+}
+
+func NewFeeEstimator(cfg FeeEstimatorConfig) *FeeEstimator {
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = 1
+	}
+	if cfg.Smoothing <= 0 || cfg.Smoothing > 1 {
+		cfg.Smoothing = 1
+	}
+	return &FeeEstimator{cfg: cfg}
+}
+
+// Add records the cost of a batch and updates the estimate
+func (e *FeeEstimator) Add(sample DaCostSample) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.samples = append(e.samples, sample)
+	if len(e.samples) > e.cfg.WindowSize {
+		e.samples = e.samples[len(e.samples)-e.cfg.WindowSize:]
+	}
+	raw, ok := e.windowFee()
+	if !ok {
+		return
+	}
+	if e.endBlock == nil {
+		e.smoothed = raw
+	} else {
+		e.smoothed = e.cfg.Smoothing*raw + (1-e.cfg.Smoothing)*e.smoothed
+	}
+	e.endBlock = sample.EndL2BlockNumber
+}
+
+// windowFee is the total cost of the window divided by the bytes it stored
+func (e *FeeEstimator) windowFee() (float64, bool) {
+	var size float64
+	cost := new(big.Float)
+	for _, s := range e.samples {
+		size += float64(s.DataSize)
+		if s.L1GasCost != nil {
+			cost.Add(cost, new(big.Float).SetInt(s.L1GasCost))
+		}
+		daCost := float64(s.DataSize) * float64(s.Duration) * float64(e.cfg.FeePerBytePerTime)
+		cost.Add(cost, big.NewFloat(daCost))
+	}
+	if size == 0 {
+		return 0, false
+	}
+	total, _ := cost.Float64()
+	return total / size, true
+}
+
+// Estimate returns the smoothed da fee in wei per byte and the last l2 block it
+// covers, ok is false until the first batch has been recorded
+func (e *FeeEstimator) Estimate() (fee *big.Int, endL2BlockNumber *big.Int, ok bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.endBlock == nil {
+		return nil, nil, false
+	}
+	fee, _ = big.NewFloat(math.Ceil(e.smoothed)).Int(nil)
+	return fee, new(big.Int).Set(e.endBlock), true
+}
+
+// isDifferenceSignificant reports whether next differs from current by more
+// than factor, relative to current
+func isDifferenceSignificant(current, next *big.Int, factor float64) bool {
+	if current.Sign() == 0 {
+		return next.Sign() != 0
+	}
+	diff := new(big.Float).SetInt(new(big.Int).Abs(new(big.Int).Sub(current, next)))
+	rel, _ := new(big.Float).Quo(diff, new(big.Float).SetInt(current)).Float64()
+	return rel > factor
+}
@@ -0,0 +1,89 @@
+package sequencer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeEstimator(t *testing.T) {
+	e := NewFeeEstimator(FeeEstimatorConfig{WindowSize: 2, Smoothing: 1, FeePerBytePerTime: 2})
+	_, _, ok := e.Estimate()
+	require.False(t, ok)
+
+	// (1000 + 100*5*2) / 100
+	e.Add(DaCostSample{EndL2BlockNumber: big.NewInt(10), DataSize: 100, Duration: 5, L1GasCost: big.NewInt(1000)})
+	fee, end, ok := e.Estimate()
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(20), fee)
+	require.Equal(t, big.NewInt(10), end)
+
+	// (2000 + 1001 + 300*5*2) / 400, rounded up
+	e.Add(DaCostSample{EndL2BlockNumber: big.NewInt(20), DataSize: 300, Duration: 5, L1GasCost: big.NewInt(1001)})
+	fee, end, _ = e.Estimate()
+	require.Equal(t, big.NewInt(16), fee)
+	require.Equal(t, big.NewInt(20), end)
+
+	// The first sample leaves the window, a missing gas cost counts as 0:
+	// (4001 + 100*5*2) / 400, rounded up
+	e.Add(DaCostSample{EndL2BlockNumber: big.NewInt(30), DataSize: 100, Duration: 5})
+	fee, end, _ = e.Estimate()
+	require.Equal(t, big.NewInt(13), fee)
+	require.Equal(t, big.NewInt(30), end)
+}
+
+func TestFeeEstimatorSmoothing(t *testing.T) {
+	e := NewFeeEstimator(FeeEstimatorConfig{WindowSize: 1, Smoothing: 0.5})
+	e.Add(DaCostSample{EndL2BlockNumber: big.NewInt(1), DataSize: 10, L1GasCost: big.NewInt(1000)})
+	fee, _, _ := e.Estimate()
+	require.Equal(t, big.NewInt(100), fee)
+
+	// Half of the new window estimate of 200
+	e.Add(DaCostSample{EndL2BlockNumber: big.NewInt(2), DataSize: 10, L1GasCost: big.NewInt(2000)})
+	fee, _, _ = e.Estimate()
+	require.Equal(t, big.NewInt(150), fee)
+
+	// Empty batches do not move the estimate
+	e.Add(DaCostSample{EndL2BlockNumber: big.NewInt(3)})
+	fee, end, _ := e.Estimate()
+	require.Equal(t, big.NewInt(150), fee)
+	require.Equal(t, big.NewInt(2), end)
+}
+
+func TestNewFeeEstimatorDefaults(t *testing.T) {
+	for _, cfg := range []FeeEstimatorConfig{
+		{WindowSize: 0, Smoothing: 0},
+		{WindowSize: -1, Smoothing: 1.5},
+	} {
+		e := NewFeeEstimator(cfg)
+		require.Equal(t, 1, e.cfg.WindowSize)
+		require.Equal(t, float64(1), e.cfg.Smoothing)
+	}
+}
+
+func TestIsDifferenceSignificant(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int64
+		next    int64
+		factor  float64
+		want    bool
+	}{
+		{"equal", 100, 100, 0.05, false},
+		{"within factor", 100, 104, 0.05, false},
+		{"at factor", 100, 105, 0.05, false},
+		{"above factor", 100, 106, 0.05, true},
+		{"decrease above factor", 100, 90, 0.05, true},
+		{"zero factor", 100, 101, 0, true},
+		{"from zero", 0, 1, 0.05, true},
+		{"zero to zero", 0, 0, 0.05, false},
+		{"to zero", 100, 0, 0.05, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isDifferenceSignificant(big.NewInt(tt.current), big.NewInt(tt.next), tt.factor)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package sequencer
+
+import (
+	"context"
+	"math/big"
+	"math/bits"
+
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
+)
+
+const defaultBlockFetchConcurrency = 8
+
+type blockResult struct {
+	block *l2types.Block
+	err   error
+}
+
+// prefetchBlocks fetches the l2 blocks in [start, end) with at most
+// concurrency requests in flight. Every element of the returned channel is
+// the result of the next block in order, cancel ctx to stop fetching early.
+func prefetchBlocks(ctx context.Context, start, end *big.Int, concurrency int, fetch func(context.Context, *big.Int) (*l2types.Block, error)) <-chan chan blockResult {
+	if concurrency <= 0 {
+		concurrency = defaultBlockFetchConcurrency
+	}
+	// the block the consumer waits for is in flight too
+	pending := make(chan chan blockResult, concurrency-1)
+	go func() {
+		defer close(pending)
+		for i := new(big.Int).Set(start); i.Cmp(end) < 0; i.Add(i, bigOne) {
+			result := make(chan blockResult, 1)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+			go func(number *big.Int) {
+				block, err := fetch(ctx, number)
+				result <- blockResult{block: block, err: err}
+			}(new(big.Int).Set(i))
+		}
+	}()
+	return pending
+}
+
+// rlpListSize returns the length of an rlp list whose items are contentSize
+// bytes long once encoded
+func rlpListSize(contentSize uint64) uint64 {
+	if contentSize < 56 {
+		return 1 + contentSize
+	}
+	return 1 + uint64((bits.Len64(contentSize)+7)/8) + contentSize
+}
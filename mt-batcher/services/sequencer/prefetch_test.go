@@ -0,0 +1,121 @@
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
+	l2rlp "github.com/mantlenetworkio/mantle/l2geth/rlp"
+)
+
+func TestRlpListSize(t *testing.T) {
+	for _, size := range []int{0, 1, 54, 55, 56, 200, 255, 256, 1 << 16, 1<<16 + 1, 1 << 24} {
+		// the content of a list with a single item is the encoded item
+		item := make([]byte, size)
+		encodedItem, err := l2rlp.EncodeToBytes(item)
+		require.NoError(t, err)
+		encodedList, err := l2rlp.EncodeToBytes([][]byte{item})
+		require.NoError(t, err)
+		require.Equal(t, uint64(len(encodedList)), rlpListSize(uint64(len(encodedItem))), "item size %d", size)
+	}
+	require.Equal(t, uint64(1), rlpListSize(0))
+	require.Equal(t, uint64(56), rlpListSize(55))
+	require.Equal(t, uint64(58), rlpListSize(56))
+	require.Equal(t, uint64(259), rlpListSize(256))
+}
+
+// testFetcher hands out blocks once released and records how many fetches
+// run at once
+type testFetcher struct {
+	release  chan struct{}
+	inFlight int32
+	max      int32
+	started  int32
+	fail     *big.Int
+}
+
+func (f *testFetcher) fetch(ctx context.Context, number *big.Int) (*l2types.Block, error) {
+	atomic.AddInt32(&f.started, 1)
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&f.max)
+		if n <= max || atomic.CompareAndSwapInt32(&f.max, max, n) {
+			break
+		}
+	}
+	select {
+	case <-f.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if f.fail != nil && f.fail.Cmp(number) == 0 {
+		return nil, errors.New("fetch failed")
+	}
+	return l2types.NewBlockWithHeader(&l2types.Header{Number: new(big.Int).Set(number)}), nil
+}
+
+func TestPrefetchBlocksWindow(t *testing.T) {
+	const concurrency = 3
+	f := &testFetcher{release: make(chan struct{})}
+	blocks := prefetchBlocks(context.Background(), big.NewInt(10), big.NewInt(20), concurrency, f.fetch)
+
+	// the window is filled before the first block is read
+	first := <-blocks
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&f.started) == concurrency }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	require.EqualValues(t, concurrency, atomic.LoadInt32(&f.started))
+
+	// blocks come out in order whatever order they are fetched in, every
+	// result is waited for before the next one is taken like TxAggregator does
+	close(f.release)
+	fetched := <-first
+	require.NoError(t, fetched.err)
+	require.Equal(t, big.NewInt(10), fetched.block.Number())
+	next := int64(11)
+	for result := range blocks {
+		fetched := <-result
+		require.NoError(t, fetched.err)
+		require.Equal(t, big.NewInt(next), fetched.block.Number())
+		next++
+	}
+	require.Equal(t, int64(20), next)
+	require.LessOrEqual(t, atomic.LoadInt32(&f.max), int32(concurrency))
+}
+
+func TestPrefetchBlocksCancel(t *testing.T) {
+	f := &testFetcher{release: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	blocks := prefetchBlocks(ctx, big.NewInt(0), big.NewInt(1000), 2, f.fetch)
+	first := <-blocks
+	cancel()
+
+	fetched := <-first
+	require.ErrorIs(t, fetched.err, context.Canceled)
+	var drained int
+	for range blocks {
+		drained++
+	}
+	require.LessOrEqual(t, drained, 1)
+	require.LessOrEqual(t, atomic.LoadInt32(&f.started), int32(2))
+}
+
+func TestPrefetchBlocksError(t *testing.T) {
+	f := &testFetcher{release: make(chan struct{}), fail: big.NewInt(2)}
+	close(f.release)
+	blocks := prefetchBlocks(context.Background(), big.NewInt(0), big.NewInt(4), 0, f.fetch)
+	var errs []error
+	for result := range blocks {
+		errs = append(errs, (<-result).err)
+	}
+	require.Len(t, errs, 4)
+	require.NoError(t, errs[1])
+	require.Error(t, errs[2])
+	require.NoError(t, errs[3])
+}
@@ -0,0 +1,256 @@
+package challenger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Layr-Labs/datalayr/common/graphView"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/mantle/mt-challenger/challenger/db"
+)
+
+// rollupStoreReverted is RollupStoreStatus.REVERTED of the eigen da contract
+const rollupStoreReverted = 2
+
+// recordFraud stores a newly detected fraud and wakes up the challenge loop,
+// a batch that is already challenged keeps its existing record
+func (c *Challenger) recordFraud(batchIndex uint64, store *graphView.DataStore, fraud *Fraud) bool {
+	if _, ok := c.LevelDBStore.GetChallenge(batchIndex); ok {
+		return true
+	}
+	now := time.Now().Unix()
+	challenge := &db.Challenge{
+		BatchIndex:    batchIndex,
+		StoreNumber:   store.StoreNumber,
+		L2BlockNumber: fraud.BlockNumber,
+		Reason:        fraud.Reason,
+		StartingIndex: fraud.StartingIndex,
+		EndingIndex:   fraud.EndingIndex,
+		Status:        db.ChallengeDetected,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if !c.LevelDBStore.SetChallenge(challenge) {
+		return false
+	}
+	select {
+	case c.challengeSignal <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (c *Challenger) challengeLoop() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.Cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.processChallenges()
+		case <-c.challengeSignal:
+			c.processChallenges()
+		case err := <-c.Ctx.Done():
+			log.Error("MtChallenger challenge loop shutting down", "err", err)
+			return
+		}
+	}
+}
+
+// processChallenges moves every open challenge one step forward, it is only
+// ever run by the challenge loop so a proof is never posted twice
+func (c *Challenger) processChallenges() {
+	challenges, err := c.LevelDBStore.ListChallenges()
+	if err != nil {
+		log.Error("MtChallenger list challenges fail", "err", err)
+		return
+	}
+	var open int
+	for _, challenge := range challenges {
+		if !challenge.Status.Open() {
+			continue
+		}
+		if err := c.advanceChallenge(challenge); err != nil {
+			log.Error("MtChallenger advance challenge fail", "batchIndex", challenge.BatchIndex, "status", challenge.Status, "err", err)
+		}
+		if !c.LevelDBStore.SetChallenge(challenge) {
+			log.Error("MtChallenger persist challenge fail", "batchIndex", challenge.BatchIndex)
+		}
+		if challenge.Status.Open() {
+			open++
+		}
+	}
+	c.Cfg.Metrics.OpenChallenges().Set(float64(open))
+}
+
+func (c *Challenger) advanceChallenge(challenge *db.Challenge) error {
+	rollupStore, err := c.EigenDaContract.GetRollupStoreByRollupBatchIndex(&bind.CallOpts{}, new(big.Int).SetUint64(challenge.BatchIndex))
+	if err != nil {
+		return fmt.Errorf("get rollup store: %w", err)
+	}
+	header, err := c.Cfg.L1Client.HeaderByNumber(c.Ctx, nil)
+	if err != nil {
+		return fmt.Errorf("get latest l1 header: %w", err)
+	}
+	if !c.stepChallenge(challenge, rollupStore.Status == rollupStoreReverted, uint64(rollupStore.ConfirmAt), header, time.Now().Unix()) {
+		return nil
+	}
+	challenge.Attempts++
+	receipt, err := c.proveChallenge(challenge)
+	if err != nil {
+		c.setChallengeStatus(challenge, db.ChallengeProofFailed, err.Error())
+		return err
+	}
+	return c.recordProofReceipt(challenge, receipt)
+}
+
+// stepChallenge moves the challenge according to the rollup store state and
+// the latest L1 header, it reports whether a new proof has to be posted
+func (c *Challenger) stepChallenge(challenge *db.Challenge, reverted bool, confirmAt uint64, header *types.Header, now int64) bool {
+	if reverted {
+		c.setChallengeStatus(challenge, db.ChallengeResolved, "")
+		log.Info("MtChallenger challenge resolved", "batchIndex", challenge.BatchIndex, "storeNumber", challenge.StoreNumber)
+		return false
+	}
+	if challenge.Status == db.ChallengeProofSubmitted {
+		var confirmations uint64
+		if head := header.Number.Uint64(); head > challenge.ProofBlock {
+			confirmations = head - challenge.ProofBlock
+		}
+		if confirmations < c.Cfg.ProofConfirmations && now-challenge.ProofMinedAt < int64(c.Cfg.ProofTimeout.Seconds()) {
+			return false
+		}
+		// the proof was mined but the store is still committed, the contract
+		// rejected it without reverting or it was reorged out
+		c.setChallengeStatus(challenge, db.ChallengeProofFailed, fmt.Sprintf("rollup store not reverted %d blocks after the proof tx", confirmations))
+	}
+	if header.Time >= confirmAt {
+		c.setChallengeStatus(challenge, db.ChallengeAbandoned, "fraud proof period is over")
+		log.Error("MtChallenger fraud proof period is over, abandon challenge", "batchIndex", challenge.BatchIndex, "storeNumber", challenge.StoreNumber)
+		return false
+	}
+	if challenge.Attempts >= c.Cfg.MaxChallengeAttempts {
+		c.setChallengeStatus(challenge, db.ChallengeAbandoned, fmt.Sprintf("gave up after %d attempts: %s", challenge.Attempts, challenge.LastError))
+		log.Error("MtChallenger too many failed proof attempts, abandon challenge", "batchIndex", challenge.BatchIndex, "attempts", challenge.Attempts)
+		return false
+	}
+	return true
+}
+
+// recordProofReceipt stores the outcome of a mined proof tx, a successful one
+// waits for the rollup store to be reverted
+func (c *Challenger) recordProofReceipt(challenge *db.Challenge, receipt *types.Receipt) error {
+	challenge.ProofTxHash = receipt.TxHash
+	challenge.ReceiptStatus = receipt.Status
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		c.setChallengeStatus(challenge, db.ChallengeProofFailed, "proof tx reverted")
+		return fmt.Errorf("proof tx %s reverted", receipt.TxHash)
+	}
+	if receipt.BlockNumber != nil {
+		challenge.ProofBlock = receipt.BlockNumber.Uint64()
+	}
+	c.setChallengeStatus(challenge, db.ChallengeProofSubmitted, "")
+	challenge.ProofMinedAt = challenge.UpdatedAt
+	log.Info("MtChallenger fraud proof tx mined", "batchIndex", challenge.BatchIndex, "hash", receipt.TxHash.Hex(), "block", challenge.ProofBlock)
+	return nil
+}
+
+func (c *Challenger) setChallengeStatus(challenge *db.Challenge, status db.ChallengeStatus, lastError string) {
+	challenge.Status = status
+	challenge.LastError = lastError
+	challenge.UpdatedAt = time.Now().Unix()
+}
+
+// proveChallenge retrieves the data store again and posts a fraud proof for
+// the recorded byte range
+func (c *Challenger) proveChallenge(challenge *db.Challenge) (*types.Receipt, error) {
+	store, err := c.getDataStoreById(strconv.FormatUint(uint64(challenge.StoreNumber), 10))
+	if err != nil {
+		return nil, fmt.Errorf("get data store: %w", err)
+	}
+	data, frames, err := c.callRetrieve(store)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve data store: %w", err)
+	}
+	fraud := &Fraud{
+		StartingIndex: challenge.StartingIndex,
+		EndingIndex:   challenge.EndingIndex,
+		BlockNumber:   challenge.L2BlockNumber,
+		Reason:        challenge.Reason,
+	}
+	proof, err := c.constructFraudProof(store, data, fraud, frames)
+	if err != nil {
+		return nil, fmt.Errorf("construct fraud proof: %w", err)
+	}
+	return c.postFraudProof(store, proof)
+}
+
+type challengeResponse struct {
+	*db.Challenge
+	StatusName string `json:"status_name"`
+}
+
+// serveChallenges lists the recorded challenges, ?status=open or
+// ?status=closed restricts the list
+func (c *Challenger) serveChallenges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	filter := r.URL.Query().Get("status")
+	if filter != "" && filter != "open" && filter != "closed" {
+		http.Error(w, "status must be open or closed", http.StatusBadRequest)
+		return
+	}
+	challenges, err := c.LevelDBStore.ListChallenges()
+	if err != nil {
+		log.Error("MtChallenger list challenges fail", "err", err)
+		http.Error(w, "list challenges fail", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]challengeResponse, 0, len(challenges))
+	for _, challenge := range challenges {
+		if (filter == "open" && !challenge.Status.Open()) || (filter == "closed" && challenge.Status.Open()) {
+			continue
+		}
+		resp = append(resp, challengeResponse{Challenge: challenge, StatusName: challenge.Status.String()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("MtChallenger encode challenges fail", "err", err)
+	}
+}
+
+func (c *Challenger) startStatusServer() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/challenges", c.serveChallenges)
+	c.statusServer = &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", c.Cfg.StatusHostname, c.Cfg.StatusPort),
+		Handler: mux,
+	}
+	go func() {
+		if err := c.statusServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("MtChallenger status server fail", "err", err)
+		}
+	}()
+}
+
+func (c *Challenger) stopStatusServer() {
+	if c.statusServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.statusServer.Shutdown(ctx); err != nil {
+		log.Error("MtChallenger status server shutdown fail", "err", err)
+	}
+}
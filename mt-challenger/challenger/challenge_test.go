@@ -0,0 +1,139 @@
+package challenger
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mantlenetworkio/mantle/mt-challenger/challenger/db"
+)
+
+func newTestChallenger() *Challenger {
+	return &Challenger{Cfg: &ChallengerConfig{
+		MaxChallengeAttempts: 3,
+		ProofConfirmations:   12,
+		ProofTimeout:         10 * time.Minute,
+	}}
+}
+
+func testHeader(number, time uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number), Time: time}
+}
+
+func TestStepChallenge(t *testing.T) {
+	const confirmAt = 1000
+	submitted := func() *db.Challenge {
+		return &db.Challenge{Status: db.ChallengeProofSubmitted, Attempts: 1, ProofBlock: 100, ProofMinedAt: 500}
+	}
+	tests := []struct {
+		name      string
+		challenge *db.Challenge
+		reverted  bool
+		header    *types.Header
+		now       int64
+		prove     bool
+		status    db.ChallengeStatus
+	}{
+		{
+			name:      "detected is proven",
+			challenge: &db.Challenge{Status: db.ChallengeDetected},
+			header:    testHeader(100, 900),
+			prove:     true,
+			status:    db.ChallengeDetected,
+		},
+		{
+			name:      "failed proof is retried",
+			challenge: &db.Challenge{Status: db.ChallengeProofFailed, Attempts: 2},
+			header:    testHeader(100, 900),
+			prove:     true,
+			status:    db.ChallengeProofFailed,
+		},
+		{
+			name:      "reverted store resolves",
+			challenge: submitted(),
+			reverted:  true,
+			header:    testHeader(101, 900),
+			status:    db.ChallengeResolved,
+		},
+		{
+			name:      "submitted proof waits for confirmations",
+			challenge: submitted(),
+			header:    testHeader(111, 900),
+			now:       500 + 60,
+			status:    db.ChallengeProofSubmitted,
+		},
+		{
+			name:      "submitted proof waits on a lagging node",
+			challenge: submitted(),
+			header:    testHeader(90, 900),
+			now:       500,
+			status:    db.ChallengeProofSubmitted,
+		},
+		{
+			name:      "submitted proof fails after confirmations",
+			challenge: submitted(),
+			header:    testHeader(112, 900),
+			now:       500 + 60,
+			prove:     true,
+			status:    db.ChallengeProofFailed,
+		},
+		{
+			name:      "submitted proof fails after timeout",
+			challenge: submitted(),
+			header:    testHeader(101, 900),
+			now:       500 + 600,
+			prove:     true,
+			status:    db.ChallengeProofFailed,
+		},
+		{
+			name:      "fraud proof period is over",
+			challenge: &db.Challenge{Status: db.ChallengeDetected},
+			header:    testHeader(100, confirmAt),
+			status:    db.ChallengeAbandoned,
+		},
+		{
+			name:      "submitted proof is not abandoned before confirmations",
+			challenge: submitted(),
+			header:    testHeader(101, confirmAt),
+			now:       500,
+			status:    db.ChallengeProofSubmitted,
+		},
+		{
+			name:      "too many attempts",
+			challenge: &db.Challenge{Status: db.ChallengeProofFailed, Attempts: 3},
+			header:    testHeader(100, 900),
+			status:    db.ChallengeAbandoned,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prove := newTestChallenger().stepChallenge(tt.challenge, tt.reverted, confirmAt, tt.header, tt.now)
+			require.Equal(t, tt.prove, prove)
+			require.Equal(t, tt.status, tt.challenge.Status)
+		})
+	}
+}
+
+func TestRecordProofReceipt(t *testing.T) {
+	c := newTestChallenger()
+	challenge := &db.Challenge{Status: db.ChallengeDetected, Attempts: 1}
+	hash := common.HexToHash("0x01")
+
+	err := c.recordProofReceipt(challenge, &types.Receipt{TxHash: hash, Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(100)})
+	require.Error(t, err)
+	require.Equal(t, db.ChallengeProofFailed, challenge.Status)
+	require.Equal(t, hash, challenge.ProofTxHash)
+
+	require.NoError(t, c.recordProofReceipt(challenge, &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100)}))
+	require.Equal(t, db.ChallengeProofSubmitted, challenge.Status)
+	require.Equal(t, uint64(100), challenge.ProofBlock)
+	require.Equal(t, challenge.UpdatedAt, challenge.ProofMinedAt)
+
+	// The next tick in the same block keeps waiting for the store to revert
+	require.False(t, c.stepChallenge(challenge, false, uint64(time.Now().Unix())+3600, testHeader(100, uint64(time.Now().Unix())), time.Now().Unix()))
+	require.Equal(t, db.ChallengeProofSubmitted, challenge.Status)
+}
@@ -1,12 +1,11 @@
 package challenger
 
 import (
-	"bytes"
 	"context"
 	"crypto/ecdsa"
-	"encoding/hex"
 	"fmt"
 	"math/big"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,7 +16,6 @@ import (
 	"google.golang.org/grpc"
 
 	datalayr "github.com/Layr-Labs/datalayr/common/contracts"
-	gkzg "github.com/Layr-Labs/datalayr/common/crypto/go-kzg-bn254"
 	"github.com/Layr-Labs/datalayr/common/graphView"
 	"github.com/Layr-Labs/datalayr/common/header"
 	pb "github.com/Layr-Labs/datalayr/common/interfaces/interfaceRetrieverServer"
@@ -30,13 +28,9 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/mantlenetworkio/mantle/l2geth/common"
-	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
 	l2ethclient "github.com/mantlenetworkio/mantle/l2geth/ethclient"
-	l2rlp "github.com/mantlenetworkio/mantle/l2geth/rlp"
-	"github.com/mantlenetworkio/mantle/l2geth/rollup/eigenda"
 	common4 "github.com/mantlenetworkio/mantle/mt-batcher/services/common"
 	"github.com/mantlenetworkio/mantle/mt-batcher/txmgr"
 	"github.com/mantlenetworkio/mantle/mt-challenger/bindings"
@@ -46,8 +40,6 @@ import (
 	"github.com/mantlenetworkio/mantle/mt-challenger/metrics"
 )
 
-const fraudString = "2d5f2860204f2060295f2d202d5f2860206f2060295f2d202d5f286020512060295f2d2042495444414f204a5553542052454b5420594f55207c5f2860204f2060295f7c202d207c5f2860206f2060295f7c202d207c5f286020512060295f7c"
-
 type SignerFn func(context.Context, ethc.Address, *types.Transaction) (*types.Transaction, error)
 
 var (
@@ -65,10 +57,20 @@ type KzgConfig struct {
 	TableDir  string
 	NumWorker int
 	Order     uint64 // Order is the total size of SRS
+	// G1Hash and G2Hash are the optional expected sha256 of the SRS files
+	G1Hash string
+	G2Hash string
+	// LazyLoad defers reading the SRS until the first fraud proof
+	LazyLoad bool
 }
 
+// Fraud is the byte range [StartingIndex, EndingIndex) of a data store that
+// does not match the local l2geth chain
 type Fraud struct {
 	StartingIndex int
+	EndingIndex   int
+	BlockNumber   *big.Int
+	Reason        string
 }
 
 type DataLayrDisclosureProof struct {
@@ -110,6 +112,12 @@ type ChallengerConfig struct {
 	ResubmissionTimeout       time.Duration
 	NumConfirmations          uint64
 	SafeAbortNonceTooLowCount uint64
+	MaxChallengeAttempts      int
+	ProofConfirmations        uint64
+	ProofTimeout              time.Duration
+	StatusServerEnable        bool
+	StatusHostname            string
+	StatusPort                uint64
 	Metrics                   metrics.ChallengerMetrics
 
 	EnableHsm  bool
@@ -130,6 +138,10 @@ type Challenger struct {
 	DtlEigenClient   client.DtlClient
 	LevelDBStore     *db.Store
 	txMgr            txmgr.TxManager
+	challengeSignal  chan struct{}
+	statusServer     *http.Server
+	prover           *datalayr.DisclosureProver
+	proverOnce       sync.Once
 	cancel           func()
 	wg               sync.WaitGroup
 	once             sync.Once
@@ -200,6 +212,7 @@ func NewChallenger(ctx context.Context, cfg *ChallengerConfig) (*Challenger, err
 		DtlEigenClient:   dtlEigenClient,
 		LevelDBStore:     levelDBStore,
 		txMgr:            txMgr,
+		challengeSignal:  make(chan struct{}, 1),
 		cancel:           cancel,
 	}, nil
 }
@@ -262,16 +275,6 @@ func (c *Challenger) callRetrieve(store *graphView.DataStore) ([]byte, []datalay
 	return data, frames, nil
 }
 
-// check if the fraud string exists within the data
-func (c *Challenger) checkForFraud(store *graphView.DataStore, data []byte) (*Fraud, bool) {
-	dataString := hex.EncodeToString(data)
-	index := strings.Index(dataString, fraudString)
-	if index != -1 {
-		return &Fraud{StartingIndex: index / 2}, true
-	}
-	return nil, false
-}
-
 func (c *Challenger) constructFraudProof(store *graphView.DataStore, data []byte, fraud *Fraud, frames []datalayr.Frame) (*FraudProof, error) {
 	// encode data to frames here
 	header, err := header.DecodeDataStoreHeader(store.Header)
@@ -279,18 +282,13 @@ func (c *Challenger) constructFraudProof(store *graphView.DataStore, data []byte
 		c.Cfg.Logger.Printf("MtChallenger Could not decode header %v. %v\n", header, err)
 		return nil, err
 	}
-	config := c.Cfg.KzgConfig
-
-	s1 := gkzg.ReadG1Points(config.G1Path, config.Order, config.NumWorker)
-	s2 := gkzg.ReadG2Points(config.G2Path, config.Order, config.NumWorker)
-
-	dp := datalayr.NewDisclosureProver(s1, s2)
+	dp := c.disclosureProver()
 
 	//there are 31 bytes per fr so there are 31*chunkLenE bytes in each chunk
 	//so the i'th byte starts at the (i/(31*encoder.EncodingParams.ChunkLenE))'th chunk
 	startingChunkIndex := fraud.StartingIndex / int(31*header.Degree)
-	//the fraud string ends len(fraudString)/2 bytes later
-	endingChunkIndex := (fraud.StartingIndex + len(fraudString)/2) / int(31*header.Degree)
+	//the fraud ends at the last byte of the mismatched range
+	endingChunkIndex := (fraud.EndingIndex - 1) / int(31*header.Degree)
 	startingSymbolIndex := fraud.StartingIndex % int(31*header.Degree)
 	//do some math to shift this over by the correct number of bytes
 	//there are 32 bytes in the actual poly for every 31 bytes in the data, hence (startingSymbolIndex/31)*32
@@ -301,6 +299,9 @@ func (c *Challenger) constructFraudProof(store *graphView.DataStore, data []byte
 	if startingChunkIndex > len(frames) {
 		return nil, fmt.Errorf("startingChunkIndex is out of frames range, startingChunkIndex: %d, len(frames): %d", startingChunkIndex, len(frames))
 	}
+	if endingChunkIndex >= len(frames) {
+		return nil, fmt.Errorf("endingChunkIndex is out of frames range, endingChunkIndex: %d, len(frames): %d", endingChunkIndex, len(frames))
+	}
 
 	//generate parameters for proving data on chain
 	//this is
@@ -441,7 +442,7 @@ func (c *Challenger) ChallengeProveFraud(ctx context.Context, fraudStoreNumber *
 	}
 }
 
-func (c *Challenger) postFraudProof(store *graphView.DataStore, fraudProof *FraudProof) (*types.Transaction, error) {
+func (c *Challenger) postFraudProof(store *graphView.DataStore, fraudProof *FraudProof) (*types.Receipt, error) {
 	searchData := rc.IDataLayrServiceManagerDataStoreSearchData{
 		Duration:  store.Duration,
 		Timestamp: new(big.Int).SetUint64(uint64(store.InitTime)),
@@ -476,8 +477,8 @@ func (c *Challenger) postFraudProof(store *graphView.DataStore, fraudProof *Frau
 	if err != nil {
 		return nil, err
 	}
-	log.Info("MtChallenger challenger prove fraud success", "TxHash", receipt.TxHash)
-	return tx, nil
+	log.Info("MtChallenger challenger prove fraud tx mined", "TxHash", receipt.TxHash, "status", receipt.Status)
+	return receipt, nil
 }
 
 func (c *Challenger) makeReRollupBatchTx(ctx context.Context, batchIndex *big.Int) (*types.Transaction, error) {
@@ -564,6 +565,13 @@ func (c *Challenger) ServiceInit() error {
 		return err
 	}
 	totalDaNode = nodeNum
+	if err := c.Cfg.KzgConfig.CheckSrs(); err != nil {
+		log.Error("MtChallenger kzg srs self-check fail", "err", err)
+		return err
+	}
+	if !c.Cfg.KzgConfig.LazyLoad {
+		c.disclosureProver()
+	}
 	walletBalance, err := c.Cfg.L1Client.BalanceAt(
 		c.Ctx, c.WalletAddr, nil,
 	)
@@ -590,8 +598,12 @@ func (c *Challenger) Start() error {
 		return err
 	}
 	if c.Cfg.ChallengerCheckEnable {
-		c.wg.Add(1)
+		c.wg.Add(2)
 		go c.eventLoop()
+		go c.challengeLoop()
+	}
+	if c.Cfg.StatusServerEnable {
+		c.startStatusServer()
 	}
 	if c.Cfg.DataCompensateEnable {
 		c.wg.Add(1)
@@ -627,6 +639,7 @@ func (c *Challenger) Start() error {
 }
 
 func (c *Challenger) Stop() {
+	c.stopStatusServer()
 	c.cancel()
 	c.wg.Wait()
 }
@@ -677,53 +690,29 @@ func (c *Challenger) eventLoop() {
 				}
 				log.Info("MtChallenger get data store by id success", "Confirmed", store.Confirmed)
 				if store.Confirmed {
-					data, frames, err := c.callRetrieve(store)
+					data, _, err := c.callRetrieve(store)
 					if err != nil {
 						log.Error("MtChallenger error getting data", "err", err)
 						continue
 					}
-					batchTxn := new([]eigenda.BatchTx)
-					batchRlpStream := rlp.NewStream(bytes.NewBuffer(data), uint64(len(data)))
-					err = batchRlpStream.Decode(batchTxn)
+					fraud, err := c.checkForFraud(store, data)
 					if err != nil {
-						log.Error("MtChallenger decode batch txn fail", "err", err)
-						continue
-					}
-					newBatchTxn := *batchTxn
-					for i := 0; i < len(newBatchTxn); i++ {
-						l2Tx := new(l2types.Transaction)
-						rlpStream := l2rlp.NewStream(bytes.NewBuffer(newBatchTxn[i].RawTx), 0)
-						if err := l2Tx.DecodeRLP(rlpStream); err != nil {
-							c.Cfg.Logger.Error().Err(err).Msg("Decode RLP fail")
-						}
-
-						// tx check for tmp, will remove in future
-						l2Transaction, _, err := c.Cfg.L2Client.TransactionByHash(c.Ctx, l2Tx.Hash())
-						if err != nil {
-							log.Error("MtChallenger no this transaction", "err", err)
-							continue
-						}
-						log.Info("MtChallenger fond transaction", "hash", l2Transaction.Hash().Hex())
+						log.Error("MtChallenger check batch fail", "batchIndex", i, "err", err)
+						break
 					}
-
-					// check if the fraud string exists within the data
-					fraud, exists := c.checkForFraud(store, data)
-					if !exists {
-						log.Info("MtChallenger no fraud")
+					if fraud == nil {
+						log.Info("MtChallenger no fraud", "batchIndex", i)
 						c.LevelDBStore.SetLatestBatchIndex(i)
 						continue
 					}
-					proof, err := c.constructFraudProof(store, data, fraud, frames)
-					if err != nil {
-						log.Error("MtChallenger error constructing fraud", "err", err)
-						continue
-					}
-					tx, err := c.postFraudProof(store, proof)
-					if err != nil {
-						log.Error("MtChallenger error posting fraud proof", "err", err)
-						continue
+					log.Warn("MtChallenger found fraud", "batchIndex", i, "storeNumber", store.StoreNumber,
+						"l2BlockNumber", fraud.BlockNumber, "reason", fraud.Reason,
+						"startingIndex", fraud.StartingIndex, "endingIndex", fraud.EndingIndex)
+					// the challenge loop owns proving and retries from here on
+					if !c.recordFraud(i, store, fraud) {
+						log.Error("MtChallenger record fraud fail", "batchIndex", i)
+						break
 					}
-					log.Info("MtChallenger fraud proof tx", "hash", tx.Hash().Hex())
 				}
 				c.LevelDBStore.SetLatestBatchIndex(i)
 			}
@@ -1,10 +1,17 @@
 package challenger
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
+
+	l2common "github.com/mantlenetworkio/mantle/l2geth/common"
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
+	"github.com/mantlenetworkio/mantle/l2geth/rollup/eigenda"
 )
 
 func TestSliceRange(t *testing.T) {
@@ -21,3 +28,119 @@ func TestSliceRange(t *testing.T) {
 	require.NoError(t, testFunc(frames, len(frames)))
 	require.Error(t, testFunc(frames, len(frames)+1))
 }
+
+func TestSplitBatchTxs(t *testing.T) {
+	batchTxs := []eigenda.BatchTx{
+		{BlockNumber: big.NewInt(1).Bytes(), TxMeta: []byte(`{"index":0}`), RawTx: []byte{0x01}},
+		{BlockNumber: big.NewInt(300).Bytes(), TxMeta: []byte(`{"index":1}`), RawTx: bytes.Repeat([]byte{0xaa}, 100)},
+	}
+	data, err := rlp.EncodeToBytes(batchTxs)
+	require.NoError(t, err)
+	data = append(data, make([]byte, 31)...)
+
+	ranges, err := splitBatchTxs(data)
+	require.NoError(t, err)
+	require.Len(t, ranges, len(batchTxs))
+	for i, r := range ranges {
+		require.Equal(t, batchTxs[i].BlockNumber, data[r.BlockNumber.Start:r.BlockNumber.End])
+		require.Equal(t, batchTxs[i].TxMeta, data[r.TxMeta.Start:r.TxMeta.End])
+		require.Equal(t, batchTxs[i].RawTx, data[r.RawTx.Start:r.RawTx.End])
+
+		var decoded eigenda.BatchTx
+		require.NoError(t, rlp.DecodeBytes(data[r.Start:r.End], &decoded))
+		require.Equal(t, batchTxs[i], decoded)
+	}
+
+	_, err = splitBatchTxs([]byte{0x01, 0x02})
+	require.Error(t, err)
+}
+
+func TestCheckBlockRange(t *testing.T) {
+	encode := func(blocks ...int64) *retrievedBatch {
+		var batchTxs []eigenda.BatchTx
+		for _, n := range blocks {
+			batchTxs = append(batchTxs, eigenda.BatchTx{BlockNumber: big.NewInt(n).Bytes(), TxMeta: []byte(`{}`), RawTx: []byte{0x01}})
+		}
+		data, err := rlp.EncodeToBytes(batchTxs)
+		require.NoError(t, err)
+		return &retrievedBatch{header: &eigenda.BatchHeader{PayloadOffset: 1, PayloadLen: len(data)}, payload: data}
+	}
+	tests := []struct {
+		name   string
+		blocks []int64
+		block  int64
+		fraud  bool
+		whole  bool
+	}{
+		{name: "exact range", blocks: []int64{10, 11, 12}},
+		{name: "wrong start", blocks: []int64{9, 10, 11}, block: 9, fraud: true},
+		{name: "gap", blocks: []int64{10, 12, 13}, block: 12, fraud: true},
+		{name: "past the end", blocks: []int64{10, 11, 12, 13}, block: 13, fraud: true},
+		{name: "ends early", blocks: []int64{10, 11}, block: 12, fraud: true, whole: true},
+		{name: "empty", block: 10, fraud: true, whole: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := encode(tt.blocks...)
+			ranges, err := splitBatchTxs(batch.payload)
+			require.NoError(t, err)
+			payloadRange := byteRange{Start: 0, End: batch.header.PayloadLen}
+			fraud := checkBlockRange(batch, ranges, payloadRange, big.NewInt(10), big.NewInt(13))
+			if !tt.fraud {
+				require.Nil(t, fraud)
+				return
+			}
+			require.NotNil(t, fraud)
+			require.Equal(t, big.NewInt(tt.block), fraud.BlockNumber)
+			if tt.whole {
+				require.Equal(t, 1, fraud.StartingIndex)
+				require.Equal(t, 1+batch.header.PayloadLen, fraud.EndingIndex)
+			} else {
+				require.Equal(t, big.NewInt(tt.block).Bytes(), batch.payload[fraud.StartingIndex-1:fraud.EndingIndex-1])
+			}
+		})
+	}
+}
+
+func TestCompareBatchTx(t *testing.T) {
+	index := uint64(5)
+	newTx := func(nonce uint64) *l2types.Transaction {
+		tx := l2types.NewTransaction(nonce, l2common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+		tx.SetTransactionMeta(l2types.NewTransactionMeta(big.NewInt(100), 1000, nil, l2types.QueueOriginSequencer, &index, nil, nil))
+		return tx
+	}
+	newBlock := func(txs ...*l2types.Transaction) *l2types.Block {
+		return l2types.NewBlock(&l2types.Header{Number: big.NewInt(10)}, txs, nil, nil)
+	}
+	tx := newTx(1)
+	var rawTx bytes.Buffer
+	require.NoError(t, tx.EncodeRLP(&rawTx))
+	txMeta, err := eigenda.EncodeTxMeta(&eigenda.TransactionMeta{L1BlockNumber: big.NewInt(100), L1Timestamp: 1000, Index: &index})
+	require.NoError(t, err)
+	data, err := rlp.EncodeToBytes([]eigenda.BatchTx{{BlockNumber: big.NewInt(10).Bytes(), TxMeta: txMeta, RawTx: rawTx.Bytes()}})
+	require.NoError(t, err)
+	batch := &retrievedBatch{header: &eigenda.BatchHeader{Version: eigenda.BatchVersionV1, PayloadOffset: 6, PayloadLen: len(data)}, payload: data}
+	ranges, err := splitBatchTxs(data)
+	require.NoError(t, err)
+	r := ranges[0]
+
+	fraud, err := compareBatchTx(batch, r, newBlock(tx))
+	require.NoError(t, err)
+	require.Nil(t, fraud)
+
+	fraud, err = compareBatchTx(batch, r, newBlock(newTx(2)))
+	require.NoError(t, err)
+	require.Equal(t, "raw tx mismatch", fraud.Reason)
+	require.Equal(t, 6+r.RawTx.Start, fraud.StartingIndex)
+
+	// a local block without exactly one tx is challenged instead of stalling
+	// the challenger on an error
+	for _, block := range []*l2types.Block{newBlock(), newBlock(tx, newTx(2))} {
+		fraud, err = compareBatchTx(batch, r, block)
+		require.NoError(t, err)
+		require.NotNil(t, fraud)
+		require.Equal(t, big.NewInt(10), fraud.BlockNumber)
+		require.Equal(t, 6+r.Start, fraud.StartingIndex)
+		require.Equal(t, 6+r.End, fraud.EndingIndex)
+	}
+}
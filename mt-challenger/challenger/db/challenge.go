@@ -0,0 +1,115 @@
+package db
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+type ChallengeStatus uint8
+
+const (
+	// ChallengeDetected is a fraud that has no proof tx mined yet
+	ChallengeDetected ChallengeStatus = iota + 1
+	// ChallengeProofFailed is a fraud whose last proof attempt failed or
+	// reverted, it is retried until MaxAttempts is reached
+	ChallengeProofFailed
+	// ChallengeProofSubmitted is a fraud whose proof tx was mined successfully
+	// and waits for the rollup store to be reverted on chain, it is retried
+	// when the store is still committed after ProofConfirmations blocks or
+	// ProofTimeout
+	ChallengeProofSubmitted
+	// ChallengeResolved is a fraud whose rollup store was reverted on chain
+	ChallengeResolved
+	// ChallengeAbandoned is a fraud that can no longer be proven, either the
+	// fraud proof period is over or every attempt failed
+	ChallengeAbandoned
+)
+
+func (s ChallengeStatus) String() string {
+	switch s {
+	case ChallengeDetected:
+		return "detected"
+	case ChallengeProofFailed:
+		return "proof-failed"
+	case ChallengeProofSubmitted:
+		return "proof-submitted"
+	case ChallengeResolved:
+		return "resolved"
+	case ChallengeAbandoned:
+		return "abandoned"
+	default:
+		return "unknown"
+	}
+}
+
+// Open reports whether the challenge still needs work
+func (s ChallengeStatus) Open() bool {
+	return s == ChallengeDetected || s == ChallengeProofFailed || s == ChallengeProofSubmitted
+}
+
+// Challenge tracks a detected fraud from detection to its on-chain resolution
+type Challenge struct {
+	BatchIndex    uint64          `json:"batch_index"`
+	StoreNumber   uint32          `json:"store_number"`
+	L2BlockNumber *big.Int        `json:"l2_block_number"`
+	Reason        string          `json:"reason"`
+	StartingIndex int             `json:"starting_index"`
+	EndingIndex   int             `json:"ending_index"`
+	Status        ChallengeStatus `json:"status"`
+	ProofTxHash   common.Hash     `json:"proof_tx_hash"`
+	ReceiptStatus uint64          `json:"receipt_status"`
+	ProofBlock    uint64          `json:"proof_block"`
+	ProofMinedAt  int64           `json:"proof_mined_at"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     int64           `json:"created_at"`
+	UpdatedAt     int64           `json:"updated_at"`
+}
+
+var challengePrefix = []byte("Challenge-")
+
+func challengeKey(batchIndex uint64) []byte {
+	return append(append([]byte{}, challengePrefix...), toByteArray(batchIndex)...)
+}
+
+func (s *Store) GetChallenge(batchIndex uint64) (*Challenge, bool) {
+	data, err := s.db.Get(challengeKey(batchIndex))
+	if err != nil {
+		return nil, false
+	}
+	var challenge Challenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		log.Error("Could not decode challenge", "batchIndex", batchIndex, "err", err)
+		return nil, false
+	}
+	return &challenge, true
+}
+
+func (s *Store) SetChallenge(challenge *Challenge) bool {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		log.Error("Could not encode challenge", "batchIndex", challenge.BatchIndex, "err", err)
+		return false
+	}
+	err = s.db.Put(challengeKey(challenge.BatchIndex), data)
+	return err == nil
+}
+
+// ListChallenges returns every recorded challenge in batch index order
+func (s *Store) ListChallenges() ([]*Challenge, error) {
+	iter := s.db.NewIterator(util.BytesPrefix(challengePrefix), nil)
+	defer iter.Release()
+	var challenges []*Challenge
+	for iter.Next() {
+		var challenge Challenge
+		if err := json.Unmarshal(iter.Value(), &challenge); err != nil {
+			return nil, err
+		}
+		challenges = append(challenges, &challenge)
+	}
+	return challenges, iter.Error()
+}
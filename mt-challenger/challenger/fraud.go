@@ -0,0 +1,240 @@
+package challenger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/datalayr/common/graphView"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common"
+	l2types "github.com/mantlenetworkio/mantle/l2geth/core/types"
+	"github.com/mantlenetworkio/mantle/l2geth/rollup/eigenda"
+)
+
+// byteRange is a half open range [Start, End) of a batch
+type byteRange struct {
+	Start int
+	End   int
+}
+
+// batchTxRange records where each rlp encoded eigenda.BatchTx and its fields
+// live inside the rlp encoded batch
+type batchTxRange struct {
+	byteRange
+	BlockNumber byteRange
+	TxMeta      byteRange
+	RawTx       byteRange
+}
+
+// splitBatchTxs walks the rlp encoded []eigenda.BatchTx without decoding it so
+// that a mismatching field can be mapped back to its position in the batch.
+// Anything after the outer list is padding and is ignored.
+func splitBatchTxs(data []byte) ([]batchTxRange, error) {
+	content, rest, err := rlp.SplitList(data)
+	if err != nil {
+		return nil, err
+	}
+	offset := len(data) - len(content) - len(rest)
+	var ranges []batchTxRange
+	for len(content) > 0 {
+		elemContent, elemRest, err := rlp.SplitList(content)
+		if err != nil {
+			return nil, fmt.Errorf("batch tx %d: %w", len(ranges), err)
+		}
+		elemEnd := offset + len(content) - len(elemRest)
+		fieldOffset := elemEnd - len(elemContent)
+		var fields [3]byteRange
+		for j := range fields {
+			_, val, fieldRest, err := rlp.Split(elemContent)
+			if err != nil {
+				return nil, fmt.Errorf("batch tx %d field %d: %w", len(ranges), j, err)
+			}
+			fieldEnd := fieldOffset + len(elemContent) - len(fieldRest)
+			fields[j] = byteRange{Start: fieldEnd - len(val), End: fieldEnd}
+			fieldOffset = fieldEnd
+			elemContent = fieldRest
+		}
+		if len(elemContent) != 0 {
+			return nil, fmt.Errorf("batch tx %d has unexpected trailing fields", len(ranges))
+		}
+		ranges = append(ranges, batchTxRange{
+			byteRange:   byteRange{Start: offset, End: elemEnd},
+			BlockNumber: fields[0],
+			TxMeta:      fields[1],
+			RawTx:       fields[2],
+		})
+		offset = elemEnd
+		content = elemRest
+	}
+	return ranges, nil
+}
+
+func newFraud(r byteRange, blockNumber *big.Int, reason string) *Fraud {
+	return &Fraud{
+		StartingIndex: r.Start,
+		EndingIndex:   r.End,
+		BlockNumber:   blockNumber,
+		Reason:        reason,
+	}
+}
+
+// retrievedBatch is a data store decoded far enough to map ranges of the
+// rlp encoded batch back to the retrieved data
+type retrievedBatch struct {
+	header  *eigenda.BatchHeader
+	payload []byte
+}
+
+// locate maps a range of the rlp encoded batch to the retrieved data, a
+// compressed payload can only be disclosed as a whole
+func (b *retrievedBatch) locate(r byteRange) byteRange {
+	if b.header.Compression != eigenda.CompressionNone {
+		return byteRange{Start: b.header.PayloadOffset, End: b.header.PayloadOffset + b.header.PayloadLen}
+	}
+	return byteRange{Start: b.header.PayloadOffset + r.Start, End: b.header.PayloadOffset + r.End}
+}
+
+func (b *retrievedBatch) field(r byteRange) []byte {
+	return b.payload[r.Start:r.End]
+}
+
+// checkForFraud re-executes the batch against the local l2geth node and
+// returns the first byte range that does not match. A nil Fraud with a nil
+// error means the batch is valid, an error means the check could not be
+// completed and must be retried.
+func (c *Challenger) checkForFraud(store *graphView.DataStore, data []byte) (*Fraud, error) {
+	header, err := eigenda.ParseBatchHeader(data)
+	if err != nil {
+		log.Warn("MtChallenger data store has no valid batch header", "storeNumber", store.StoreNumber, "err", err)
+		end := len(data)
+		if end > 31 {
+			end = 31
+		}
+		return newFraud(byteRange{Start: 0, End: end}, nil, "undecodable batch header"), nil
+	}
+	batch := &retrievedBatch{header: header}
+	payloadRange := byteRange{Start: 0, End: header.PayloadLen}
+	if batch.payload, err = header.Payload(data); err != nil {
+		log.Warn("MtChallenger data store has an invalid batch payload", "storeNumber", store.StoreNumber, "err", err)
+		return newFraud(batch.locate(payloadRange), nil, "undecodable batch payload"), nil
+	}
+	ranges, err := splitBatchTxs(batch.payload)
+	if err != nil {
+		log.Warn("MtChallenger data store is not a valid batch", "storeNumber", store.StoreNumber, "err", err)
+		return newFraud(batch.locate(payloadRange), nil, "undecodable batch"), nil
+	}
+	rollupBlock, err := c.EigenDaContract.DataStoreIdToL2RollUpBlock(&bind.CallOpts{}, store.StoreNumber)
+	if err != nil {
+		return nil, fmt.Errorf("get l2 rollup block of data store %d: %w", store.StoreNumber, err)
+	}
+	if fraud := checkBlockRange(batch, ranges, payloadRange, rollupBlock.StartL2BlockNumber, rollupBlock.EndBL2BlockNumber); fraud != nil {
+		log.Warn("MtChallenger batch does not match its rollup block range", "storeNumber", store.StoreNumber,
+			"start", rollupBlock.StartL2BlockNumber, "end", rollupBlock.EndBL2BlockNumber, "reason", fraud.Reason)
+		return fraud, nil
+	}
+	for _, r := range ranges {
+		blockNumber := new(big.Int).SetBytes(batch.field(r.BlockNumber))
+		fraud, err := c.checkBatchTx(c.Ctx, batch, r, blockNumber)
+		if err != nil || fraud != nil {
+			return fraud, err
+		}
+	}
+	return nil, nil
+}
+
+// checkBlockRange checks that the batch txs are the consecutive l2 blocks
+// [start, end) the batch was stored for. A batch that ends early is disclosed
+// as a whole since the missing blocks have no bytes of their own.
+func checkBlockRange(batch *retrievedBatch, ranges []batchTxRange, payloadRange byteRange, start, end *big.Int) *Fraud {
+	expected := new(big.Int).Set(start)
+	for _, r := range ranges {
+		blockNumber := new(big.Int).SetBytes(batch.field(r.BlockNumber))
+		if expected.Cmp(end) >= 0 {
+			return newFraud(batch.locate(r.BlockNumber), blockNumber, fmt.Sprintf("block %d is past the end block %d", blockNumber, end))
+		}
+		if blockNumber.Cmp(expected) != 0 {
+			return newFraud(batch.locate(r.BlockNumber), blockNumber, fmt.Sprintf("expected block %d", expected))
+		}
+		expected.Add(expected, big.NewInt(1))
+	}
+	if expected.Cmp(end) != 0 {
+		return newFraud(batch.locate(payloadRange), expected, fmt.Sprintf("batch ends at block %d, expected end block %d", expected, end))
+	}
+	return nil
+}
+
+// checkBatchTx compares a single batch tx with the block of the same number
+// on the local l2geth node
+func (c *Challenger) checkBatchTx(ctx context.Context, batch *retrievedBatch, r batchTxRange, blockNumber *big.Int) (*Fraud, error) {
+	block, err := c.Cfg.L2Client.BlockByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("get l2 block %d: %w", blockNumber, err)
+	}
+	return compareBatchTx(batch, r, block)
+}
+
+// compareBatchTx compares a single batch tx with the local l2 block of the
+// same number. Every l2 block holds exactly one tx, a local block that does
+// not cannot match the batch tx and is challenged as a whole.
+func compareBatchTx(batch *retrievedBatch, r batchTxRange, block *l2types.Block) (*Fraud, error) {
+	blockNumber := block.Number()
+	txs := block.Transactions()
+	if len(txs) != 1 {
+		log.Warn("MtChallenger local l2 block does not have exactly one tx", "blockNumber", blockNumber, "txs", len(txs))
+		return newFraud(batch.locate(r.byteRange), blockNumber, fmt.Sprintf("local block has %d txs instead of 1", len(txs))), nil
+	}
+	var txBuf bytes.Buffer
+	if err := txs[0].EncodeRLP(&txBuf); err != nil {
+		return nil, fmt.Errorf("encode l2 tx of block %d: %w", blockNumber, err)
+	}
+	if !bytes.Equal(txBuf.Bytes(), batch.field(r.RawTx)) {
+		return newFraud(batch.locate(r.RawTx), blockNumber, "raw tx mismatch"), nil
+	}
+
+	metaRange := batch.locate(r.TxMeta)
+	txMeta, err := eigenda.DecodeTxMeta(batch.header.Version, batch.field(r.TxMeta), batch.field(r.RawTx))
+	if err != nil {
+		return newFraud(metaRange, blockNumber, "undecodable tx meta"), nil
+	}
+	localMeta := txs[0].GetMeta()
+	switch {
+	case !bigEqual(txMeta.L1BlockNumber, localMeta.L1BlockNumber):
+		return newFraud(metaRange, blockNumber, "l1 block number mismatch"), nil
+	case txMeta.L1Timestamp != localMeta.L1Timestamp:
+		return newFraud(metaRange, blockNumber, "l1 timestamp mismatch"), nil
+	case !uint64PtrEqual(txMeta.QueueIndex, localMeta.QueueIndex):
+		return newFraud(metaRange, blockNumber, "queue index mismatch"), nil
+	case !uint64PtrEqual(txMeta.Index, localMeta.Index):
+		return newFraud(metaRange, blockNumber, "index mismatch"), nil
+	case !addressPtrEqual(txMeta.L1MessageSender, localMeta.L1MessageSender):
+		return newFraud(metaRange, blockNumber, "l1 message sender mismatch"), nil
+	}
+	return nil, nil
+}
+
+func bigEqual(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+func uint64PtrEqual(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func addressPtrEqual(a, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
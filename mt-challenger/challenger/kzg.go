@@ -0,0 +1,78 @@
+package challenger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	datalayr "github.com/Layr-Labs/datalayr/common/contracts"
+	gkzg "github.com/Layr-Labs/datalayr/common/crypto/go-kzg-bn254"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Sizes of an uncompressed bn254 point in the SRS files
+const (
+	g1PointSize = 64
+	g2PointSize = 128
+)
+
+// checkSrsFile verifies that an SRS file holds at least order points and,
+// when expectedHash is set, that its sha256 matches
+func checkSrsFile(path string, order uint64, pointSize int64, expectedHash string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if want := int64(order) * pointSize; info.Size() < want {
+		return fmt.Errorf("%s is %d bytes, order %d needs at least %d", path, info.Size(), order, want)
+	}
+	if expectedHash == "" {
+		return nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != strings.TrimPrefix(strings.ToLower(expectedHash), "0x") {
+		return fmt.Errorf("%s has sha256 %s, expected %s", path, got, expectedHash)
+	}
+	return nil
+}
+
+// CheckSrs is the startup self-check of the configured SRS files, reading a
+// truncated or wrong SRS would otherwise only fail once a fraud is found
+func (cfg KzgConfig) CheckSrs() error {
+	if cfg.Order == 0 {
+		return fmt.Errorf("srs order must be positive")
+	}
+	if err := checkSrsFile(cfg.G1Path, cfg.Order, g1PointSize, cfg.G1Hash); err != nil {
+		return fmt.Errorf("invalid g1 srs: %w", err)
+	}
+	if err := checkSrsFile(cfg.G2Path, cfg.Order, g2PointSize, cfg.G2Hash); err != nil {
+		return fmt.Errorf("invalid g2 srs: %w", err)
+	}
+	return nil
+}
+
+// disclosureProver returns the prover shared by every fraud proof, reading
+// the SRS on first use
+func (c *Challenger) disclosureProver() *datalayr.DisclosureProver {
+	c.proverOnce.Do(func() {
+		config := c.Cfg.KzgConfig
+		log.Info("MtChallenger loading kzg srs", "g1", config.G1Path, "g2", config.G2Path, "order", config.Order)
+		s1 := gkzg.ReadG1Points(config.G1Path, config.Order, config.NumWorker)
+		s2 := gkzg.ReadG2Points(config.G2Path, config.Order, config.NumWorker)
+		c.prover = datalayr.NewDisclosureProver(s1, s2)
+		log.Info("MtChallenger kzg srs loaded")
+	})
+	return c.prover
+}
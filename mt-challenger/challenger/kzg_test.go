@@ -0,0 +1,61 @@
+package challenger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gkzg "github.com/Layr-Labs/datalayr/common/crypto/go-kzg-bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSrs writes order copies of point to a file in dir
+func writeSrs(t *testing.T, dir, name string, point []byte, order int) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, bytes.Repeat(point, order), 0o600))
+	return path
+}
+
+// TestSrsPointSize checks g1PointSize and g2PointSize against the points the
+// gkzg reader reads, the generators are the first points of every SRS
+func TestSrsPointSize(t *testing.T) {
+	const order = 4
+	_, _, g1, g2 := bn254.Generators()
+	g1Bytes, g2Bytes := g1.RawBytes(), g2.RawBytes()
+	require.Len(t, g1Bytes, g1PointSize)
+	require.Len(t, g2Bytes, g2PointSize)
+
+	dir := t.TempDir()
+	g1Path := writeSrs(t, dir, "g1.point", g1Bytes[:], order)
+	g2Path := writeSrs(t, dir, "g2.point", g2Bytes[:], order)
+	g1Sum := sha256.Sum256(bytes.Repeat(g1Bytes[:], order))
+	cfg := KzgConfig{G1Path: g1Path, G2Path: g2Path, Order: order, NumWorker: 1, G1Hash: "0x" + hex.EncodeToString(g1Sum[:])}
+	require.NoError(t, cfg.CheckSrs())
+
+	// files CheckSrs accepts are read back point by point
+	s1 := gkzg.ReadG1Points(cfg.G1Path, cfg.Order, cfg.NumWorker)
+	require.Len(t, s1, order)
+	for i := range s1 {
+		require.True(t, s1[i].Equal(&g1), "g1 point %d", i)
+	}
+	s2 := gkzg.ReadG2Points(cfg.G2Path, cfg.Order, cfg.NumWorker)
+	require.Len(t, s2, order)
+	for i := range s2 {
+		require.True(t, s2[i].Equal(&g2), "g2 point %d", i)
+	}
+
+	// one point short
+	cfg.Order = order + 1
+	require.ErrorContains(t, cfg.CheckSrs(), "g1")
+	cfg.Order = order
+	cfg.G2Path = writeSrs(t, dir, "g2-short.point", g2Bytes[:g2PointSize-1], order)
+	require.ErrorContains(t, cfg.CheckSrs(), "g2")
+
+	cfg.G2Path = g2Path
+	cfg.G1Hash = hex.EncodeToString(make([]byte, sha256.Size))
+	require.ErrorContains(t, cfg.CheckSrs(), "sha256")
+}
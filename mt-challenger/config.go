@@ -42,6 +42,12 @@ type Config struct {
 	MetricsServerEnable       bool
 	MetricsHostname           string
 	MetricsPort               uint64
+	MaxChallengeAttempts      int
+	ProofConfirmations        uint64
+	ProofTimeout              time.Duration
+	StatusServerEnable        bool
+	StatusHostname            string
+	StatusPort                uint64
 	EnableHsm                 bool
 	HsmAPIName                string
 	HsmCreden                 string
@@ -67,6 +73,9 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 			TableDir:  ctx.GlobalString(flags.SrsTablePathFlag.Name),
 			Order:     ctx.GlobalUint64(flags.OrderFlag.Name),
 			NumWorker: ctx.GlobalInt(flags.KzgWorkersFlag.Name),
+			G1Hash:    ctx.GlobalString(flags.G1HashFlag.Name),
+			G2Hash:    ctx.GlobalString(flags.G2HashFlag.Name),
+			LazyLoad:  ctx.GlobalBool(flags.KzgLazyLoadFlag.Name),
 		},
 		ResubmissionTimeout:       ctx.GlobalDuration(flags.ResubmissionTimeoutFlag.Name),
 		NumConfirmations:          ctx.GlobalUint64(flags.NumConfirmationsFlag.Name),
@@ -86,6 +95,12 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 		MetricsServerEnable:       ctx.GlobalBool(flags.MetricsServerEnableFlag.Name),
 		MetricsHostname:           ctx.GlobalString(flags.MetricsHostnameFlag.Name),
 		MetricsPort:               ctx.GlobalUint64(flags.MetricsPortFlag.Name),
+		MaxChallengeAttempts:      ctx.GlobalInt(flags.MaxChallengeAttemptsFlag.Name),
+		ProofConfirmations:        ctx.GlobalUint64(flags.ProofConfirmationsFlag.Name),
+		ProofTimeout:              ctx.GlobalDuration(flags.ProofTimeoutFlag.Name),
+		StatusServerEnable:        ctx.GlobalBool(flags.StatusServerEnableFlag.Name),
+		StatusHostname:            ctx.GlobalString(flags.StatusHostnameFlag.Name),
+		StatusPort:                ctx.GlobalUint64(flags.StatusPortFlag.Name),
 		EnableHsm:                 ctx.GlobalBool(flags.EnableHsmFlag.Name),
 		HsmAddress:                ctx.GlobalString(flags.HsmAddressFlag.Name),
 		HsmAPIName:                ctx.GlobalString(flags.HsmAPINameFlag.Name),
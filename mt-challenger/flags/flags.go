@@ -1,6 +1,8 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/urfave/cli"
 	
 	"github.com/Layr-Labs/datalayr/common/logging"
@@ -102,6 +104,21 @@ var (
 		Required: true,
 		EnvVar:   prefixEnvVar("ORDER"),
 	}
+	G1HashFlag = cli.StringFlag{
+		Name:   "g1-sha256",
+		Usage:  "Expected sha256 of the G1 SRS file, checked at startup when set",
+		EnvVar: prefixEnvVar("G1_SHA256"),
+	}
+	G2HashFlag = cli.StringFlag{
+		Name:   "g2-sha256",
+		Usage:  "Expected sha256 of the G2 SRS file, checked at startup when set",
+		EnvVar: prefixEnvVar("G2_SHA256"),
+	}
+	KzgLazyLoadFlag = cli.BoolFlag{
+		Name:   "kzg-lazy-load",
+		Usage:  "Read the SRS on the first fraud proof instead of at startup",
+		EnvVar: prefixEnvVar("KZG_LAZY_LOAD"),
+	}
 	KzgWorkersFlag = cli.IntFlag{
 		Name:     "kzg-num-workers",
 		Usage:    "Order of the SRS",
@@ -211,6 +228,43 @@ var (
 		Value:  7301,
 		EnvVar: prefixEnvVar("METRICS_PORT"),
 	}
+	MaxChallengeAttemptsFlag = cli.IntFlag{
+		Name:   "max-challenge-attempts",
+		Usage:  "Number of fraud proof attempts before a challenge is abandoned",
+		Value:  5,
+		EnvVar: prefixEnvVar("MAX_CHALLENGE_ATTEMPTS"),
+	}
+	ProofConfirmationsFlag = cli.Uint64Flag{
+		Name: "proof-confirmations",
+		Usage: "Number of L1 blocks a mined fraud proof is given to revert " +
+			"the rollup store before it is retried",
+		Value:  12,
+		EnvVar: prefixEnvVar("PROOF_CONFIRMATIONS"),
+	}
+	ProofTimeoutFlag = cli.DurationFlag{
+		Name: "proof-timeout",
+		Usage: "Duration a mined fraud proof is given to revert the " +
+			"rollup store before it is retried",
+		Value:  30 * time.Minute,
+		EnvVar: prefixEnvVar("PROOF_TIMEOUT"),
+	}
+	StatusServerEnableFlag = cli.BoolFlag{
+		Name:   "status-server-enable",
+		Usage:  "Whether or not to run the challenge status server",
+		EnvVar: prefixEnvVar("STATUS_SERVER_ENABLE"),
+	}
+	StatusHostnameFlag = cli.StringFlag{
+		Name:   "status-hostname",
+		Usage:  "The hostname of the challenge status server",
+		Value:  "127.0.0.1",
+		EnvVar: prefixEnvVar("STATUS_HOSTNAME"),
+	}
+	StatusPortFlag = cli.Uint64Flag{
+		Name:   "status-port",
+		Usage:  "The port of the challenge status server",
+		Value:  7302,
+		EnvVar: prefixEnvVar("STATUS_PORT"),
+	}
 	EnableHsmFlag = cli.BoolFlag{
 		Name:   "enable-hsm",
 		Usage:  "Enalbe the hsm",
@@ -259,6 +313,9 @@ var requiredFlags = []cli.Flag{
 
 var optionalFlags = []cli.Flag{
 	KzgWorkersFlag,
+	G1HashFlag,
+	G2HashFlag,
+	KzgLazyLoadFlag,
 	HTTP2DisableFlag,
 	NeedReRollupBatchFlag,
 	ReRollupToolEnableFlag,
@@ -270,6 +327,12 @@ var optionalFlags = []cli.Flag{
 	MetricsServerEnableFlag,
 	MetricsHostnameFlag,
 	MetricsPortFlag,
+	MaxChallengeAttemptsFlag,
+	ProofConfirmationsFlag,
+	ProofTimeoutFlag,
+	StatusServerEnableFlag,
+	StatusHostnameFlag,
+	StatusPortFlag,
 }
 
 func init() {
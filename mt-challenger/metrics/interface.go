@@ -12,4 +12,6 @@ type ChallengerMetrics interface {
 	CheckBatchIndex() prometheus.Gauge
 
 	DataStoreId() prometheus.Gauge
+
+	OpenChallenges() prometheus.Gauge
 }
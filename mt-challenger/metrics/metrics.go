@@ -11,6 +11,7 @@ type ChallengerBase struct {
 	reRollupBatchIndex prometheus.Gauge
 	checkBatchIndex    prometheus.Gauge
 	dataStoreId        prometheus.Gauge
+	openChallenges     prometheus.Gauge
 }
 
 func NewChallengerBase() *ChallengerBase {
@@ -43,6 +44,11 @@ func NewChallengerBase() *ChallengerBase {
 			Help:      "current rollup da data_store_id",
 			Subsystem: "mtbatcher",
 		}),
+		openChallenges: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "open_challenges",
+			Help:      "detected frauds that are not resolved or abandoned yet",
+			Subsystem: "challenger",
+		}),
 	}
 }
 
@@ -65,3 +71,7 @@ func (cb *ChallengerBase) CheckBatchIndex() prometheus.Gauge {
 func (cb *ChallengerBase) DataStoreId() prometheus.Gauge {
 	return cb.dataStoreId
 }
+
+func (cb *ChallengerBase) OpenChallenges() prometheus.Gauge {
+	return cb.openChallenges
+}
@@ -85,6 +85,12 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 			ResubmissionTimeout:       cfg.ResubmissionTimeout,
 			NumConfirmations:          cfg.NumConfirmations,
 			SafeAbortNonceTooLowCount: cfg.SafeAbortNonceTooLowCount,
+			MaxChallengeAttempts:      cfg.MaxChallengeAttempts,
+			ProofConfirmations:        cfg.ProofConfirmations,
+			ProofTimeout:              cfg.ProofTimeout,
+			StatusServerEnable:        cfg.StatusServerEnable,
+			StatusHostname:            cfg.StatusHostname,
+			StatusPort:                cfg.StatusPort,
 			Metrics:                   metrics.NewChallengerBase(),
 			EnableHsm:                 cfg.EnableHsm,
 			HsmCreden:                 cfg.HsmCreden,
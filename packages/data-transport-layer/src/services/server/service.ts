@@ -744,6 +744,29 @@ export class L1TransportServer extends BaseService<L1TransportServerOptions> {
         let stateRoots = null
         let batch = null
 
+        // the datastore is posted to eigenda before the state roots are
+        // committed, so look it up on its own by batchindex and datastore_id
+        let datastore = null
+        let daBatchIndex = null
+        const transaction = await this.state.db.getDaTransactionByIndex(
+          BigNumber.from(req.params.index).toNumber()
+        )
+        if (transaction !== null) {
+          daBatchIndex = transaction.batchIndex
+          const daBatch = await this.state.db.getRollupStoreByBatchIndex(
+            transaction.batchIndex
+          )
+          if (daBatch !== null) {
+            let upgradeDataStoreId = 0
+            if (daBatch.upgrade_data_store_id) {
+              upgradeDataStoreId = daBatch.upgrade_data_store_id
+            }
+            datastore = await this.state.db.getDsById(
+              daBatch.data_store_id + upgradeDataStoreId
+            )
+          }
+        }
+
         switch (backend) {
           case 'l1':
             stateRoots = await this.state.db.getStateRootByIndex(
@@ -768,9 +791,9 @@ export class L1TransportServer extends BaseService<L1TransportServerOptions> {
             return {
               batch: null,
               stateRoots: null,
-              daBatchIndex: null,
+              daBatchIndex,
               currentL1BlockNumber,
-              datastore: null,
+              datastore,
               fraudProofWindow,
             }
           } else {
@@ -786,27 +809,11 @@ export class L1TransportServer extends BaseService<L1TransportServerOptions> {
           )
         }
 
-        // get datastore information by batchindex and datastore_id
-        let datastore = null
-        const transaction = await this.state.db.getDaTransactionByIndex(
-          BigNumber.from(req.params.index).toNumber()
-        )
-        const daBatch = await this.state.db.getRollupStoreByBatchIndex(
-          transaction.batchIndex
-        )
-        let upgradeDataStoreId = 0
-        if (daBatch.upgrade_data_store_id) {
-          upgradeDataStoreId = daBatch.upgrade_data_store_id
-        }
-        datastore = await this.state.db.getDsById(
-          daBatch.data_store_id + upgradeDataStoreId
-        )
-
         return {
           batch,
           stateRoots,
           currentL1BlockNumber,
-          daBatchIndex: transaction.batchIndex,
+          daBatchIndex,
           datastore,
           fraudProofWindow,
         }
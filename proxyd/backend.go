@@ -27,6 +27,8 @@ import (
 const (
 	JSONRPCVersion       = "2.0"
 	JSONRPCErrorInternal = -32000
+	// JSONRPCErrorLimitExceeded is the EIP-1474 error code for requests over a limit
+	JSONRPCErrorLimitExceeded = -32005
 )
 
 var (
@@ -79,6 +81,33 @@ var (
 		Message:       "rate limited",
 		HTTPErrorCode: 429,
 	}
+	// ErrOverRequestLimit is returned by the method, auth tier and sender
+	// limits, the per IP limit keeps ErrOverRateLimit
+	ErrOverRequestLimit = &RPCErr{
+		Code:          JSONRPCErrorLimitExceeded,
+		Message:       "rate limited",
+		HTTPErrorCode: 429,
+	}
+	ErrBlockOutOfRange = &RPCErr{
+		Code:          JSONRPCErrorInternal - 17,
+		Message:       "block is out of range",
+		HTTPErrorCode: 400,
+	}
+	ErrSubscriptionNotFound = &RPCErr{
+		Code:          JSONRPCErrorInternal - 18,
+		Message:       "subscription not found",
+		HTTPErrorCode: 400,
+	}
+	ErrBlockRangeTooLarge = &RPCErr{
+		Code:          JSONRPCErrorLimitExceeded,
+		Message:       "block range too large",
+		HTTPErrorCode: 400,
+	}
+	ErrTooManyResults = &RPCErr{
+		Code:          JSONRPCErrorLimitExceeded,
+		Message:       "query returned too many results",
+		HTTPErrorCode: 400,
+	}
 
 	ErrBackendUnexpectedJSONRPC = errors.New("backend returned an unexpected JSON-RPC response")
 )
@@ -91,6 +120,14 @@ func ErrInvalidRequest(msg string) *RPCErr {
 	}
 }
 
+func ErrInvalidParams(msg string) *RPCErr {
+	return &RPCErr{
+		Code:          -32602,
+		Message:       msg,
+		HTTPErrorCode: 400,
+	}
+}
+
 type Backend struct {
 	Name                 string
 	rpcURL               string
@@ -107,6 +144,8 @@ type Backend struct {
 	outOfServiceInterval time.Duration
 	stripTrailingXFF     bool
 	proxydIP             string
+	weight               int
+	latency              latencyEWMA
 }
 
 type BackendOpt func(b *Backend)
@@ -169,6 +208,12 @@ func WithStrippedTrailingXFF() BackendOpt {
 	}
 }
 
+func WithWeight(weight int) BackendOpt {
+	return func(b *Backend) {
+		b.weight = weight
+	}
+}
+
 func WithProxydIP(ip string) BackendOpt {
 	return func(b *Backend) {
 		b.proxydIP = ip
@@ -189,6 +234,7 @@ func NewBackend(
 		wsURL:           wsURL,
 		rateLimiter:     rateLimiter,
 		maxResponseSize: math.MaxInt64,
+		weight:          1,
 		client: &LimitedHTTPClient{
 			Client:      http.Client{Timeout: 5 * time.Second},
 			sem:         rpcSemaphore,
@@ -257,12 +303,18 @@ func (b *Backend) Forward(ctx context.Context, reqs []*RPCReq, isBatch bool) ([]
 				"req_id", GetReqID(ctx),
 				"err", err,
 			)
-			timer.ObserveDuration()
+			// A failed attempt counts at least as slow as the timeout, so a
+			// backend failing fast never looks like the fastest one
+			elapsed := timer.ObserveDuration()
+			if elapsed < b.client.Timeout {
+				elapsed = b.client.Timeout
+			}
+			RecordBackendLatency(b.Name, b.latency.Observe(elapsed))
 			RecordBatchRPCError(ctx, b.Name, reqs, err)
 			sleepContext(ctx, calcBackoff(i))
 			continue
 		}
-		timer.ObserveDuration()
+		RecordBackendLatency(b.Name, b.latency.Observe(timer.ObserveDuration()))
 
 		MaybeRecordErrorsInRPCRes(ctx, b.Name, reqs, res)
 		return res, err
@@ -273,6 +325,16 @@ func (b *Backend) Forward(ctx context.Context, reqs []*RPCReq, isBatch bool) ([]
 }
 
 func (b *Backend) ProxyWS(clientConn *websocket.Conn, methodWhitelist *StringSet) (*WSProxier, error) {
+	backendConn, err := b.dialWS()
+	if err != nil {
+		return nil, err
+	}
+	return NewWSProxier(b, clientConn, backendConn, methodWhitelist), nil
+}
+
+// dialWS opens a websocket connection to the backend, it counts towards the
+// max WS conns of the backend until releaseWS is called
+func (b *Backend) dialWS() (*websocket.Conn, error) {
 	if !b.Online() {
 		return nil, ErrBackendOffline
 	}
@@ -290,7 +352,14 @@ func (b *Backend) ProxyWS(clientConn *websocket.Conn, methodWhitelist *StringSet
 	}
 
 	activeBackendWsConnsGauge.WithLabelValues(b.Name).Inc()
-	return NewWSProxier(b, clientConn, backendConn, methodWhitelist), nil
+	return backendConn, nil
+}
+
+func (b *Backend) releaseWS() {
+	if err := b.rateLimiter.DecBackendWSConns(b.Name); err != nil {
+		log.Error("error decrementing backend ws conns", "name", b.Name, "err", err)
+	}
+	activeBackendWsConnsGauge.WithLabelValues(b.Name).Dec()
 }
 
 func (b *Backend) Online() bool {
@@ -448,6 +517,28 @@ func (b *Backend) doForward(ctx context.Context, rpcReqs []*RPCReq, isBatch bool
 	return res, nil
 }
 
+// callRPC sends a single request to the backend outside of its rate limits and
+// decodes the result into out
+func (b *Backend) callRPC(ctx context.Context, out interface{}, method string, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	req := &RPCReq{
+		JSONRPC: JSONRPCVersion,
+		Method:  method,
+		Params:  mustMarshalJSON(params),
+		ID:      json.RawMessage("1"),
+	}
+	res, err := b.doForward(ctx, []*RPCReq{req}, false)
+	if err != nil {
+		return err
+	}
+	if res[0].IsError() {
+		return res[0].Error
+	}
+	return json.Unmarshal(mustMarshalJSON(res[0].Result), out)
+}
+
 func responseIsNotBatched(b []byte) bool {
 	var r RPCRes
 	return json.Unmarshal(b, &r) == nil
@@ -472,8 +563,13 @@ func sortBatchRPCResponse(req []*RPCReq, res []*RPCRes) {
 }
 
 type BackendGroup struct {
-	Name     string
-	Backends []*Backend
+	Name      string
+	Backends  []*Backend
+	Consensus *ConsensusPoller
+	Strategy  RoutingStrategy
+
+	// next is the round robin position
+	next uint64
 }
 
 func (b *BackendGroup) Forward(ctx context.Context, rpcReqs []*RPCReq, isBatch bool) ([]*RPCRes, error) {
@@ -483,7 +579,21 @@ func (b *BackendGroup) Forward(ctx context.Context, rpcReqs []*RPCReq, isBatch b
 
 	rpcRequestsTotal.Inc()
 
-	for _, back := range b.Backends {
+	backends := b.Backends
+	var overrides []*RPCRes
+	if b.Consensus != nil {
+		var rctx RewriteContext
+		var ok bool
+		backends, rctx, ok = b.Consensus.consensusBackends()
+		if ok {
+			rpcReqs, overrides = rewriteRequests(ctx, rctx, rpcReqs)
+			if len(rpcReqs) == 0 {
+				return overrides, nil
+			}
+		}
+	}
+
+	for _, back := range b.orderBackends(backends) {
 		res, err := back.Forward(ctx, rpcReqs, isBatch)
 		if errors.Is(err, ErrMethodNotWhitelisted) {
 			return nil, err
@@ -516,15 +626,67 @@ func (b *BackendGroup) Forward(ctx context.Context, rpcReqs []*RPCReq, isBatch b
 			)
 			continue
 		}
-		return res, nil
+		RecordGroupRoutedRequest(b.Name, back.Name, RPCRequestSourceHTTP)
+		return mergeOverrides(overrides, res), nil
 	}
 
 	RecordUnserviceableRequest(ctx, RPCRequestSourceHTTP)
 	return nil, ErrNoBackends
 }
 
+// rewriteRequests rewrites the requests to the consensus of the group. It
+// returns the requests left to forward, and the responses of the requests
+// proxyd answers itself at their position with nil for the forwarded ones.
+func rewriteRequests(ctx context.Context, rctx RewriteContext, rpcReqs []*RPCReq) ([]*RPCReq, []*RPCRes) {
+	var overrides []*RPCRes
+	forward := make([]*RPCReq, 0, len(rpcReqs))
+	for i, req := range rpcReqs {
+		res := new(RPCRes)
+		result, err := RewriteRequest(rctx, req, res)
+		switch result {
+		case RewriteOverrideError:
+			if errors.Is(err, ErrRewriteBlockOutOfRange) {
+				res = NewRPCErrorRes(req.ID, ErrBlockOutOfRange)
+			} else {
+				res = NewRPCErrorRes(req.ID, err)
+			}
+			RecordRPCError(ctx, BackendProxyd, req.Method, res.Error)
+		case RewriteOverrideResponse:
+			RecordRPCForward(ctx, BackendProxyd, req.Method, RPCRequestSourceHTTP)
+		default:
+			forward = append(forward, req)
+			continue
+		}
+		if overrides == nil {
+			overrides = make([]*RPCRes, len(rpcReqs))
+		}
+		overrides[i] = res
+	}
+	return forward, overrides
+}
+
+// mergeOverrides fills the positions of the forwarded requests with the
+// backend responses
+func mergeOverrides(overrides []*RPCRes, res []*RPCRes) []*RPCRes {
+	if overrides == nil {
+		return res
+	}
+	j := 0
+	for i := range overrides {
+		if overrides[i] == nil {
+			overrides[i] = res[j]
+			j++
+		}
+	}
+	return overrides
+}
+
 func (b *BackendGroup) ProxyWS(ctx context.Context, clientConn *websocket.Conn, methodWhitelist *StringSet) (*WSProxier, error) {
-	for _, back := range b.Backends {
+	backends := b.Backends
+	if b.Consensus != nil {
+		backends, _, _ = b.Consensus.consensusBackends()
+	}
+	for _, back := range b.orderBackends(backends) {
 		proxier, err := back.ProxyWS(clientConn, methodWhitelist)
 		if errors.Is(err, ErrBackendOffline) {
 			log.Warn(
@@ -554,6 +716,7 @@ func (b *BackendGroup) ProxyWS(ctx context.Context, clientConn *websocket.Conn,
 			)
 			continue
 		}
+		RecordGroupRoutedRequest(b.Name, back.Name, RPCRequestSourceWS)
 		return proxier, nil
 	}
 
@@ -738,10 +901,7 @@ func (w *WSProxier) backendPump(ctx context.Context, errC chan error) {
 func (w *WSProxier) close() {
 	w.clientConn.Close()
 	w.backendConn.Close()
-	if err := w.backend.rateLimiter.DecBackendWSConns(w.backend.Name); err != nil {
-		log.Error("error decrementing backend ws conns", "name", w.backend.Name, "err", err)
-	}
-	activeBackendWsConnsGauge.WithLabelValues(w.backend.Name).Dec()
+	w.backend.releaseWS()
 }
 
 func (w *WSProxier) prepareClientMsg(msg []byte) (*RPCReq, error) {
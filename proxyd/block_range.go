@@ -0,0 +1,254 @@
+package proxyd
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const defaultMaxSplitRequests = 10
+
+// BlockRangeLimiter keeps the eth_getLogs and eth_getBlockRange requests
+// within a maximum block range and their responses within a maximum number
+// of results. Larger ranges are rejected, or split into chunks that are
+// forwarded in parallel and merged back into a single response.
+type BlockRangeLimiter struct {
+	maxBlockRange       uint64
+	maxResults          int
+	splitRequests       bool
+	maxSplitRequests    int
+	getLatestBlockNumFn GetLatestBlockNumFn
+}
+
+func NewBlockRangeLimiter(config BlockRangeConfig, getLatestBlockNumFn GetLatestBlockNumFn) *BlockRangeLimiter {
+	maxSplitRequests := config.MaxSplitRequests
+	if maxSplitRequests == 0 {
+		maxSplitRequests = defaultMaxSplitRequests
+	}
+	return &BlockRangeLimiter{
+		maxBlockRange:       config.MaxBlockRange,
+		maxResults:          config.MaxResults,
+		splitRequests:       config.SplitRequests,
+		maxSplitRequests:    maxSplitRequests,
+		getLatestBlockNumFn: getLatestBlockNumFn,
+	}
+}
+
+// rangeRequest is a request spanning a range of blocks. The bounds are block
+// numbers or tags, withRange returns the params of the request for another
+// range.
+type rangeRequest struct {
+	from      string
+	to        string
+	withRange func(from, to string) json.RawMessage
+}
+
+func parseRangeRequest(req *RPCReq) (*rangeRequest, bool) {
+	switch req.Method {
+	case "eth_getLogs":
+		var p []map[string]json.RawMessage
+		if err := json.Unmarshal(req.Params, &p); err != nil || len(p) == 0 {
+			return nil, false
+		}
+		// Filters by block hash span a single block
+		if _, ok := p[0]["blockHash"]; ok {
+			return nil, false
+		}
+		rr := &rangeRequest{from: "latest", to: "latest"}
+		for key, bound := range map[string]*string{"fromBlock": &rr.from, "toBlock": &rr.to} {
+			val, ok := p[0][key]
+			if !ok {
+				continue
+			}
+			if err := json.Unmarshal(val, bound); err != nil {
+				return nil, false
+			}
+		}
+		rr.withRange = func(from, to string) json.RawMessage {
+			filter := make(map[string]json.RawMessage, len(p[0]))
+			for k, v := range p[0] {
+				filter[k] = v
+			}
+			filter["fromBlock"] = mustMarshalJSON(from)
+			filter["toBlock"] = mustMarshalJSON(to)
+			params := append([]map[string]json.RawMessage{filter}, p[1:]...)
+			return mustMarshalJSON(params)
+		}
+		return rr, true
+	case "eth_getBlockRange":
+		start, end, includeTx, err := decodeGetBlockRangeParams(req.Params)
+		if err != nil {
+			return nil, false
+		}
+		return &rangeRequest{
+			from: start,
+			to:   end,
+			withRange: func(from, to string) json.RawMessage {
+				return mustMarshalJSON([]interface{}{from, to, includeTx})
+			},
+		}, true
+	}
+	return nil, false
+}
+
+// resolveBlock turns a block tag into a number. The tags that may be behind
+// the latest block resolve to it, so the range is never underestimated.
+func (l *BlockRangeLimiter) resolveBlock(ctx context.Context, tag string) (uint64, bool, error) {
+	switch tag {
+	case "earliest":
+		return 0, true, nil
+	case "latest", "pending", "safe", "finalized":
+		latest, err := l.getLatestBlockNumFn(ctx)
+		if err != nil {
+			return 0, false, err
+		}
+		return latest, true, nil
+	}
+	num, err := decodeBlockInput(tag)
+	if err != nil {
+		// Invalid blocks are left for the backend to reject
+		return 0, false, nil
+	}
+	return num, true, nil
+}
+
+// Split checks the block range of the request. It returns nil when the
+// request can be forwarded as it is, or the chunks to forward instead.
+func (l *BlockRangeLimiter) Split(ctx context.Context, req *RPCReq) ([]*RPCReq, error) {
+	if l.maxBlockRange == 0 {
+		return nil, nil
+	}
+	rr, ok := parseRangeRequest(req)
+	if !ok {
+		return nil, nil
+	}
+
+	from, to, ok, err := l.resolveRange(ctx, rr)
+	if err != nil {
+		// Fail open rather than rejecting every range request while the
+		// latest block is unknown
+		log.Warn(
+			"error resolving block range, forwarding request as is",
+			"req_id", GetReqID(ctx),
+			"method", req.Method,
+			"err", err,
+		)
+		return nil, nil
+	}
+	if !ok || to < from || to-from < l.maxBlockRange {
+		return nil, nil
+	}
+	return l.split(req, rr, from, to)
+}
+
+func (l *BlockRangeLimiter) resolveRange(ctx context.Context, rr *rangeRequest) (uint64, uint64, bool, error) {
+	from, ok, err := l.resolveBlock(ctx, rr.from)
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+	to, ok, err := l.resolveBlock(ctx, rr.to)
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+	return from, to, true, nil
+}
+
+func (l *BlockRangeLimiter) split(req *RPCReq, rr *rangeRequest, from, to uint64) ([]*RPCReq, error) {
+	numChunks := (to-from)/l.maxBlockRange + 1
+	if !l.splitRequests || numChunks > uint64(l.maxSplitRequests) {
+		return nil, ErrBlockRangeTooLarge
+	}
+
+	// The outer bounds keep their tags, so the last chunk still ends at the
+	// block the client asked for if the chain moved on in the meantime
+	chunks := make([]*RPCReq, 0, numChunks)
+	for start := from; start <= to; start += l.maxBlockRange {
+		end := start + l.maxBlockRange - 1
+		chunkFrom, chunkTo := hexutil.EncodeUint64(start), hexutil.EncodeUint64(end)
+		if start == from {
+			chunkFrom = rr.from
+		}
+		if end >= to {
+			chunkTo = rr.to
+		}
+		chunks = append(chunks, &RPCReq{
+			JSONRPC: req.JSONRPC,
+			Method:  req.Method,
+			Params:  rr.withRange(chunkFrom, chunkTo),
+			ID:      req.ID,
+		})
+		if end >= to {
+			break
+		}
+	}
+	return chunks, nil
+}
+
+// ExceedsMaxResults returns whether the response to a range request holds
+// more logs or blocks than allowed
+func (l *BlockRangeLimiter) ExceedsMaxResults(req *RPCReq, res *RPCRes) bool {
+	if l.maxResults == 0 || res == nil || res.Error != nil {
+		return false
+	}
+	if req.Method != "eth_getLogs" && req.Method != "eth_getBlockRange" {
+		return false
+	}
+	results, ok := res.Result.([]interface{})
+	return ok && len(results) > l.maxResults
+}
+
+// ForwardChunks forwards the chunks of a split request to the group at once
+// and merges their results in order. The first error of a chunk fails the
+// whole request.
+func (l *BlockRangeLimiter) ForwardChunks(ctx context.Context, group *BackendGroup, req *RPCReq, chunks []*RPCReq) *RPCRes {
+	RecordSplitRequest(req.Method, len(chunks))
+
+	chunkRes := make([]*RPCRes, len(chunks))
+	chunkErrs := make([]error, len(chunks))
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk *RPCReq) {
+			defer wg.Done()
+			res, err := group.Forward(ctx, []*RPCReq{chunk}, false)
+			if err == nil && len(res) != 1 {
+				err = ErrBackendBadResponse
+			}
+			if err != nil {
+				chunkErrs[i] = err
+				return
+			}
+			chunkRes[i] = res[0]
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	merged := make([]interface{}, 0)
+	for i, res := range chunkRes {
+		if err := chunkErrs[i]; err != nil {
+			log.Error(
+				"error forwarding request chunk",
+				"req_id", GetReqID(ctx),
+				"method", req.Method,
+				"chunk", i,
+				"err", err,
+			)
+			return NewRPCErrorRes(req.ID, err)
+		}
+		if res.Error != nil {
+			return NewRPCErrorRes(req.ID, res.Error)
+		}
+		results, ok := res.Result.([]interface{})
+		if !ok && res.Result != nil {
+			return NewRPCErrorRes(req.ID, ErrBackendBadResponse)
+		}
+		merged = append(merged, results...)
+		if l.maxResults != 0 && len(merged) > l.maxResults {
+			return NewRPCErrorRes(req.ID, ErrTooManyResults)
+		}
+	}
+	return NewRPCRes(req.ID, merged)
+}
@@ -0,0 +1,90 @@
+package proxyd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockRangeLimiterSplit(t *testing.T) {
+	latest := func(ctx context.Context) (uint64, error) {
+		return 100, nil
+	}
+	config := BlockRangeConfig{MaxBlockRange: 10, SplitRequests: true, MaxSplitRequests: 3}
+	l := NewBlockRangeLimiter(config, latest)
+
+	tests := []struct {
+		name   string
+		method string
+		params string
+		err    error
+		chunks []string
+	}{
+		{"within range", "eth_getLogs", `[{"fromBlock":"0x1","toBlock":"0xa"}]`, nil, nil},
+		{"default range", "eth_getLogs", `[{}]`, nil, nil},
+		{"block hash", "eth_getLogs", `[{"blockHash":"0x01"}]`, nil, nil},
+		{"reversed range", "eth_getLogs", `[{"fromBlock":"0x10","toBlock":"0x1"}]`, nil, nil},
+		{"invalid block", "eth_getLogs", `[{"fromBlock":"foo","toBlock":"0x1"}]`, nil, nil},
+		{"other method", "eth_call", `[{},"earliest"]`, nil, nil},
+		{
+			"split range",
+			"eth_getLogs",
+			`[{"fromBlock":"0x1","toBlock":"0x19","address":"0x01"}]`,
+			nil,
+			[]string{
+				`[{"fromBlock":"0x1","toBlock":"0xa","address":"0x01"}]`,
+				`[{"fromBlock":"0xb","toBlock":"0x14","address":"0x01"}]`,
+				`[{"fromBlock":"0x15","toBlock":"0x19","address":"0x01"}]`,
+			},
+		},
+		{
+			"split tags",
+			"eth_getLogs",
+			`[{"fromBlock":"0x51"}]`,
+			nil,
+			[]string{
+				`[{"fromBlock":"0x51","toBlock":"0x5a"}]`,
+				`[{"fromBlock":"0x5b","toBlock":"latest"}]`,
+			},
+		},
+		{
+			"split block range",
+			"eth_getBlockRange",
+			`["0x0","0xf",true]`,
+			nil,
+			[]string{`["0x0","0x9",true]`, `["0xa","0xf",true]`},
+		},
+		{"too many chunks", "eth_getLogs", `[{"fromBlock":"earliest"}]`, ErrBlockRangeTooLarge, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &RPCReq{JSONRPC: JSONRPCVersion, Method: tt.method, Params: json.RawMessage(tt.params), ID: []byte("1")}
+			chunks, err := l.Split(context.Background(), req)
+			require.Equal(t, tt.err, err)
+			require.Equal(t, len(tt.chunks), len(chunks))
+			for i, chunk := range chunks {
+				require.Equal(t, tt.method, chunk.Method)
+				require.Equal(t, req.ID, chunk.ID)
+				require.JSONEq(t, tt.chunks[i], string(chunk.Params))
+			}
+		})
+	}
+
+	config.SplitRequests = false
+	l = NewBlockRangeLimiter(config, latest)
+	req := &RPCReq{JSONRPC: JSONRPCVersion, Method: "eth_getLogs", Params: json.RawMessage(`[{"fromBlock":"0x1","toBlock":"0xb"}]`), ID: []byte("1")}
+	_, err := l.Split(context.Background(), req)
+	require.Equal(t, ErrBlockRangeTooLarge, err)
+}
+
+func TestBlockRangeLimiterExceedsMaxResults(t *testing.T) {
+	l := NewBlockRangeLimiter(BlockRangeConfig{MaxResults: 2}, nil)
+	logs := &RPCReq{Method: "eth_getLogs"}
+
+	require.False(t, l.ExceedsMaxResults(logs, NewRPCRes(nil, []interface{}{1, 2})))
+	require.True(t, l.ExceedsMaxResults(logs, NewRPCRes(nil, []interface{}{1, 2, 3})))
+	require.False(t, l.ExceedsMaxResults(&RPCReq{Method: "eth_call"}, NewRPCRes(nil, []interface{}{1, 2, 3})))
+	require.False(t, l.ExceedsMaxResults(logs, NewRPCErrorRes(nil, ErrInternal)))
+}
@@ -40,10 +40,46 @@ type MetricsConfig struct {
 }
 
 type RateLimitConfig struct {
+	UseRedis         bool     `toml:"use_redis"`
 	RatePerSecond    int      `toml:"rate_per_second"`
 	ExemptOrigins    []string `toml:"exempt_origins"`
 	ExemptUserAgents []string `toml:"exempt_user_agents"`
 	ErrorMessage     string   `toml:"error_message"`
+
+	// MethodOverrides limits methods on top of the per client limit, a name
+	// ending with * matches every method with that prefix
+	MethodOverrides map[string]*RateLimitRuleConfig `toml:"method_overrides"`
+	// Tiers replace the per IP limit of the auth keys in AuthTiers, which maps
+	// auth key aliases to tier names, with a limit per auth key
+	Tiers     map[string]*RateLimitTierConfig `toml:"tiers"`
+	AuthTiers map[string]string               `toml:"auth_tiers"`
+	// SenderLimit limits the eth_sendRawTransaction calls per sender address
+	SenderLimit RateLimitRuleConfig `toml:"sender_limit"`
+}
+
+type RateLimitRuleConfig struct {
+	Limit           int `toml:"limit"`
+	IntervalSeconds int `toml:"interval_seconds"`
+	// Global shares the limit among all clients instead of limiting each client
+	Global bool `toml:"global"`
+}
+
+type RateLimitTierConfig struct {
+	// RatePerSecond of zero leaves the auth keys of the tier unlimited
+	RatePerSecond int `toml:"rate_per_second"`
+}
+
+type BlockRangeConfig struct {
+	// MaxBlockRange is the most blocks an eth_getLogs or eth_getBlockRange
+	// request may span, 0 disables the limit
+	MaxBlockRange uint64 `toml:"max_block_range"`
+	// MaxResults is the most logs or blocks a response may hold, 0 disables
+	// the limit
+	MaxResults int `toml:"max_results"`
+	// SplitRequests forwards larger ranges as chunks of MaxBlockRange blocks
+	// instead of rejecting them, up to MaxSplitRequests chunks
+	SplitRequests    bool `toml:"split_requests"`
+	MaxSplitRequests int  `toml:"max_split_requests"`
 }
 
 type BackendOptions struct {
@@ -64,12 +100,24 @@ type BackendConfig struct {
 	ClientCertFile   string `toml:"client_cert_file"`
 	ClientKeyFile    string `toml:"client_key_file"`
 	StripTrailingXFF bool   `toml:"strip_trailing_xff"`
+	Weight           int    `toml:"weight"`
 }
 
 type BackendsConfig map[string]*BackendConfig
 
 type BackendGroupConfig struct {
 	Backends []string `toml:"backends"`
+
+	// RoutingStrategy is one of priority (default), round_robin, weighted
+	// and least_latency
+	RoutingStrategy string `toml:"routing_strategy"`
+
+	// ConsensusAware routes only to the backends that are in sync and agree
+	// on the chain, and pins block tags to the height they agree on
+	ConsensusAware            bool   `toml:"consensus_aware"`
+	ConsensusPollIntervalMs   int    `toml:"consensus_poll_interval_ms"`
+	ConsensusMaxBlockLag      uint64 `toml:"consensus_max_block_lag"`
+	ConsensusBanPeriodSeconds int    `toml:"consensus_ban_period_seconds"`
 }
 
 type BackendGroupsConfig map[string]*BackendGroupConfig
@@ -78,11 +126,13 @@ type MethodMappingsConfig map[string]string
 
 type Config struct {
 	WSBackendGroup    string              `toml:"ws_backend_group"`
+	WSMultiplexing    bool                `toml:"ws_multiplexing"`
 	Server            ServerConfig        `toml:"server"`
 	Cache             CacheConfig         `toml:"cache"`
 	Redis             RedisConfig         `toml:"redis"`
 	Metrics           MetricsConfig       `toml:"metrics"`
 	RateLimit         RateLimitConfig     `toml:"rate_limit"`
+	BlockRange        BlockRangeConfig    `toml:"block_range"`
 	BackendOptions    BackendOptions      `toml:"backend"`
 	Backends          BackendsConfig      `toml:"backends"`
 	Authentication    map[string]string   `toml:"authentication"`
@@ -0,0 +1,340 @@
+package proxyd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	defaultConsensusPollInterval = 1 * time.Second
+	defaultConsensusMaxBlockLag  = 8
+	defaultConsensusBanPeriod    = 5 * time.Minute
+)
+
+// ConsensusPoller tracks the head of every backend of a group and keeps the
+// set of backends that are in sync and agree on the chain. Backends that lag
+// behind are left out until they catch up, backends that are on a different
+// chain than the majority are banned for a while.
+type ConsensusPoller struct {
+	group       *BackendGroup
+	interval    time.Duration
+	maxBlockLag uint64
+	banPeriod   time.Duration
+
+	mu     sync.RWMutex
+	states map[*Backend]*backendState
+	// consensus is nil until the first poll finished
+	consensus   []*Backend
+	latestBlock uint64
+	safeBlock   uint64
+
+	quit chan struct{}
+}
+
+type backendState struct {
+	latestBlock uint64
+	safeBlock   uint64
+	syncing     bool
+	ok          bool
+	bannedUntil time.Time
+}
+
+type ConsensusOpt func(cp *ConsensusPoller)
+
+func WithConsensusPollInterval(interval time.Duration) ConsensusOpt {
+	return func(cp *ConsensusPoller) {
+		cp.interval = interval
+	}
+}
+
+func WithConsensusMaxBlockLag(lag uint64) ConsensusOpt {
+	return func(cp *ConsensusPoller) {
+		cp.maxBlockLag = lag
+	}
+}
+
+func WithConsensusBanPeriod(period time.Duration) ConsensusOpt {
+	return func(cp *ConsensusPoller) {
+		cp.banPeriod = period
+	}
+}
+
+func NewConsensusPoller(group *BackendGroup, opts ...ConsensusOpt) *ConsensusPoller {
+	cp := &ConsensusPoller{
+		group:       group,
+		interval:    defaultConsensusPollInterval,
+		maxBlockLag: defaultConsensusMaxBlockLag,
+		banPeriod:   defaultConsensusBanPeriod,
+		states:      make(map[*Backend]*backendState),
+		quit:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(cp)
+	}
+	for _, back := range group.Backends {
+		cp.states[back] = &backendState{}
+	}
+	return cp
+}
+
+func (cp *ConsensusPoller) Start() {
+	go func() {
+		ticker := time.NewTicker(cp.interval)
+		defer ticker.Stop()
+
+		for {
+			cp.poll(context.Background())
+
+			select {
+			case <-ticker.C:
+			case <-cp.quit:
+				return
+			}
+		}
+	}()
+}
+
+func (cp *ConsensusPoller) Stop() {
+	close(cp.quit)
+}
+
+// ConsensusBlocks returns the latest and safe block the backends agreed on
+func (cp *ConsensusPoller) ConsensusBlocks() (latest uint64, safe uint64) {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	return cp.latestBlock, cp.safeBlock
+}
+
+// IsBanned returns whether the backend is banned for being on another chain
+// than the rest of the group
+func (cp *ConsensusPoller) IsBanned(back *Backend) bool {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	state := cp.states[back]
+	return state != nil && time.Now().Before(state.bannedUntil)
+}
+
+// consensusBackends returns the backends to route to in the order of the
+// group. Before the first poll every backend of the group is used and no
+// rewrite context is returned.
+func (cp *ConsensusPoller) consensusBackends() ([]*Backend, RewriteContext, bool) {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	if cp.consensus == nil {
+		return cp.group.Backends, RewriteContext{}, false
+	}
+	backends := make([]*Backend, 0, len(cp.consensus))
+	for _, back := range cp.group.Backends {
+		for _, c := range cp.consensus {
+			if back == c {
+				backends = append(backends, back)
+				break
+			}
+		}
+	}
+	rctx := RewriteContext{
+		latest: hexutil.Uint64(cp.latestBlock),
+		safe:   hexutil.Uint64(cp.safeBlock),
+	}
+	return backends, rctx, true
+}
+
+type rpcBlock struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   string         `json:"hash"`
+}
+
+type rpcRollupInfo struct {
+	Syncing bool `json:"syncing"`
+}
+
+func (cp *ConsensusPoller) poll(ctx context.Context) {
+	updates := make(map[*Backend]*backendState, len(cp.group.Backends))
+	var updatesMu sync.Mutex
+	var wg sync.WaitGroup
+	for _, back := range cp.group.Backends {
+		wg.Add(1)
+		go func(back *Backend) {
+			defer wg.Done()
+			state := cp.fetchState(ctx, back)
+			updatesMu.Lock()
+			updates[back] = state
+			updatesMu.Unlock()
+		}(back)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	cp.mu.Lock()
+	for back, update := range updates {
+		update.bannedUntil = cp.states[back].bannedUntil
+		cp.states[back] = update
+	}
+	candidates := make([]*Backend, 0, len(cp.group.Backends))
+	var highest uint64
+	for _, back := range cp.group.Backends {
+		state := cp.states[back]
+		if !state.ok || state.syncing || now.Before(state.bannedUntil) {
+			continue
+		}
+		candidates = append(candidates, back)
+		if state.latestBlock > highest {
+			highest = state.latestBlock
+		}
+	}
+
+	// Leave out the backends lagging too far behind the highest one, the
+	// others agree on the lowest head among them
+	inSync := candidates[:0]
+	proposed := highest
+	for _, back := range candidates {
+		state := cp.states[back]
+		if state.latestBlock+cp.maxBlockLag < highest {
+			log.Debug("backend lagging behind", "group", cp.group.Name, "name", back.Name, "block", state.latestBlock, "highest", highest)
+			continue
+		}
+		inSync = append(inSync, back)
+		if state.latestBlock < proposed {
+			proposed = state.latestBlock
+		}
+	}
+	cp.mu.Unlock()
+
+	consensus := cp.agree(ctx, inSync, proposed)
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	var safe uint64
+	for i, back := range consensus {
+		state := cp.states[back]
+		if i == 0 || state.safeBlock < safe {
+			safe = state.safeBlock
+		}
+	}
+	if safe > proposed {
+		safe = proposed
+	}
+	if len(consensus) == 0 {
+		// Routing to no backend at all would blackhole the group, keep the
+		// previous consensus until the backends agree again
+		log.Warn("no backends in consensus, keeping the previous consensus", "group", cp.group.Name)
+		consensus = cp.consensus
+		proposed, safe = cp.latestBlock, cp.safeBlock
+	}
+	cp.consensus = consensus
+	cp.latestBlock = proposed
+	cp.safeBlock = safe
+
+	RecordGroupConsensus(cp.group.Name, proposed, safe, len(consensus))
+	for _, back := range cp.group.Backends {
+		state := cp.states[back]
+		inConsensus := false
+		for _, c := range consensus {
+			if c == back {
+				inConsensus = true
+				break
+			}
+		}
+		RecordBackendConsensusState(cp.group.Name, back.Name, state.latestBlock, now.Before(state.bannedUntil), inConsensus)
+	}
+}
+
+// agree returns the backends that have the block a strict majority of the
+// backends have at the proposed height, the others are banned. Without a
+// strict majority nobody can tell which chain is right, so no backend is
+// banned and no backend is returned.
+func (cp *ConsensusPoller) agree(ctx context.Context, backends []*Backend, proposed uint64) []*Backend {
+	hashes := make([]string, len(backends))
+	var wg sync.WaitGroup
+	for i, back := range backends {
+		wg.Add(1)
+		go func(i int, back *Backend) {
+			defer wg.Done()
+			var block *rpcBlock
+			err := back.callRPC(ctx, &block, "eth_getBlockByNumber", hexutil.Uint64(proposed), false)
+			if err != nil || block == nil {
+				log.Warn("error getting consensus block", "name", back.Name, "block", proposed, "err", err)
+				return
+			}
+			hashes[i] = block.Hash
+		}(i, back)
+	}
+	wg.Wait()
+
+	votes := make(map[string]int)
+	var majority string
+	var voters int
+	for _, hash := range hashes {
+		if hash == "" {
+			continue
+		}
+		voters++
+		votes[hash]++
+		if votes[hash] > votes[majority] {
+			majority = hash
+		}
+	}
+	if votes[majority]*2 <= voters {
+		if voters > 0 {
+			log.Warn("no majority on the consensus block", "group", cp.group.Name, "block", proposed, "hashes", len(votes))
+		}
+		return nil
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	consensus := make([]*Backend, 0, len(backends))
+	for i, back := range backends {
+		switch hashes[i] {
+		case "":
+		case majority:
+			consensus = append(consensus, back)
+		default:
+			log.Warn(
+				"banning backend disagreeing with the consensus",
+				"group", cp.group.Name,
+				"name", back.Name,
+				"block", proposed,
+				"hash", hashes[i],
+				"consensus_hash", majority,
+			)
+			cp.states[back].bannedUntil = time.Now().Add(cp.banPeriod)
+		}
+	}
+	return consensus
+}
+
+func (cp *ConsensusPoller) fetchState(ctx context.Context, back *Backend) *backendState {
+	state := &backendState{}
+	if !back.Online() {
+		return state
+	}
+
+	var latest *rpcBlock
+	if err := back.callRPC(ctx, &latest, "eth_getBlockByNumber", "latest", false); err != nil || latest == nil {
+		log.Warn("error polling backend head", "name", back.Name, "err", err)
+		return state
+	}
+	state.latestBlock = uint64(latest.Number)
+
+	// Not every backend knows the safe tag, the group has no safe block then
+	var safe *rpcBlock
+	if err := back.callRPC(ctx, &safe, "eth_getBlockByNumber", "safe", false); err == nil && safe != nil {
+		state.safeBlock = uint64(safe.Number)
+	}
+
+	// Backends without the rollup namespace are taken as synced
+	var info rpcRollupInfo
+	if err := back.callRPC(ctx, &info, "rollup_getInfo"); err == nil {
+		state.syncing = info.Syncing
+	}
+
+	state.ok = true
+	return state
+}
@@ -0,0 +1,173 @@
+package integration_tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mantlenetworkio/mantle/proxyd"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	blockRangeTooLargeResponse = `{"jsonrpc":"2.0","error":{"code":-32005,"message":"block range too large"},"id":999}`
+	tooManyResultsResponse     = `{"jsonrpc":"2.0","error":{"code":-32005,"message":"query returned too many results"},"id":999}`
+)
+
+// logsHandler answers eth_getLogs with a log for every block of the range,
+// the latest block is 100
+func logsHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		panic(err)
+	}
+	var reqs []json.RawMessage
+	if proxyd.IsBatch(body) {
+		if reqs, err = proxyd.ParseBatchRPCReq(body); err != nil {
+			panic(err)
+		}
+	} else {
+		reqs = []json.RawMessage{body}
+	}
+
+	out := make([]*proxyd.RPCRes, len(reqs))
+	for i := range reqs {
+		req, err := proxyd.ParseRPCReq(reqs[i])
+		if err != nil {
+			panic(err)
+		}
+		out[i] = &proxyd.RPCRes{JSONRPC: proxyd.JSONRPCVersion, ID: req.ID}
+		switch req.Method {
+		case "eth_blockNumber":
+			out[i].Result = "0x64"
+		case "eth_chainId":
+			out[i].Result = "0x1"
+		case "eth_getLogs":
+			var params []map[string]string
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				panic(err)
+			}
+			logs := make([]map[string]string, 0)
+			for n := blockParam(params[0]["fromBlock"]); n <= blockParam(params[0]["toBlock"]); n++ {
+				logs = append(logs, map[string]string{"blockNumber": hexutil.EncodeUint64(n)})
+			}
+			out[i].Result = logs
+		default:
+			w.WriteHeader(400)
+			return
+		}
+	}
+
+	var res interface{} = out
+	if !proxyd.IsBatch(body) {
+		res = out[0]
+	}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		panic(err)
+	}
+}
+
+func blockParam(tag string) uint64 {
+	switch tag {
+	case "", "latest":
+		return 100
+	case "earliest":
+		return 0
+	}
+	return hexutil.MustDecodeUint64(tag)
+}
+
+func logsResponse(from, to uint64) string {
+	logs := make([]string, 0)
+	for n := from; n <= to; n++ {
+		logs = append(logs, fmt.Sprintf(`{"blockNumber":"%s"}`, hexutil.EncodeUint64(n)))
+	}
+	return fmt.Sprintf(`{"jsonrpc":"2.0","result":[%s],"id":999}`, strings.Join(logs, ","))
+}
+
+func TestBlockRange(t *testing.T) {
+	goodBackend := NewMockBackend(http.HandlerFunc(logsHandler))
+	defer goodBackend.Close()
+
+	require.NoError(t, os.Setenv("GOOD_BACKEND_RPC_URL", goodBackend.URL()))
+
+	config := ReadConfig("block_range")
+	client := NewProxydClient("http://127.0.0.1:8545")
+	shutdown, err := proxyd.Start(config)
+	require.NoError(t, err)
+	defer shutdown()
+
+	// allow time for the block number fetcher to fire
+	time.Sleep(1500 * time.Millisecond)
+
+	logsFilter := func(from, to string) []interface{} {
+		return []interface{}{map[string]interface{}{"fromBlock": from, "toBlock": to}}
+	}
+
+	t.Run("range within limit", func(t *testing.T) {
+		goodBackend.Reset()
+		res, code, err := client.SendRPC("eth_getLogs", logsFilter("0x1", "0xa"))
+		require.NoError(t, err)
+		require.Equal(t, 200, code)
+		RequireEqualJSON(t, []byte(logsResponse(1, 10)), res)
+		require.Equal(t, 1, len(goodBackend.Requests()))
+	})
+
+	t.Run("split range", func(t *testing.T) {
+		goodBackend.Reset()
+		res, code, err := client.SendRPC("eth_getLogs", logsFilter("0x1", "0x19"))
+		require.NoError(t, err)
+		require.Equal(t, 200, code)
+		RequireEqualJSON(t, []byte(logsResponse(1, 25)), res)
+		require.Equal(t, 3, len(goodBackend.Requests()))
+	})
+
+	t.Run("split range up to latest", func(t *testing.T) {
+		goodBackend.Reset()
+		res, code, err := client.SendRPC("eth_getLogs", []interface{}{map[string]interface{}{"fromBlock": "0x50"}})
+		require.NoError(t, err)
+		require.Equal(t, 200, code)
+		RequireEqualJSON(t, []byte(logsResponse(80, 100)), res)
+		require.Equal(t, 3, len(goodBackend.Requests()))
+	})
+
+	t.Run("too many chunks", func(t *testing.T) {
+		goodBackend.Reset()
+		res, code, err := client.SendRPC("eth_getLogs", logsFilter("earliest", "latest"))
+		require.NoError(t, err)
+		require.Equal(t, 400, code)
+		RequireEqualJSON(t, []byte(blockRangeTooLargeResponse), res)
+		require.Equal(t, 0, len(goodBackend.Requests()))
+	})
+
+	t.Run("too many results", func(t *testing.T) {
+		res, code, err := client.SendRPC("eth_getLogs", logsFilter("0x1", "0x32"))
+		require.NoError(t, err)
+		require.Equal(t, 400, code)
+		RequireEqualJSON(t, []byte(tooManyResultsResponse), res)
+	})
+
+	t.Run("batch with split range", func(t *testing.T) {
+		res, code, err := client.SendBatchRPC(
+			NewRPCReq("1", "eth_getLogs", logsFilter("0x1", "0x14")),
+			NewRPCReq("2", "eth_chainId", nil),
+			NewRPCReq("3", "eth_getLogs", logsFilter("earliest", "latest")),
+		)
+		require.NoError(t, err)
+		require.Equal(t, 200, code)
+
+		var out []*proxyd.RPCRes
+		require.NoError(t, json.Unmarshal(res, &out))
+		require.Equal(t, 3, len(out))
+		require.Nil(t, out[0].Error)
+		require.Equal(t, 20, len(out[0].Result.([]interface{})))
+		require.Equal(t, "0x1", out[1].Result)
+		require.Equal(t, proxyd.ErrBlockRangeTooLarge.Code, out[2].Error.Code)
+	})
+}
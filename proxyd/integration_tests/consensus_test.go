@@ -0,0 +1,197 @@
+package integration_tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mantlenetworkio/mantle/proxyd"
+	"github.com/stretchr/testify/require"
+)
+
+// chainHandler mocks an l2geth node with a chain of the given head, the node
+// has its own block hashes from forkBlock on
+type chainHandler struct {
+	name      string
+	mtx       sync.Mutex
+	head      uint64
+	forkBlock uint64
+	syncing   bool
+}
+
+func (h *chainHandler) set(head, forkBlock uint64, syncing bool) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.head = head
+	h.forkBlock = forkBlock
+	h.syncing = syncing
+}
+
+func (h *chainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		panic(err)
+	}
+	req, err := proxyd.ParseRPCReq(body)
+	if err != nil {
+		panic(err)
+	}
+
+	var result interface{}
+	switch req.Method {
+	case "eth_chainId":
+		result = h.name
+	case "rollup_getInfo":
+		result = map[string]interface{}{"syncing": h.syncing}
+	case "eth_getBlockByNumber":
+		var params []interface{}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			panic(err)
+		}
+		number := h.head
+		if tag := params[0].(string); tag != "latest" && tag != "safe" {
+			number = hexutil.MustDecodeUint64(tag)
+		}
+		if number > h.head {
+			break
+		}
+		hash := fmt.Sprintf("0x%064x", number)
+		if h.forkBlock != 0 && number >= h.forkBlock {
+			hash = fmt.Sprintf("0x%064x", number+1000)
+		}
+		result = map[string]interface{}{
+			"number": hexutil.Uint64(number),
+			"hash":   hash,
+		}
+	default:
+		w.WriteHeader(400)
+		return
+	}
+
+	res := &proxyd.RPCRes{
+		JSONRPC: proxyd.JSONRPCVersion,
+		Result:  result,
+		ID:      req.ID,
+	}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		panic(err)
+	}
+}
+
+func TestConsensus(t *testing.T) {
+	nodes := make([]*chainHandler, 3)
+	for i := range nodes {
+		nodes[i] = &chainHandler{name: fmt.Sprintf("node%d", i+1), head: 10}
+		backend := NewMockBackend(nodes[i])
+		defer backend.Close()
+		require.NoError(t, os.Setenv(fmt.Sprintf("NODE%d_URL", i+1), backend.URL()))
+	}
+
+	config := ReadConfig("consensus")
+	client := NewProxydClient("http://127.0.0.1:8545")
+	shutdown, err := proxyd.Start(config)
+	require.NoError(t, err)
+	defer shutdown()
+
+	servedBy := func() string {
+		res, _, err := client.SendRPC("eth_chainId", nil)
+		require.NoError(t, err)
+		var rpcRes proxyd.RPCRes
+		require.NoError(t, json.Unmarshal(res, &rpcRes))
+		if rpcRes.IsError() {
+			return ""
+		}
+		return rpcRes.Result.(string)
+	}
+	blockNumber := func() string {
+		res, _, err := client.SendRPC("eth_blockNumber", nil)
+		require.NoError(t, err)
+		var rpcRes proxyd.RPCRes
+		require.NoError(t, json.Unmarshal(res, &rpcRes))
+		if rpcRes.IsError() {
+			return ""
+		}
+		return rpcRes.Result.(string)
+	}
+
+	t.Run("consensus block is the lowest head in sync", func(t *testing.T) {
+		nodes[1].set(11, 0, false)
+		nodes[2].set(12, 0, false)
+		require.Eventually(t, func() bool {
+			return blockNumber() == "0xa"
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, "node1", servedBy())
+
+		res, _, err := client.SendRPC("eth_getBlockByNumber", []interface{}{"latest", false})
+		require.NoError(t, err)
+		var rpcRes proxyd.RPCRes
+		require.NoError(t, json.Unmarshal(res, &rpcRes))
+		require.Equal(t, "0xa", rpcRes.Result.(map[string]interface{})["number"])
+	})
+
+	t.Run("blocks past the consensus block are out of range", func(t *testing.T) {
+		res, code, err := client.SendRPC("eth_getBlockByNumber", []interface{}{"0xc", false})
+		require.NoError(t, err)
+		require.Equal(t, 400, code)
+		RequireEqualJSON(t, []byte(`{"error":{"code":-32017,"message":"block is out of range"},"id":999,"jsonrpc":"2.0"}`), res)
+	})
+
+	t.Run("lagging backend is left out", func(t *testing.T) {
+		nodes[0].set(5, 0, false)
+		require.Eventually(t, func() bool {
+			return blockNumber() == "0xb" && servedBy() == "node2"
+		}, time.Second, 10*time.Millisecond)
+
+		nodes[0].set(12, 0, false)
+		require.Eventually(t, func() bool {
+			return servedBy() == "node1"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("syncing backend is left out", func(t *testing.T) {
+		nodes[0].set(12, 0, true)
+		require.Eventually(t, func() bool {
+			return servedBy() == "node2"
+		}, time.Second, 10*time.Millisecond)
+		nodes[0].set(12, 0, false)
+		require.Eventually(t, func() bool {
+			return servedBy() == "node1"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("forked backend is banned", func(t *testing.T) {
+		nodes[0].set(12, 5, false)
+		require.Eventually(t, func() bool {
+			return servedBy() == "node2"
+		}, time.Second, 10*time.Millisecond)
+
+		// The ban outlasts the fork
+		nodes[0].set(12, 0, false)
+		time.Sleep(200 * time.Millisecond)
+		require.Equal(t, "node2", servedBy())
+	})
+
+	t.Run("tie bans no backend", func(t *testing.T) {
+		// node2 and node3 are left and disagree, the group keeps its previous
+		// consensus instead of banning one of them or routing nowhere
+		nodes[2].set(12, 5, false)
+		time.Sleep(200 * time.Millisecond)
+		require.Equal(t, "node2", servedBy())
+		require.Equal(t, "0xb", blockNumber())
+
+		nodes[2].set(12, 0, false)
+		nodes[1].set(12, 0, true)
+		require.Eventually(t, func() bool {
+			return servedBy() == "node3"
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,145 @@
+package integration_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mantlenetworkio/mantle/proxyd"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	methodOverLimitResponse = `{"error":{"code":-32005,"message":"over rate limit"},"id":999,"jsonrpc":"2.0"}`
+	tierOverLimitResponse   = `{"error":{"code":-32005,"message":"over rate limit"},"id":null,"jsonrpc":"2.0"}`
+)
+
+func sendRPCWithRetryAfter(t *testing.T, url string, method string, params []interface{}) ([]byte, int, string) {
+	body, err := json.Marshal(NewRPCReq("999", method, params))
+	require.NoError(t, err)
+	res, err := http.Post(url, "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	return resBody, res.StatusCode, res.Header.Get("Retry-After")
+}
+
+func signedRawTx(t *testing.T, nonce uint64) (string, string) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := types.SignTx(
+		types.NewTransaction(nonce, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil),
+		types.NewEIP155Signer(big.NewInt(5000)),
+		key,
+	)
+	require.NoError(t, err)
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return hexutil.Encode(raw), crypto.PubkeyToAddress(key.PublicKey).Hex()
+}
+
+func TestFrontendRateLimitRules(t *testing.T) {
+	goodBackend := NewMockBackend(BatchedResponseHandler(200, goodResponse))
+	defer goodBackend.Close()
+
+	require.NoError(t, os.Setenv("GOOD_BACKEND_RPC_URL", goodBackend.URL()))
+
+	config := ReadConfig("frontend_rate_limit_rules")
+	shutdown, err := proxyd.Start(config)
+	require.NoError(t, err)
+	defer shutdown()
+
+	const (
+		alice = "http://127.0.0.1:8545/alice_key"
+		bob   = "http://127.0.0.1:8545/bob_key"
+		carol = "http://127.0.0.1:8545/carol_key"
+	)
+
+	t.Run("method limit per client", func(t *testing.T) {
+		_, code, _ := sendRPCWithRetryAfter(t, bob, "eth_getLogs", []interface{}{map[string]interface{}{}})
+		require.Equal(t, 200, code)
+		res, code, retryAfter := sendRPCWithRetryAfter(t, bob, "eth_getLogs", []interface{}{map[string]interface{}{}})
+		require.Equal(t, 429, code)
+		RequireEqualJSON(t, []byte(methodOverLimitResponse), res)
+		seconds, err := strconv.Atoi(retryAfter)
+		require.NoError(t, err)
+		require.True(t, seconds >= 1 && seconds <= 10)
+
+		_, code, _ = sendRPCWithRetryAfter(t, carol, "eth_getLogs", []interface{}{map[string]interface{}{}})
+		require.Equal(t, 200, code)
+	})
+
+	t.Run("global wildcard method limit", func(t *testing.T) {
+		_, code, _ := sendRPCWithRetryAfter(t, bob, "debug_traceTransaction", []interface{}{"0x01"})
+		require.Equal(t, 200, code)
+		_, code, retryAfter := sendRPCWithRetryAfter(t, alice, "debug_traceTransaction", []interface{}{"0x01"})
+		require.Equal(t, 429, code)
+		require.NotEmpty(t, retryAfter)
+	})
+
+	time.Sleep(1100 * time.Millisecond)
+
+	t.Run("auth key tiers", func(t *testing.T) {
+		_, codes := spamReqs(t, NewProxydClient(alice), 429)
+		require.Equal(t, 2, codes[200])
+		require.Equal(t, 1, codes[429])
+
+		_, codes = spamReqs(t, NewProxydClient(bob), 429)
+		require.Equal(t, 3, codes[200])
+
+		// Auth keys without a tier get the per IP limit
+		_, codes = spamReqs(t, NewProxydClient(carol), 429)
+		require.Equal(t, 1, codes[200])
+		require.Equal(t, 2, codes[429])
+	})
+
+	time.Sleep(1100 * time.Millisecond)
+
+	t.Run("auth keys without a tier are limited per IP", func(t *testing.T) {
+		fromIP := func(url, ip string) *ProxydHTTPClient {
+			headers := make(http.Header)
+			headers.Set("X-Forwarded-For", ip)
+			return NewProxydClientWithHeaders(url, headers)
+		}
+
+		limitedRes, codes := spamReqs(t, fromIP(carol, "1.1.1.1"), 429)
+		require.Equal(t, 1, codes[200])
+		require.Equal(t, 2, codes[429])
+		RequireEqualJSON(t, []byte(frontendOverLimitResponse), limitedRes)
+		_, codes = spamReqs(t, fromIP(carol, "2.2.2.2"), 429)
+		require.Equal(t, 1, codes[200])
+		require.Equal(t, 2, codes[429])
+
+		// Tiered auth keys share their limit among IPs
+		_, codes = spamReqs(t, fromIP(alice, "3.3.3.3"), 429)
+		require.Equal(t, 2, codes[200])
+		limitedRes, codes = spamReqs(t, fromIP(alice, "4.4.4.4"), 429)
+		require.Equal(t, 3, codes[429])
+		RequireEqualJSON(t, []byte(tierOverLimitResponse), limitedRes)
+	})
+
+	t.Run("sender limit", func(t *testing.T) {
+		rawTx, _ := signedRawTx(t, 0)
+		_, code, _ := sendRPCWithRetryAfter(t, bob, "eth_sendRawTransaction", []interface{}{rawTx})
+		require.Equal(t, 200, code)
+
+		// Same sender
+		_, code, _ = sendRPCWithRetryAfter(t, bob, "eth_sendRawTransaction", []interface{}{rawTx})
+		require.Equal(t, 429, code)
+
+		otherTx, _ := signedRawTx(t, 0)
+		_, code, _ = sendRPCWithRetryAfter(t, bob, "eth_sendRawTransaction", []interface{}{otherTx})
+		require.Equal(t, 200, code)
+	})
+}
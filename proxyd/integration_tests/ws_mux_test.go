@@ -0,0 +1,249 @@
+package integration_tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mantlenetworkio/mantle/proxyd"
+	"github.com/stretchr/testify/require"
+)
+
+// subscriptionBackend is a websocket backend that accepts every subscription
+// and sends notifications on demand
+type subscriptionBackend struct {
+	*MockWSBackend
+	name string
+
+	mu           sync.Mutex
+	conns        int
+	conn         *websocket.Conn
+	subscribes   []string
+	unsubscribes []string
+}
+
+func newSubscriptionBackend(name string) *subscriptionBackend {
+	b := &subscriptionBackend{name: name}
+	b.MockWSBackend = NewMockWSBackend(func(conn *websocket.Conn) {
+		b.mu.Lock()
+		b.conns++
+		b.mu.Unlock()
+	}, func(conn *websocket.Conn, msgType int, data []byte) {
+		req, err := proxyd.ParseRPCReq(data)
+		if err != nil {
+			panic(err)
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.conn = conn
+		var result interface{}
+		switch req.Method {
+		case "eth_subscribe":
+			b.subscribes = append(b.subscribes, string(req.Params))
+			result = fmt.Sprintf("%s-%d", b.name, len(b.subscribes))
+		case "eth_unsubscribe":
+			b.unsubscribes = append(b.unsubscribes, string(req.Params))
+			result = true
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, mustMarshal(proxyd.NewRPCRes(req.ID, result))); err != nil {
+			panic(err)
+		}
+	}, nil)
+	return b
+}
+
+func (b *subscriptionBackend) notify(t *testing.T, subID string, result string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	msg := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"%s","result":"%s"}}`, subID, result)
+	require.NoError(t, b.conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+}
+
+func (b *subscriptionBackend) calls() (int, []string, []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.conns, append([]string(nil), b.subscribes...), append([]string(nil), b.unsubscribes...)
+}
+
+func mustMarshal(in interface{}) []byte {
+	out, err := json.Marshal(in)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+type muxTestClient struct {
+	*ProxydWSClient
+	msgs chan map[string]interface{}
+}
+
+func newMuxTestClient(t *testing.T) *muxTestClient {
+	c := &muxTestClient{msgs: make(chan map[string]interface{}, 16)}
+	client, err := NewProxydWSClient("ws://127.0.0.1:8546", func(msgType int, data []byte) {
+		var msg map[string]interface{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			panic(err)
+		}
+		c.msgs <- msg
+	}, nil)
+	require.NoError(t, err)
+	c.ProxydWSClient = client
+	return c
+}
+
+func (c *muxTestClient) call(t *testing.T, req string) map[string]interface{} {
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(req)))
+	return c.next(t)
+}
+
+func (c *muxTestClient) next(t *testing.T) map[string]interface{} {
+	select {
+	case msg := <-c.msgs:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out")
+		return nil
+	}
+}
+
+func (c *muxTestClient) requireNoMessage(t *testing.T) {
+	select {
+	case msg := <-c.msgs:
+		t.Fatalf("unexpected message %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func notification(subID string, result string) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_subscription",
+		"params": map[string]interface{}{
+			"subscription": subID,
+			"result":       result,
+		},
+	}
+}
+
+func TestWSMultiplexing(t *testing.T) {
+	first := newSubscriptionBackend("first")
+	defer first.Close()
+	second := newSubscriptionBackend("second")
+	defer second.Close()
+	rpcBackend := NewMockBackend(BatchedResponseHandler(200, goodResponse))
+	defer rpcBackend.Close()
+
+	require.NoError(t, os.Setenv("FIRST_BACKEND_RPC_URL", rpcBackend.URL()))
+	require.NoError(t, os.Setenv("FIRST_BACKEND_WS_URL", first.URL()))
+	require.NoError(t, os.Setenv("SECOND_BACKEND_RPC_URL", rpcBackend.URL()))
+	require.NoError(t, os.Setenv("SECOND_BACKEND_WS_URL", second.URL()))
+
+	config := ReadConfig("ws_mux")
+	shutdown, err := proxyd.Start(config)
+	require.NoError(t, err)
+	defer shutdown()
+
+	// More clients than the backends allow WS connections
+	clients := []*muxTestClient{newMuxTestClient(t), newMuxTestClient(t), newMuxTestClient(t)}
+	defer func() {
+		for _, c := range clients {
+			c.HardClose()
+		}
+	}()
+
+	subIDs := make([]string, len(clients))
+	for i, c := range clients {
+		res := c.call(t, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"eth_subscribe","params":["newHeads"]}`, i))
+		require.Nil(t, res["error"])
+		require.Equal(t, float64(i), res["id"])
+		subIDs[i] = res["result"].(string)
+	}
+	require.NotEqual(t, subIDs[0], subIDs[1])
+
+	conns, subscribes, _ := first.calls()
+	require.Equal(t, 1, conns)
+	require.Equal(t, []string{`["newHeads"]`}, subscribes)
+
+	t.Run("fans out notifications", func(t *testing.T) {
+		first.notify(t, "first-1", "0x1")
+		for i, c := range clients {
+			require.Equal(t, notification(subIDs[i], "0x1"), c.next(t))
+		}
+	})
+
+	t.Run("shares identical logs filters", func(t *testing.T) {
+		res := clients[0].call(t, `{"jsonrpc":"2.0","id":1,"method":"eth_subscribe","params":["logs",{"topics":[],"address":"0x01"}]}`)
+		logsID := res["result"].(string)
+		res = clients[1].call(t, `{"jsonrpc":"2.0","id":1,"method":"eth_subscribe","params":["logs",{"address":"0x01","topics":[]}]}`)
+		otherLogsID := res["result"].(string)
+		require.NotEqual(t, logsID, otherLogsID)
+
+		_, subscribes, _ := first.calls()
+		require.Equal(t, 2, len(subscribes))
+
+		// Clients can only unsubscribe their own subscriptions
+		res = clients[1].call(t, fmt.Sprintf(`{"jsonrpc":"2.0","id":2,"method":"eth_unsubscribe","params":["%s"]}`, logsID))
+		require.Equal(t, float64(proxyd.ErrSubscriptionNotFound.Code), res["error"].(map[string]interface{})["code"])
+
+		for i, id := range []string{logsID, otherLogsID} {
+			res = clients[i].call(t, fmt.Sprintf(`{"jsonrpc":"2.0","id":2,"method":"eth_unsubscribe","params":["%s"]}`, id))
+			require.Equal(t, true, res["result"])
+		}
+		require.Eventually(t, func() bool {
+			_, _, unsubscribes := first.calls()
+			return len(unsubscribes) == 1 && unsubscribes[0] == `["first-2"]`
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("unsubscribes upstream after the last client", func(t *testing.T) {
+		res := clients[2].call(t, fmt.Sprintf(`{"jsonrpc":"2.0","id":3,"method":"eth_unsubscribe","params":["%s"]}`, subIDs[2]))
+		require.Equal(t, true, res["result"])
+		first.notify(t, "first-1", "0x2")
+		require.Equal(t, notification(subIDs[0], "0x2"), clients[0].next(t))
+		require.Equal(t, notification(subIDs[1], "0x2"), clients[1].next(t))
+		clients[2].requireNoMessage(t)
+
+		res = clients[2].call(t, `{"jsonrpc":"2.0","id":4,"method":"eth_subscribe","params":["logs",{"address":"0x02"}]}`)
+		logsID := res["result"].(string)
+		res = clients[2].call(t, fmt.Sprintf(`{"jsonrpc":"2.0","id":5,"method":"eth_unsubscribe","params":["%s"]}`, logsID))
+		require.Equal(t, true, res["result"])
+
+		require.Eventually(t, func() bool {
+			_, _, unsubscribes := first.calls()
+			return len(unsubscribes) == 2 && unsubscribes[1] == `["first-3"]`
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("forwards other methods over HTTP", func(t *testing.T) {
+		res := clients[2].call(t, `{"jsonrpc":"2.0","id":999,"method":"eth_chainId","params":[]}`)
+		require.Equal(t, "hello", res["result"])
+
+		// The method limits of HTTP requests apply as well
+		res = clients[2].call(t, `{"jsonrpc":"2.0","id":999,"method":"eth_chainId","params":[]}`)
+		require.Equal(t, float64(-32005), res["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("keeps whitelist", func(t *testing.T) {
+		res := clients[2].call(t, `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":[]}`)
+		require.Equal(t, float64(-32001), res["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("resubscribes on failover", func(t *testing.T) {
+		first.Close()
+		require.Eventually(t, func() bool {
+			_, subscribes, _ := second.calls()
+			return len(subscribes) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		_, subscribes, _ := second.calls()
+		require.Equal(t, `["newHeads"]`, subscribes[0])
+
+		second.notify(t, "second-1", "0x3")
+		require.Equal(t, notification(subIDs[0], "0x3"), clients[0].next(t))
+		require.Equal(t, notification(subIDs[1], "0x3"), clients[1].next(t))
+	})
+}
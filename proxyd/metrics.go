@@ -4,6 +4,7 @@ import (
 	"context"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -222,6 +223,114 @@ var (
 	}, []string{
 		"backend_name",
 	})
+
+	consensusLatestBlockGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "group_consensus_latest_block",
+		Help:      "Latest block the backends of the group agree on.",
+	}, []string{
+		"backend_group_name",
+	})
+
+	consensusSafeBlockGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "group_consensus_safe_block",
+		Help:      "Safe block the backends of the group agree on.",
+	}, []string{
+		"backend_group_name",
+	})
+
+	consensusBackendsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "group_consensus_count",
+		Help:      "Number of backends in the consensus of the group.",
+	}, []string{
+		"backend_group_name",
+	})
+
+	backendLatestBlockGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "backend_latest_block",
+		Help:      "Latest block reported by the backend.",
+	}, []string{
+		"backend_name",
+	})
+
+	backendBannedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "backend_banned",
+		Help:      "Whether the backend is banned for disagreeing with the consensus of its group.",
+	}, []string{
+		"backend_name",
+	})
+
+	groupRoutedRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "group_routed_requests_total",
+		Help:      "Count of requests and WS connections a backend group routed to each of its backends.",
+	}, []string{
+		"backend_group_name",
+		"backend_name",
+		"source",
+	})
+
+	backendLatencyEWMAGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "backend_latency_ewma_seconds",
+		Help:      "Moving average of the backend response time used for least latency routing.",
+	}, []string{
+		"backend_name",
+	})
+
+	backendInConsensusGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "backend_in_consensus",
+		Help:      "Whether the backend is part of the consensus of the group.",
+	}, []string{
+		"backend_group_name",
+		"backend_name",
+	})
+
+	wsMuxSubscriptionsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "ws_mux_upstream_subscriptions",
+		Help:      "Gauge of upstream subscriptions shared by the WS clients of a backend group.",
+	}, []string{
+		"backend_group_name",
+	})
+
+	wsMuxClientSubscriptionsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "ws_mux_client_subscriptions",
+		Help:      "Gauge of client subscriptions served from the shared upstream subscriptions.",
+	}, []string{
+		"backend_group_name",
+	})
+
+	wsMuxResubscriptionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "ws_mux_resubscriptions_total",
+		Help:      "Count of times the shared subscriptions were moved to another upstream connection.",
+	}, []string{
+		"backend_group_name",
+		"backend_name",
+	})
+
+	splitRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "split_requests_total",
+		Help:      "Count of requests split into chunks for spanning too many blocks.",
+	}, []string{
+		"method",
+	})
+
+	splitRequestChunksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "split_request_chunks_total",
+		Help:      "Count of chunks the split requests were forwarded as.",
+	}, []string{
+		"method",
+	})
 )
 
 func RecordRedisError(source string) {
@@ -278,3 +387,44 @@ func RecordCacheHit(method string) {
 func RecordCacheMiss(method string) {
 	cacheMissesTotal.WithLabelValues(method).Inc()
 }
+
+func RecordGroupConsensus(groupName string, latest, safe uint64, count int) {
+	consensusLatestBlockGauge.WithLabelValues(groupName).Set(float64(latest))
+	consensusSafeBlockGauge.WithLabelValues(groupName).Set(float64(safe))
+	consensusBackendsGauge.WithLabelValues(groupName).Set(float64(count))
+}
+
+func RecordBackendConsensusState(groupName, backendName string, latest uint64, banned, inConsensus bool) {
+	backendLatestBlockGauge.WithLabelValues(backendName).Set(float64(latest))
+	backendBannedGauge.WithLabelValues(backendName).Set(boolToFloat64(banned))
+	backendInConsensusGauge.WithLabelValues(groupName, backendName).Set(boolToFloat64(inConsensus))
+}
+
+func RecordGroupRoutedRequest(groupName, backendName, source string) {
+	groupRoutedRequestsTotal.WithLabelValues(groupName, backendName, source).Inc()
+}
+
+func RecordBackendLatency(backendName string, latency time.Duration) {
+	backendLatencyEWMAGauge.WithLabelValues(backendName).Set(latency.Seconds())
+}
+
+func RecordSplitRequest(method string, chunks int) {
+	splitRequestsTotal.WithLabelValues(method).Inc()
+	splitRequestChunksTotal.WithLabelValues(method).Add(float64(chunks))
+}
+
+func RecordWSMuxSubscriptions(groupName string, upstream, clients int) {
+	wsMuxSubscriptionsGauge.WithLabelValues(groupName).Set(float64(upstream))
+	wsMuxClientSubscriptionsGauge.WithLabelValues(groupName).Set(float64(clients))
+}
+
+func RecordWSMuxResubscription(groupName, backendName string) {
+	wsMuxResubscriptionsTotal.WithLabelValues(groupName, backendName).Inc()
+}
+
+func boolToFloat64(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -118,6 +118,12 @@ func Start(config *Config) (func(), error) {
 		if cfg.StripTrailingXFF {
 			opts = append(opts, WithStrippedTrailingXFF())
 		}
+		if cfg.Weight < 0 {
+			return nil, fmt.Errorf("weight of backend %s must not be negative", name)
+		}
+		if cfg.Weight != 0 {
+			opts = append(opts, WithWeight(cfg.Weight))
+		}
 		opts = append(opts, WithProxydIP(os.Getenv("PROXYD_IP")))
 		back := NewBackend(name, rpcURL, wsURL, lim, rpcRequestSemaphore, opts...)
 		backendNames = append(backendNames, name)
@@ -134,9 +140,28 @@ func Start(config *Config) (func(), error) {
 			}
 			backends = append(backends, backendsByName[bName])
 		}
+		strategy, err := ParseRoutingStrategy(bg.RoutingStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("backend group %s: %w", bgName, err)
+		}
 		group := &BackendGroup{
 			Name:     bgName,
 			Backends: backends,
+			Strategy: strategy,
+		}
+		if bg.ConsensusAware {
+			opts := make([]ConsensusOpt, 0)
+			if bg.ConsensusPollIntervalMs != 0 {
+				opts = append(opts, WithConsensusPollInterval(time.Duration(bg.ConsensusPollIntervalMs)*time.Millisecond))
+			}
+			if bg.ConsensusMaxBlockLag != 0 {
+				opts = append(opts, WithConsensusMaxBlockLag(bg.ConsensusMaxBlockLag))
+			}
+			if bg.ConsensusBanPeriodSeconds != 0 {
+				opts = append(opts, WithConsensusBanPeriod(secondsToDuration(bg.ConsensusBanPeriodSeconds)))
+			}
+			group.Consensus = NewConsensusPoller(group, opts...)
+			log.Info("configured consensus aware backend group", "name", bgName)
 		}
 		backendGroups[bgName] = group
 	}
@@ -159,6 +184,19 @@ func Start(config *Config) (func(), error) {
 		}
 	}
 
+	// Multiplexed WS clients call the other methods like HTTP clients do
+	if config.WSMultiplexing {
+		for _, method := range config.WSMethodWhitelist {
+			switch method {
+			case "eth_subscribe", "eth_unsubscribe", "eth_accounts":
+				continue
+			}
+			if config.RPCMethodMappings[method] == "" {
+				return nil, fmt.Errorf("ws method %s must be in the rpc method mappings to be multiplexed", method)
+			}
+		}
+	}
+
 	var resolvedAuth map[string]string
 
 	if config.Authentication != nil {
@@ -172,20 +210,26 @@ func Start(config *Config) (func(), error) {
 		}
 	}
 
+	if config.BlockRange.SplitRequests && config.BlockRange.MaxBlockRange == 0 {
+		return nil, errors.New("must specify a max block range to split requests")
+	}
+
 	var (
 		rpcCache    RPCCache
 		blockNumLVC *EthLastValueCache
 		gasPriceLVC *EthLastValueCache
+		blockNumFn  GetLatestBlockNumFn
 	)
-	if config.Cache.Enabled {
+	// Block range limits resolve block tags with the latest block number of
+	// the cache
+	if config.Cache.Enabled || config.BlockRange.MaxBlockRange > 0 {
 		var (
 			cache      Cache
-			blockNumFn GetLatestBlockNumFn
 			gasPriceFn GetLatestGasPriceFn
 		)
 
 		if config.Cache.BlockSyncRPCURL == "" {
-			return nil, fmt.Errorf("block sync node required for caching")
+			return nil, fmt.Errorf("block sync node required for caching and block range limits")
 		}
 		blockSyncRPCURL, err := ReadFromEnvOrConfig(config.Cache.BlockSyncRPCURL)
 		if err != nil {
@@ -208,14 +252,28 @@ func Start(config *Config) (func(), error) {
 		defer ethClient.Close()
 
 		blockNumLVC, blockNumFn = makeGetLatestBlockNumFn(ethClient, cache)
-		gasPriceLVC, gasPriceFn = makeGetLatestGasPriceFn(ethClient, cache)
-		rpcCache = newRPCCache(newCacheWithCompression(cache), blockNumFn, gasPriceFn, config.Cache.NumBlockConfirmations)
+		if config.Cache.Enabled {
+			gasPriceLVC, gasPriceFn = makeGetLatestGasPriceFn(ethClient, cache)
+			rpcCache = newRPCCache(newCacheWithCompression(cache), blockNumFn, gasPriceFn, config.Cache.NumBlockConfirmations)
+		}
+	}
+
+	limiterFactory := NewMemoryServerRateLimiterFactory()
+	if config.RateLimit.UseRedis {
+		if redisURL == "" {
+			return nil, errors.New("must specify a redis URL to use redis for rate limiting")
+		}
+		limiterFactory, err = NewRedisServerRateLimiterFactory(redisURL)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	srv, err := NewServer(
 		backendGroups,
 		wsBackendGroup,
 		NewStringSetFromStrings(config.WSMethodWhitelist),
+		config.WSMultiplexing,
 		config.RPCMethodMappings,
 		config.Server.MaxBodySizeBytes,
 		resolvedAuth,
@@ -223,6 +281,8 @@ func Start(config *Config) (func(), error) {
 		config.Server.MaxUpstreamBatchSize,
 		rpcCache,
 		config.RateLimit,
+		limiterFactory,
+		NewBlockRangeLimiter(config.BlockRange, blockNumFn),
 		config.Server.EnableRequestLog,
 		config.Server.MaxRequestBodyLogLen,
 	)
@@ -269,6 +329,12 @@ func Start(config *Config) (func(), error) {
 		}()
 	}
 
+	for _, bg := range backendGroups {
+		if bg.Consensus != nil {
+			bg.Consensus.Start()
+		}
+	}
+
 	<-errTimer.C
 	log.Info("started proxyd")
 
@@ -280,6 +346,11 @@ func Start(config *Config) (func(), error) {
 		if gasPriceLVC != nil {
 			gasPriceLVC.Stop()
 		}
+		for _, bg := range backendGroups {
+			if bg.Consensus != nil {
+				bg.Consensus.Stop()
+			}
+		}
 		srv.Shutdown()
 		if err := lim.FlushBackendWSConns(backendNames); err != nil {
 			log.Error("error flushing backend ws conns", "err", err)
@@ -264,5 +264,123 @@ func randStr(l int) string {
 	return hex.EncodeToString(b)
 }
 
-type ServerRateLimiter struct {
+const ServerRateLimitScript = `
+local current
+current = redis.call("incr", KEYS[1])
+if current == 1 then
+    redis.call("pexpire", KEYS[1], ARGV[1])
+end
+return {current, redis.call("pttl", KEYS[1])}
+`
+
+// ServerRateLimiter limits the requests clients send to proxyd. Every key has
+// a budget of requests that resets at the end of each interval.
+type ServerRateLimiter interface {
+	// Take consumes a request from the budget of the key. It returns whether
+	// the budget allowed the request and the time left until it resets.
+	Take(ctx context.Context, key string) (bool, time.Duration, error)
+}
+
+// ServerRateLimiterFactory creates a limiter allowing max requests per
+// interval, the prefix separates the keys of the limiters sharing a store
+type ServerRateLimiterFactory func(prefix string, max int, interval time.Duration) ServerRateLimiter
+
+type MemoryServerRateLimiter struct {
+	max       int
+	interval  time.Duration
+	windows   map[string]*rateWindow
+	nextPurge time.Time
+	mtx       sync.Mutex
+}
+
+type rateWindow struct {
+	count int
+	reset time.Time
+}
+
+func NewMemoryServerRateLimiter(max int, interval time.Duration) *MemoryServerRateLimiter {
+	return &MemoryServerRateLimiter{
+		max:      max,
+		interval: interval,
+		windows:  make(map[string]*rateWindow),
+	}
+}
+
+func (m *MemoryServerRateLimiter) Take(ctx context.Context, key string) (bool, time.Duration, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := time.Now()
+	if now.After(m.nextPurge) {
+		for k, w := range m.windows {
+			if !now.Before(w.reset) {
+				delete(m.windows, k)
+			}
+		}
+		m.nextPurge = now.Add(m.interval)
+	}
+
+	w := m.windows[key]
+	if w == nil || !now.Before(w.reset) {
+		w = &rateWindow{reset: now.Add(m.interval)}
+		m.windows[key] = w
+	}
+	w.count++
+	return w.count <= m.max, w.reset.Sub(now), nil
+}
+
+type RedisServerRateLimiter struct {
+	rdb      *redis.Client
+	prefix   string
+	max      int
+	interval time.Duration
+}
+
+func (r *RedisServerRateLimiter) Take(ctx context.Context, key string) (bool, time.Duration, error) {
+	res, err := r.rdb.Eval(
+		ctx,
+		ServerRateLimitScript,
+		[]string{fmt.Sprintf("ratelimit:%s:%s", r.prefix, key)},
+		r.interval.Milliseconds(),
+	).Slice()
+	if err != nil {
+		RecordRedisError("ServerRateLimiterTake")
+		return false, 0, wrapErr(err, "error taking from rate limit")
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result %v", res)
+	}
+	count, _ := res[0].(int64)
+	ttl, _ := res[1].(int64)
+	if ttl < 0 {
+		ttl = 0
+	}
+	return count <= int64(r.max), time.Duration(ttl) * time.Millisecond, nil
+}
+
+func NewMemoryServerRateLimiterFactory() ServerRateLimiterFactory {
+	return func(prefix string, max int, interval time.Duration) ServerRateLimiter {
+		return NewMemoryServerRateLimiter(max, interval)
+	}
+}
+
+// NewRedisServerRateLimiterFactory creates limiters sharing their budgets with
+// every proxyd instance using the same redis
+func NewRedisServerRateLimiterFactory(url string) (ServerRateLimiterFactory, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	rdb := redis.NewClient(opts)
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		return nil, wrapErr(err, "error connecting to redis")
+	}
+	return func(prefix string, max int, interval time.Duration) ServerRateLimiter {
+		return &RedisServerRateLimiter{
+			rdb:      rdb,
+			prefix:   prefix,
+			max:      max,
+			interval: interval,
+		}
+	}, nil
 }
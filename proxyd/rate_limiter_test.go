@@ -0,0 +1,57 @@
+package proxyd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerRateLimiters(t *testing.T) {
+	redis, err := miniredis.Run()
+	require.NoError(t, err)
+	defer redis.Close()
+
+	redisFactory, err := NewRedisServerRateLimiterFactory("redis://" + redis.Addr())
+	require.NoError(t, err)
+
+	factories := map[string]ServerRateLimiterFactory{
+		"memory": NewMemoryServerRateLimiterFactory(),
+		"redis":  redisFactory,
+	}
+	for name, factory := range factories {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			lim := factory("test:"+name, 2, time.Minute)
+			for i := 0; i < 2; i++ {
+				ok, retryAfter, err := lim.Take(ctx, "a")
+				require.NoError(t, err)
+				require.True(t, ok)
+				require.Greater(t, retryAfter, 59*time.Second)
+			}
+			ok, retryAfter, err := lim.Take(ctx, "a")
+			require.NoError(t, err)
+			require.False(t, ok)
+			require.LessOrEqual(t, retryAfter, time.Minute)
+
+			// Every key has its own budget
+			ok, _, err = lim.Take(ctx, "b")
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func TestMemoryServerRateLimiterReset(t *testing.T) {
+	ctx := context.Background()
+	lim := NewMemoryServerRateLimiter(1, 50*time.Millisecond)
+	ok, _, _ := lim.Take(ctx, "a")
+	require.True(t, ok)
+	ok, _, _ = lim.Take(ctx, "a")
+	require.False(t, ok)
+	time.Sleep(60 * time.Millisecond)
+	ok, _, _ = lim.Take(ctx, "a")
+	require.True(t, ok)
+}
@@ -0,0 +1,162 @@
+package proxyd
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RewriteContext holds the block heights the backends of a group agreed on,
+// a zero safe block means the backends do not report one
+type RewriteContext struct {
+	latest hexutil.Uint64
+	safe   hexutil.Uint64
+}
+
+type RewriteResult uint8
+
+const (
+	// RewriteNone means the request is forwarded as it is
+	RewriteNone RewriteResult = iota
+
+	// RewriteOverrideError means the request must be answered with the returned error
+	RewriteOverrideError
+
+	// RewriteOverrideRequest means the block tags of the request were rewritten
+	RewriteOverrideRequest
+
+	// RewriteOverrideResponse means proxyd answers the request itself
+	RewriteOverrideResponse
+)
+
+var ErrRewriteBlockOutOfRange = errors.New("block is out of range")
+
+// RewriteRequest pins the block tags of the request to the consensus heights,
+// so that clients never read past the block every backend of the group has.
+// eth_blockNumber is answered with the consensus latest block.
+func RewriteRequest(rctx RewriteContext, req *RPCReq, res *RPCRes) (RewriteResult, error) {
+	switch req.Method {
+	case "eth_blockNumber":
+		res.JSONRPC = JSONRPCVersion
+		res.ID = req.ID
+		res.Result = rctx.latest
+		return RewriteOverrideResponse, nil
+	case "eth_getLogs":
+		// Filters installed with eth_newFilter keep their tags, pinning them
+		// would freeze the filter at the height it was installed at
+		return rewriteRange(rctx, req)
+	case "eth_getBalance",
+		"eth_getCode",
+		"eth_getTransactionCount",
+		"eth_call":
+		return rewriteParam(rctx, req, 1)
+	case "eth_getStorageAt",
+		"eth_getProof":
+		return rewriteParam(rctx, req, 2)
+	case "eth_getBlockByNumber",
+		"eth_getBlockTransactionCountByNumber",
+		"eth_getUncleCountByBlockNumber",
+		"eth_getTransactionByBlockNumberAndIndex",
+		"eth_getUncleByBlockNumberAndIndex":
+		return rewriteParam(rctx, req, 0)
+	}
+	return RewriteNone, nil
+}
+
+func rewriteParam(rctx RewriteContext, req *RPCReq, pos int) (RewriteResult, error) {
+	var p []json.RawMessage
+	if err := json.Unmarshal(req.Params, &p); err != nil || len(p) <= pos {
+		// Malformed requests are left for the backend to reject
+		return RewriteNone, nil
+	}
+
+	val, changed, err := rewriteTag(rctx, p[pos])
+	if err != nil {
+		return RewriteOverrideError, err
+	}
+	if !changed {
+		return RewriteNone, nil
+	}
+	p[pos] = val
+	req.Params = mustMarshalJSON(p)
+	return RewriteOverrideRequest, nil
+}
+
+func rewriteRange(rctx RewriteContext, req *RPCReq) (RewriteResult, error) {
+	var p []map[string]json.RawMessage
+	if err := json.Unmarshal(req.Params, &p); err != nil || len(p) == 0 {
+		return RewriteNone, nil
+	}
+
+	// Filters by block hash have no range to rewrite
+	if _, ok := p[0]["blockHash"]; ok {
+		return RewriteNone, nil
+	}
+
+	changed := false
+	for _, key := range []string{"fromBlock", "toBlock"} {
+		val, ok := p[0][key]
+		if !ok {
+			// An absent bound defaults to the latest block
+			p[0][key] = mustMarshalJSON(rctx.latest)
+			changed = true
+			continue
+		}
+		rewritten, c, err := rewriteTag(rctx, val)
+		if err != nil {
+			return RewriteOverrideError, err
+		}
+		if c {
+			p[0][key] = rewritten
+			changed = true
+		}
+	}
+	if !changed {
+		return RewriteNone, nil
+	}
+	req.Params = mustMarshalJSON(p)
+	return RewriteOverrideRequest, nil
+}
+
+// rewriteTag rewrites a block number, block tag or EIP-1898 block object
+func rewriteTag(rctx RewriteContext, val json.RawMessage) (json.RawMessage, bool, error) {
+	var tag string
+	if err := json.Unmarshal(val, &tag); err != nil {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(val, &obj); err != nil {
+			return nil, false, nil
+		}
+		num, ok := obj["blockNumber"]
+		if !ok {
+			return nil, false, nil
+		}
+		rewritten, changed, err := rewriteTag(rctx, num)
+		if err != nil || !changed {
+			return nil, false, err
+		}
+		obj["blockNumber"] = rewritten
+		return mustMarshalJSON(obj), true, nil
+	}
+
+	switch tag {
+	case "latest":
+		return mustMarshalJSON(rctx.latest), true, nil
+	case "safe":
+		if rctx.safe == 0 {
+			return nil, false, nil
+		}
+		return mustMarshalJSON(rctx.safe), true, nil
+	case "earliest", "pending", "finalized":
+		return nil, false, nil
+	}
+
+	num, err := hexutil.DecodeUint64(tag)
+	if err != nil {
+		return nil, false, nil
+	}
+	if num > uint64(rctx.latest) {
+		return nil, false, ErrRewriteBlockOutOfRange
+	}
+	return nil, false, nil
+}
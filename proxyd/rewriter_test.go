@@ -0,0 +1,52 @@
+package proxyd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteRequest(t *testing.T) {
+	rctx := RewriteContext{latest: 100, safe: 90}
+
+	tests := []struct {
+		name   string
+		method string
+		params string
+		result RewriteResult
+		err    error
+		out    string
+	}{
+		{"latest tag", "eth_getBlockByNumber", `["latest",false]`, RewriteOverrideRequest, nil, `["0x64",false]`},
+		{"safe tag", "eth_getBalance", `["0x1","safe"]`, RewriteOverrideRequest, nil, `["0x1","0x5a"]`},
+		{"number in range", "eth_getBlockByNumber", `["0x10",false]`, RewriteNone, nil, `["0x10",false]`},
+		{"number out of range", "eth_getBlockByNumber", `["0x65",false]`, RewriteOverrideError, ErrRewriteBlockOutOfRange, `["0x65",false]`},
+		{"pending tag", "eth_getTransactionCount", `["0x1","pending"]`, RewriteNone, nil, `["0x1","pending"]`},
+		{"block object", "eth_call", `[{},{"blockNumber":"latest"}]`, RewriteOverrideRequest, nil, `[{},{"blockNumber":"0x64"}]`},
+		{"block hash object", "eth_call", `[{},{"blockHash":"0x01"}]`, RewriteNone, nil, `[{},{"blockHash":"0x01"}]`},
+		{"storage slot", "eth_getStorageAt", `["0x1","0x0","latest"]`, RewriteOverrideRequest, nil, `["0x1","0x0","0x64"]`},
+		{"missing param", "eth_getBalance", `["0x1"]`, RewriteNone, nil, `["0x1"]`},
+		{"logs default range", "eth_getLogs", `[{}]`, RewriteOverrideRequest, nil, `[{"fromBlock":"0x64","toBlock":"0x64"}]`},
+		{"logs range", "eth_getLogs", `[{"fromBlock":"0x1","toBlock":"latest"}]`, RewriteOverrideRequest, nil, `[{"fromBlock":"0x1","toBlock":"0x64"}]`},
+		{"logs block hash", "eth_getLogs", `[{"blockHash":"0x01"}]`, RewriteNone, nil, `[{"blockHash":"0x01"}]`},
+		{"new filter", "eth_newFilter", `[{"fromBlock":"latest"}]`, RewriteNone, nil, `[{"fromBlock":"latest"}]`},
+		{"other method", "eth_chainId", `[]`, RewriteNone, nil, `[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &RPCReq{JSONRPC: JSONRPCVersion, Method: tt.method, Params: json.RawMessage(tt.params), ID: []byte("1")}
+			result, err := RewriteRequest(rctx, req, new(RPCRes))
+			require.Equal(t, tt.result, result)
+			require.Equal(t, tt.err, err)
+			require.JSONEq(t, tt.out, string(req.Params))
+		})
+	}
+
+	req := &RPCReq{JSONRPC: JSONRPCVersion, Method: "eth_blockNumber", Params: json.RawMessage(`[]`), ID: []byte("1")}
+	res := new(RPCRes)
+	result, err := RewriteRequest(rctx, req, res)
+	require.NoError(t, err)
+	require.Equal(t, RewriteOverrideResponse, result)
+	require.Equal(t, `{"jsonrpc":"2.0","result":"0x64","id":1}`, string(mustMarshalJSON(res)))
+}
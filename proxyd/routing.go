@@ -0,0 +1,119 @@
+package proxyd
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RoutingStrategy decides the order the backends of a group are tried in
+type RoutingStrategy string
+
+const (
+	// RoutingStrategyPriority tries the backends in the order of the config
+	RoutingStrategyPriority RoutingStrategy = "priority"
+	// RoutingStrategyRoundRobin starts at the next backend on every request
+	RoutingStrategyRoundRobin RoutingStrategy = "round_robin"
+	// RoutingStrategyWeighted picks the backends at random proportionally to their weight
+	RoutingStrategyWeighted RoutingStrategy = "weighted"
+	// RoutingStrategyLeastLatency tries the backends with the lowest average response time first
+	RoutingStrategyLeastLatency RoutingStrategy = "least_latency"
+)
+
+const latencyEWMAAlpha = 0.2
+
+func ParseRoutingStrategy(s string) (RoutingStrategy, error) {
+	switch strategy := RoutingStrategy(s); strategy {
+	case "":
+		return RoutingStrategyPriority, nil
+	case RoutingStrategyPriority, RoutingStrategyRoundRobin, RoutingStrategyWeighted, RoutingStrategyLeastLatency:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("invalid routing strategy %s", s)
+	}
+}
+
+// latencyEWMA is an exponentially weighted moving average of the response
+// times of a backend
+type latencyEWMA struct {
+	mu    sync.Mutex
+	value time.Duration
+}
+
+func (l *latencyEWMA) Observe(d time.Duration) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.value == 0 {
+		l.value = d
+	} else {
+		l.value = time.Duration(latencyEWMAAlpha*float64(d) + (1-latencyEWMAAlpha)*float64(l.value))
+	}
+	return l.value
+}
+
+func (l *latencyEWMA) Value() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.value
+}
+
+// orderBackends returns the order the group tries the backends in for a
+// request according to its routing strategy
+func (b *BackendGroup) orderBackends(backends []*Backend) []*Backend {
+	if len(backends) < 2 {
+		return backends
+	}
+
+	switch b.Strategy {
+	case RoutingStrategyRoundRobin:
+		start := int((atomic.AddUint64(&b.next, 1) - 1) % uint64(len(backends)))
+		ordered := make([]*Backend, 0, len(backends))
+		ordered = append(ordered, backends[start:]...)
+		return append(ordered, backends[:start]...)
+	case RoutingStrategyWeighted:
+		return weightedOrder(backends)
+	case RoutingStrategyLeastLatency:
+		ordered := make([]*Backend, len(backends))
+		copy(ordered, backends)
+		// Backends without a measurement yet go first so they get one
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].latency.Value() < ordered[j].latency.Value()
+		})
+		return ordered
+	default:
+		return backends
+	}
+}
+
+// weightedOrder draws the backends one after the other without replacement,
+// each with a chance proportional to its weight
+func weightedOrder(backends []*Backend) []*Backend {
+	remaining := make([]*Backend, len(backends))
+	copy(remaining, backends)
+	total := 0
+	for _, back := range remaining {
+		total += back.weight
+	}
+
+	ordered := make([]*Backend, 0, len(backends))
+	for len(remaining) > 0 {
+		idx := 0
+		if total > 0 {
+			n := rand.Intn(total)
+			for i, back := range remaining {
+				if n < back.weight {
+					idx = i
+					break
+				}
+				n -= back.weight
+			}
+		}
+		total -= remaining[idx].weight
+		ordered = append(ordered, remaining[idx])
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+	}
+	return ordered
+}
@@ -0,0 +1,93 @@
+package proxyd
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestOrderBackends(t *testing.T) {
+	a := NewBackend("a", "", "", nil, nil, WithWeight(3))
+	b := NewBackend("b", "", "", nil, nil, WithWeight(1))
+	c := NewBackend("c", "", "", nil, nil, WithWeight(0))
+	backends := []*Backend{a, b, c}
+
+	names := func(backends []*Backend) []string {
+		out := make([]string, len(backends))
+		for i, back := range backends {
+			out[i] = back.Name
+		}
+		return out
+	}
+
+	t.Run("priority", func(t *testing.T) {
+		group := &BackendGroup{Backends: backends, Strategy: RoutingStrategyPriority}
+		require.Equal(t, []string{"a", "b", "c"}, names(group.orderBackends(backends)))
+		require.Equal(t, []string{"a", "b", "c"}, names(group.orderBackends(backends)))
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		group := &BackendGroup{Backends: backends, Strategy: RoutingStrategyRoundRobin}
+		require.Equal(t, []string{"a", "b", "c"}, names(group.orderBackends(backends)))
+		require.Equal(t, []string{"b", "c", "a"}, names(group.orderBackends(backends)))
+		require.Equal(t, []string{"c", "a", "b"}, names(group.orderBackends(backends)))
+		require.Equal(t, []string{"a", "b", "c"}, names(group.orderBackends(backends)))
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		group := &BackendGroup{Backends: backends, Strategy: RoutingStrategyWeighted}
+		first := make(map[string]int)
+		for i := 0; i < 4000; i++ {
+			ordered := group.orderBackends(backends)
+			require.Len(t, ordered, 3)
+			// Backends without weight are only tried last
+			require.Equal(t, "c", ordered[2].Name)
+			first[ordered[0].Name]++
+		}
+		require.InDelta(t, 3000, first["a"], 200)
+		require.InDelta(t, 1000, first["b"], 200)
+	})
+
+	t.Run("least latency", func(t *testing.T) {
+		group := &BackendGroup{Backends: backends, Strategy: RoutingStrategyLeastLatency}
+		a.latency.Observe(30 * time.Millisecond)
+		b.latency.Observe(10 * time.Millisecond)
+		// c has no measurement yet and goes first
+		require.Equal(t, []string{"c", "b", "a"}, names(group.orderBackends(backends)))
+
+		c.latency.Observe(20 * time.Millisecond)
+		b.latency.Observe(100 * time.Millisecond)
+		require.Equal(t, 28*time.Millisecond, b.latency.Value())
+		require.Equal(t, []string{"c", "b", "a"}, names(group.orderBackends(backends)))
+		b.latency.Observe(100 * time.Millisecond)
+		require.Equal(t, []string{"c", "a", "b"}, names(group.orderBackends(backends)))
+	})
+}
+
+func TestLatencyOfFailedRequests(t *testing.T) {
+	server := httptest.NewServer(nil)
+	server.Close()
+	back := NewBackend("a", server.URL, "", NewLocalBackendRateLimiter(), semaphore.NewWeighted(1), WithTimeout(time.Second), WithMaxRetries(0))
+
+	req := &RPCReq{JSONRPC: JSONRPCVersion, Method: "eth_chainId", ID: []byte("1")}
+	_, err := back.Forward(context.Background(), []*RPCReq{req}, false)
+	require.Error(t, err)
+	require.Equal(t, time.Second, back.latency.Value())
+}
+
+func TestParseRoutingStrategy(t *testing.T) {
+	strategy, err := ParseRoutingStrategy("")
+	require.NoError(t, err)
+	require.Equal(t, RoutingStrategyPriority, strategy)
+
+	strategy, err = ParseRoutingStrategy("least_latency")
+	require.NoError(t, err)
+	require.Equal(t, RoutingStrategyLeastLatency, strategy)
+
+	_, err = ParseRoutingStrategy("random")
+	require.Error(t, err)
+}
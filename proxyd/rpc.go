@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 )
 
 type RPCReq struct {
@@ -59,6 +60,8 @@ type RPCErr struct {
 	Code          int    `json:"code"`
 	Message       string `json:"message"`
 	HTTPErrorCode int    `json:"-"`
+	// RetryAfter is sent as the Retry-After header of rate limited requests
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (r *RPCErr) Error() string {
@@ -70,6 +73,7 @@ func (r *RPCErr) Clone() *RPCErr {
 		Code:          r.Code,
 		Message:       r.Message,
 		HTTPErrorCode: r.HTTPErrorCode,
+		RetryAfter:    r.RetryAfter,
 	}
 }
 
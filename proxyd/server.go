@@ -14,10 +14,9 @@ import (
 	"sync"
 	"time"
 
-	"github.com/sethvargo/go-limiter"
-	"github.com/sethvargo/go-limiter/memorystore"
-	"github.com/sethvargo/go-limiter/noopstore"
-
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
@@ -29,6 +28,7 @@ const (
 	ContextKeyAuth              = "authorization"
 	ContextKeyReqID             = "req_id"
 	ContextKeyXForwardedFor     = "x_forwarded_for"
+	ContextKeyRateLimitExempt   = "rate_limit_exempt"
 	MaxBatchRPCCalls            = 100
 	cacheStatusHdr              = "X-Proxyd-Cache-Status"
 	defaultServerTimeout        = time.Second * 10
@@ -42,6 +42,7 @@ type Server struct {
 	backendGroups        map[string]*BackendGroup
 	wsBackendGroup       *BackendGroup
 	wsMethodWhitelist    *StringSet
+	wsMux                *WSMultiplexer
 	rpcMethodMappings    map[string]string
 	maxBodySize          int64
 	enableRequestLog     bool
@@ -50,10 +51,14 @@ type Server struct {
 	timeout              time.Duration
 	maxUpstreamBatchSize int
 	upgrader             *websocket.Upgrader
-	lim                  limiter.Store
+	mainLim              ServerRateLimiter
+	tierLims             map[string]ServerRateLimiter
+	methodLims           map[string]*methodRateLimit
+	senderLim            ServerRateLimiter
 	limConfig            RateLimitConfig
 	limExemptOrigins     map[string]bool
 	limExemptUserAgents  map[string]bool
+	blockRange           *BlockRangeLimiter
 	rpcServer            *http.Server
 	wsServer             *http.Server
 	cache                RPCCache
@@ -64,6 +69,7 @@ func NewServer(
 	backendGroups map[string]*BackendGroup,
 	wsBackendGroup *BackendGroup,
 	wsMethodWhitelist *StringSet,
+	wsMultiplexing bool,
 	rpcMethodMappings map[string]string,
 	maxBodySize int64,
 	authenticatedPaths map[string]string,
@@ -71,6 +77,8 @@ func NewServer(
 	maxUpstreamBatchSize int,
 	cache RPCCache,
 	rateLimitConfig RateLimitConfig,
+	limiterFactory ServerRateLimiterFactory,
+	blockRange *BlockRangeLimiter,
 	enableRequestLog bool,
 	maxRequestBodyLogLen int,
 ) (*Server, error) {
@@ -78,6 +86,10 @@ func NewServer(
 		cache = &NoopRPCCache{}
 	}
 
+	if blockRange == nil {
+		blockRange = NewBlockRangeLimiter(BlockRangeConfig{}, nil)
+	}
+
 	if maxBodySize == 0 {
 		maxBodySize = math.MaxInt64
 	}
@@ -90,30 +102,55 @@ func NewServer(
 		maxUpstreamBatchSize = defaultMaxUpstreamBatchSize
 	}
 
-	var lim limiter.Store
+	if limiterFactory == nil {
+		limiterFactory = NewMemoryServerRateLimiterFactory()
+	}
+
+	var mainLim ServerRateLimiter
+	if rateLimitConfig.RatePerSecond > 0 {
+		mainLim = limiterFactory("main", rateLimitConfig.RatePerSecond, time.Second)
+	}
+
 	limExemptOrigins := make(map[string]bool)
 	limExemptUserAgents := make(map[string]bool)
-	if rateLimitConfig.RatePerSecond > 0 {
-		var err error
-		lim, err = memorystore.New(&memorystore.Config{
-			Tokens:   uint64(rateLimitConfig.RatePerSecond),
-			Interval: time.Second,
-		})
-		if err != nil {
-			return nil, err
+	for _, origin := range rateLimitConfig.ExemptOrigins {
+		limExemptOrigins[strings.ToLower(origin)] = true
+	}
+	for _, agent := range rateLimitConfig.ExemptUserAgents {
+		limExemptUserAgents[strings.ToLower(agent)] = true
+	}
+
+	tierLims := make(map[string]ServerRateLimiter)
+	for name, tier := range rateLimitConfig.Tiers {
+		if tier.RatePerSecond > 0 {
+			tierLims[name] = limiterFactory("tier:"+name, tier.RatePerSecond, time.Second)
+		} else {
+			tierLims[name] = nil
+		}
+	}
+	for alias, tier := range rateLimitConfig.AuthTiers {
+		if _, ok := tierLims[tier]; !ok {
+			return nil, fmt.Errorf("rate limit tier %s of auth key %s is not defined", tier, alias)
 		}
+	}
 
-		for _, origin := range rateLimitConfig.ExemptOrigins {
-			limExemptOrigins[strings.ToLower(origin)] = true
+	methodLims := make(map[string]*methodRateLimit)
+	for method, rule := range rateLimitConfig.MethodOverrides {
+		if rule.Limit <= 0 {
+			return nil, fmt.Errorf("rate limit of method %s must be positive", method)
 		}
-		for _, agent := range rateLimitConfig.ExemptUserAgents {
-			limExemptUserAgents[strings.ToLower(agent)] = true
+		methodLims[method] = &methodRateLimit{
+			global: rule.Global,
+			lim:    limiterFactory("method:"+method, rule.Limit, rateLimitInterval(rule.IntervalSeconds)),
 		}
-	} else {
-		lim, _ = noopstore.New()
 	}
 
-	return &Server{
+	var senderLim ServerRateLimiter
+	if rateLimitConfig.SenderLimit.Limit > 0 {
+		senderLim = limiterFactory("sender", rateLimitConfig.SenderLimit.Limit, rateLimitInterval(rateLimitConfig.SenderLimit.IntervalSeconds))
+	}
+
+	srv := &Server{
 		backendGroups:        backendGroups,
 		wsBackendGroup:       wsBackendGroup,
 		wsMethodWhitelist:    wsMethodWhitelist,
@@ -128,11 +165,19 @@ func NewServer(
 		upgrader: &websocket.Upgrader{
 			HandshakeTimeout: 5 * time.Second,
 		},
-		lim:                 lim,
+		mainLim:             mainLim,
+		tierLims:            tierLims,
+		methodLims:          methodLims,
+		senderLim:           senderLim,
 		limConfig:           rateLimitConfig,
 		limExemptOrigins:    limExemptOrigins,
 		limExemptUserAgents: limExemptUserAgents,
-	}, nil
+		blockRange:          blockRange,
+	}
+	if wsMultiplexing && wsBackendGroup != nil {
+		srv.wsMux = NewWSMultiplexer(wsBackendGroup, wsMethodWhitelist, srv.handleWSMuxRPC)
+	}
+	return srv, nil
 }
 
 func (s *Server) RPCListenAndServe(host string, port int) error {
@@ -181,6 +226,9 @@ func (s *Server) Shutdown() {
 	if s.wsServer != nil {
 		_ = s.wsServer.Shutdown(context.Background())
 	}
+	if s.wsMux != nil {
+		s.wsMux.Stop()
+	}
 }
 
 func (s *Server) HandleHealthz(w http.ResponseWriter, r *http.Request) {
@@ -198,23 +246,21 @@ func (s *Server) HandleRPC(w http.ResponseWriter, r *http.Request) {
 
 	exemptOrigin := s.limExemptOrigins[strings.ToLower(r.Header.Get("Origin"))]
 	exemptUserAgent := s.limExemptUserAgents[strings.ToLower(r.Header.Get("User-Agent"))]
-	var ok bool
+	var limErr *RPCErr
 	if exemptOrigin || exemptUserAgent {
-		ok = true
+		ctx = context.WithValue(ctx, ContextKeyRateLimitExempt, true) // nolint:staticcheck
 	} else {
 		// Use XFF in context since it will automatically be replaced by the remote IP
 		xff := stripXFF(GetXForwardedFor(ctx))
 		if xff == "" {
 			log.Warn("rejecting request without XFF or remote IP")
-			ok = false
+			limErr = s.overRateLimitErr(ErrOverRateLimit, 0)
 		} else {
-			_, _, _, ok, _ = s.lim.Take(ctx, xff)
+			limErr = s.takeClientLimit(ctx)
 		}
 	}
-	if !ok {
-		rpcErr := ErrOverRateLimit.Clone()
-		rpcErr.Message = s.limConfig.ErrorMessage
-		writeRPCError(ctx, w, nil, rpcErr)
+	if limErr != nil {
+		writeRPCError(ctx, w, nil, limErr)
 		return
 	}
 
@@ -286,6 +332,23 @@ func (s *Server) HandleRPC(w http.ResponseWriter, r *http.Request) {
 	writeRPCRes(ctx, w, backendRes[0])
 }
 
+// handleWSMuxRPC handles a call of a multiplexed WS client other than a
+// subscription like an HTTP request, so the method mappings, the rate and
+// block range limits and the cache apply to it as well
+func (s *Server) handleWSMuxRPC(ctx context.Context, msg json.RawMessage) (*RPCRes, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res, _, err := s.handleBatchRPC(ctx, []json.RawMessage{msg}, false)
+	if err == context.DeadlineExceeded {
+		return nil, ErrGatewayTimeout
+	}
+	if err != nil {
+		return nil, ErrInternal
+	}
+	return res[0], nil
+}
+
 func (s *Server) handleBatchRPC(ctx context.Context, reqs []json.RawMessage, isBatch bool) ([]*RPCRes, bool, error) {
 	// A request set is transformed into groups of batches.
 	// Each batch group maps to a forwarded JSON-RPC batch request (subject to maxUpstreamBatchSize constraints)
@@ -298,8 +361,16 @@ func (s *Server) handleBatchRPC(ctx context.Context, reqs []json.RawMessage, isB
 		backendGroup string
 	}
 
+	// Requests spanning too many blocks are forwarded as chunks on their own
+	type splitElem struct {
+		batchElem
+		backendGroup string
+		chunks       []*RPCReq
+	}
+
 	responses := make([]*RPCRes, len(reqs))
 	batches := make(map[batchGroup][]batchElem)
+	var splits []splitElem
 	ids := make(map[string]int, len(reqs))
 
 	for i := range reqs {
@@ -337,6 +408,35 @@ func (s *Server) handleBatchRPC(ctx context.Context, reqs []json.RawMessage, isB
 			continue
 		}
 
+		if err := s.takeRequestLimits(ctx, parsedReq); err != nil {
+			log.Info(
+				"rate limited request",
+				"source", "rpc",
+				"req_id", GetReqID(ctx),
+				"method", parsedReq.Method,
+			)
+			RecordRPCError(ctx, BackendProxyd, parsedReq.Method, err)
+			responses[i] = NewRPCErrorRes(parsedReq.ID, err)
+			continue
+		}
+
+		chunks, err := s.blockRange.Split(ctx, parsedReq)
+		if err != nil {
+			log.Info(
+				"rejected request for block range",
+				"source", "rpc",
+				"req_id", GetReqID(ctx),
+				"method", parsedReq.Method,
+			)
+			RecordRPCError(ctx, BackendProxyd, parsedReq.Method, err)
+			responses[i] = NewRPCErrorRes(parsedReq.ID, err)
+			continue
+		}
+		if chunks != nil {
+			splits = append(splits, splitElem{batchElem{parsedReq, i}, group, chunks})
+			continue
+		}
+
 		id := string(parsedReq.ID)
 		// If this is a duplicate Request ID, move the Request to a new batchGroup
 		ids[id]++
@@ -390,6 +490,11 @@ func (s *Server) handleBatchRPC(ctx context.Context, reqs []json.RawMessage, isB
 			}
 
 			for i := range elems {
+				if s.blockRange.ExceedsMaxResults(elems[i].Req, res[i]) {
+					RecordRPCError(ctx, BackendProxyd, elems[i].Req.Method, ErrTooManyResults)
+					responses[elems[i].Index] = NewRPCErrorRes(elems[i].Req.ID, ErrTooManyResults)
+					continue
+				}
 				responses[elems[i].Index] = res[i]
 
 				// TODO(inphi): batch put these
@@ -406,6 +511,10 @@ func (s *Server) handleBatchRPC(ctx context.Context, reqs []json.RawMessage, isB
 		}
 	}
 
+	for _, split := range splits {
+		responses[split.Index] = s.blockRange.ForwardChunks(ctx, s.backendGroups[split.backendGroup], split.Req, split.chunks)
+	}
+
 	return responses, cached, nil
 }
 
@@ -423,6 +532,11 @@ func (s *Server) HandleWS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.wsMux != nil {
+		s.serveMuxWS(ctx, clientConn)
+		return
+	}
+
 	proxier, err := s.wsBackendGroup.ProxyWS(ctx, clientConn, s.wsMethodWhitelist)
 	if err != nil {
 		if errors.Is(err, ErrNoBackends) {
@@ -445,6 +559,27 @@ func (s *Server) HandleWS(w http.ResponseWriter, r *http.Request) {
 	log.Info("accepted WS connection", "auth", GetAuthCtx(ctx), "req_id", GetReqID(ctx))
 }
 
+// serveMuxWS serves the client from the shared upstream subscriptions instead
+// of a dedicated backend connection
+func (s *Server) serveMuxWS(ctx context.Context, clientConn *websocket.Conn) {
+	client, err := s.wsMux.NewClient(ctx, clientConn)
+	if err != nil {
+		log.Error("error accepting ws client", "auth", GetAuthCtx(ctx), "req_id", GetReqID(ctx), "err", err)
+		clientConn.Close()
+		return
+	}
+
+	activeClientWsConnsGauge.WithLabelValues(GetAuthCtx(ctx)).Inc()
+	go func() {
+		if err := client.Serve(); err != nil {
+			log.Error("error serving websocket", "auth", GetAuthCtx(ctx), "req_id", GetReqID(ctx), "err", err)
+		}
+		activeClientWsConnsGauge.WithLabelValues(GetAuthCtx(ctx)).Dec()
+	}()
+
+	log.Info("accepted WS connection", "auth", GetAuthCtx(ctx), "req_id", GetReqID(ctx), "multiplexed", true)
+}
+
 func (s *Server) populateContext(w http.ResponseWriter, r *http.Request) context.Context {
 	vars := mux.Vars(r)
 	authorization := vars["authorization"]
@@ -474,7 +609,7 @@ func (s *Server) populateContext(w http.ResponseWriter, r *http.Request) context
 			return nil
 		}
 
-		ctx = context.WithValue(r.Context(), ContextKeyAuth, s.authenticatedPaths[authorization]) // nolint:staticcheck
+		ctx = context.WithValue(ctx, ContextKeyAuth, s.authenticatedPaths[authorization]) // nolint:staticcheck
 	}
 
 	return context.WithValue(
@@ -484,6 +619,20 @@ func (s *Server) populateContext(w http.ResponseWriter, r *http.Request) context
 	)
 }
 
+// setRetryAfterHeader tells the client when the last of the rate limits it
+// ran into resets
+func setRetryAfterHeader(w http.ResponseWriter, res []*RPCRes) {
+	var retryAfter time.Duration
+	for _, r := range res {
+		if r.IsError() && r.Error.RetryAfter > retryAfter {
+			retryAfter = r.Error.RetryAfter
+		}
+	}
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+}
+
 func setCacheHeader(w http.ResponseWriter, cached bool) {
 	if cached {
 		w.Header().Set(cacheStatusHdr, "HIT")
@@ -507,6 +656,7 @@ func writeRPCRes(ctx context.Context, w http.ResponseWriter, res *RPCRes) {
 	if res.IsError() && res.Error.HTTPErrorCode != 0 {
 		statusCode = res.Error.HTTPErrorCode
 	}
+	setRetryAfterHeader(w, []*RPCRes{res})
 
 	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(statusCode)
@@ -522,6 +672,7 @@ func writeRPCRes(ctx context.Context, w http.ResponseWriter, res *RPCRes) {
 }
 
 func writeBatchRPCRes(ctx context.Context, w http.ResponseWriter, res []*RPCRes) {
+	setRetryAfterHeader(w, res)
 	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(200)
 	ww := &recordLenWriter{Writer: w}
@@ -612,3 +763,135 @@ func createBatchRequest(elems []batchElem) []*RPCReq {
 	}
 	return batch
 }
+
+type methodRateLimit struct {
+	global bool
+	lim    ServerRateLimiter
+}
+
+func rateLimitInterval(seconds int) time.Duration {
+	if seconds <= 0 {
+		return time.Second
+	}
+	return secondsToDuration(seconds)
+}
+
+func isRateLimitExempt(ctx context.Context) bool {
+	exempt, _ := ctx.Value(ContextKeyRateLimitExempt).(bool)
+	return exempt
+}
+
+// rateLimitKey identifies the client of the request. The auth keys of a tier
+// are limited per key, everyone else per IP.
+func (s *Server) rateLimitKey(ctx context.Context) string {
+	if auth := GetAuthCtx(ctx); auth != "none" {
+		if _, ok := s.limConfig.AuthTiers[auth]; ok {
+			return "auth:" + auth
+		}
+	}
+	return stripXFF(GetXForwardedFor(ctx))
+}
+
+func (s *Server) overRateLimitErr(base *RPCErr, retryAfter time.Duration) *RPCErr {
+	rpcErr := base.Clone()
+	if s.limConfig.ErrorMessage != "" {
+		rpcErr.Message = s.limConfig.ErrorMessage
+	}
+	rpcErr.RetryAfter = retryAfter
+	return rpcErr
+}
+
+// take consumes a request from the limiter, a nil limiter means no limit.
+// Requests are let through when the limiter store fails.
+func (s *Server) take(ctx context.Context, lim ServerRateLimiter, key string) (bool, time.Duration) {
+	if lim == nil {
+		return true, 0
+	}
+	ok, retryAfter, err := lim.Take(ctx, key)
+	if err != nil {
+		log.Error("error taking from rate limit", "key", key, "req_id", GetReqID(ctx), "err", err)
+		return true, 0
+	}
+	return ok, retryAfter
+}
+
+// takeClientLimit applies the limit of the client, the auth keys of a tier
+// have the limit of the tier and everyone else the limit per IP
+func (s *Server) takeClientLimit(ctx context.Context) *RPCErr {
+	if tier, ok := s.limConfig.AuthTiers[GetAuthCtx(ctx)]; ok {
+		if ok, retryAfter := s.take(ctx, s.tierLims[tier], s.rateLimitKey(ctx)); !ok {
+			return s.overRateLimitErr(ErrOverRequestLimit, retryAfter)
+		}
+		return nil
+	}
+	if ok, retryAfter := s.take(ctx, s.mainLim, s.rateLimitKey(ctx)); !ok {
+		return s.overRateLimitErr(ErrOverRateLimit, retryAfter)
+	}
+	return nil
+}
+
+// takeRequestLimits applies the limits of the method of the request and the
+// limit of the transaction sender
+func (s *Server) takeRequestLimits(ctx context.Context, req *RPCReq) error {
+	if isRateLimitExempt(ctx) {
+		return nil
+	}
+
+	if ml := s.methodRateLimit(req.Method); ml != nil {
+		key := s.rateLimitKey(ctx)
+		if ml.global {
+			key = "global"
+		}
+		if ok, retryAfter := s.take(ctx, ml.lim, key); !ok {
+			return s.overRateLimitErr(ErrOverRequestLimit, retryAfter)
+		}
+	}
+
+	if s.senderLim != nil && req.Method == "eth_sendRawTransaction" {
+		// Undecodable transactions are left for the backend to reject
+		sender, err := rawTransactionSender(req.Params)
+		if err != nil {
+			return nil
+		}
+		if ok, retryAfter := s.take(ctx, s.senderLim, sender.Hex()); !ok {
+			return s.overRateLimitErr(ErrOverRequestLimit, retryAfter)
+		}
+	}
+	return nil
+}
+
+// methodRateLimit returns the limit of the method, an exact match goes before
+// the longest matching wildcard
+func (s *Server) methodRateLimit(method string) *methodRateLimit {
+	if ml := s.methodLims[method]; ml != nil {
+		return ml
+	}
+	var match *methodRateLimit
+	var matchLen int
+	for pattern, ml := range s.methodLims {
+		if !strings.HasSuffix(pattern, "*") {
+			continue
+		}
+		prefix := strings.TrimSuffix(pattern, "*")
+		if strings.HasPrefix(method, prefix) && (match == nil || len(prefix) > matchLen) {
+			match = ml
+			matchLen = len(prefix)
+		}
+	}
+	return match
+}
+
+func rawTransactionSender(params json.RawMessage) (common.Address, error) {
+	var input []hexutil.Bytes
+	if err := json.Unmarshal(params, &input); err != nil {
+		return common.Address{}, err
+	}
+	if len(input) != 1 {
+		return common.Address{}, errInvalidRPCParams
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input[0]); err != nil {
+		return common.Address{}, err
+	}
+	return types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+}
@@ -0,0 +1,716 @@
+package proxyd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsMuxClientQueueSize  = 256
+	wsMuxSubscribeTimeout = 10 * time.Second
+)
+
+// WSMultiplexer serves the websocket clients of a backend group over a single
+// upstream connection. Identical eth_subscribe calls share one upstream
+// subscription whose notifications are fanned out to every client, the other
+// calls are handled like HTTP requests. When the upstream connection breaks
+// the subscriptions are made again on the next available backend and the
+// clients keep their subscription IDs. Notifications the old backend did not
+// deliver before it went away are not replayed, so clients may miss some
+// during a failover.
+type WSMultiplexer struct {
+	group           *BackendGroup
+	methodWhitelist *StringSet
+	forward         func(ctx context.Context, msg json.RawMessage) (*RPCRes, error)
+
+	mu       sync.Mutex
+	upstream *wsUpstream
+	// connecting is set while a backend is dialed, the subscriptions made in
+	// the meantime are made once the connection is up
+	connecting bool
+	// subs are keyed by their params, byUpstreamID by the subscription ID of
+	// the current upstream connection
+	subs         map[string]*muxSubscription
+	byUpstreamID map[string]*muxSubscription
+	clients      map[*WSMuxClient]struct{}
+	numClientSub int
+	quit         chan struct{}
+	closed       bool
+}
+
+type muxSubscription struct {
+	key        string
+	params     json.RawMessage
+	upstreamID string
+	clients    map[string]*WSMuxClient
+	// waiting is the number of clients waiting for the first upstream
+	// response, the subscription is kept until they joined
+	waiting   int
+	ready     chan struct{}
+	readyOnce sync.Once
+	err       error
+}
+
+func (s *muxSubscription) markReady(err error) {
+	s.readyOnce.Do(func() {
+		s.err = err
+		close(s.ready)
+	})
+}
+
+type wsUpstream struct {
+	backend *Backend
+	conn    *websocket.Conn
+	out     chan []byte
+	done    chan struct{}
+	// nextID and pending are guarded by the multiplexer lock, pending holds
+	// the handlers of the requests awaiting a response, they run with the
+	// multiplexer lock held
+	nextID    uint64
+	pending   map[string]func(res *RPCRes)
+	closeOnce sync.Once
+}
+
+func newWSUpstream(backend *Backend, conn *websocket.Conn) *wsUpstream {
+	return &wsUpstream{
+		backend: backend,
+		conn:    conn,
+		out:     make(chan []byte, wsMuxClientQueueSize),
+		done:    make(chan struct{}),
+		pending: make(map[string]func(res *RPCRes)),
+	}
+}
+
+// call queues a request for the upstream without blocking, so it can be made
+// with the multiplexer lock held. A connection too slow to keep up is closed,
+// which fails over to another backend.
+func (u *wsUpstream) call(method string, params interface{}, handler func(res *RPCRes)) error {
+	u.nextID++
+	id := strconv.FormatUint(u.nextID, 10)
+	req := &RPCReq{
+		JSONRPC: JSONRPCVersion,
+		Method:  method,
+		Params:  mustMarshalJSON(params),
+		ID:      json.RawMessage(id),
+	}
+	select {
+	case u.out <- mustMarshalJSON(req):
+	default:
+		u.conn.Close()
+		return ErrBackendOverCapacity
+	}
+	if handler != nil {
+		u.pending[id] = handler
+	}
+	return nil
+}
+
+func (u *wsUpstream) writeLoop() {
+	for {
+		select {
+		case msg := <-u.out:
+			if err := u.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				// The read loop fails over to another backend
+				u.conn.Close()
+				return
+			}
+		case <-u.done:
+			return
+		}
+	}
+}
+
+func (u *wsUpstream) close() {
+	u.closeOnce.Do(func() {
+		close(u.done)
+		u.conn.Close()
+		u.backend.releaseWS()
+	})
+}
+
+func NewWSMultiplexer(
+	group *BackendGroup,
+	methodWhitelist *StringSet,
+	forward func(ctx context.Context, msg json.RawMessage) (*RPCRes, error),
+) *WSMultiplexer {
+	return &WSMultiplexer{
+		group:           group,
+		methodWhitelist: methodWhitelist,
+		forward:         forward,
+		subs:            make(map[string]*muxSubscription),
+		byUpstreamID:    make(map[string]*muxSubscription),
+		clients:         make(map[*WSMuxClient]struct{}),
+		quit:            make(chan struct{}),
+	}
+}
+
+// Stop closes the upstream connection and disconnects every client
+func (m *WSMultiplexer) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quit)
+	if m.upstream != nil {
+		m.upstream.close()
+		m.upstream = nil
+	}
+	for c := range m.clients {
+		c.close()
+	}
+}
+
+// dial connects to the first available backend of the group, the backend
+// that failed last is tried last. It runs without the lock, so a slow backend
+// holds up no client.
+func (m *WSMultiplexer) dial(failed *Backend) (*wsUpstream, error) {
+	backends := m.group.Backends
+	if m.group.Consensus != nil {
+		backends, _, _ = m.group.Consensus.consensusBackends()
+	}
+	ordered := m.group.orderBackends(backends)
+	if failed != nil {
+		reordered := make([]*Backend, 0, len(ordered))
+		for _, back := range ordered {
+			if back != failed {
+				reordered = append(reordered, back)
+			}
+		}
+		if len(reordered) < len(ordered) {
+			reordered = append(reordered, failed)
+		}
+		ordered = reordered
+	}
+
+	for _, back := range ordered {
+		conn, err := back.dialWS()
+		if err != nil {
+			log.Warn(
+				"error dialing ws upstream",
+				"group", m.group.Name,
+				"name", back.Name,
+				"err", err,
+			)
+			continue
+		}
+		return newWSUpstream(back, conn), nil
+	}
+	return nil, ErrNoBackends
+}
+
+// install makes the connection the upstream and subscribes all the
+// subscriptions on it. The lock must be held.
+func (m *WSMultiplexer) install(u *wsUpstream) {
+	m.upstream = u
+	go m.readLoop(u)
+	go u.writeLoop()
+	for _, sub := range m.subs {
+		m.subscribeUpstream(u, sub)
+	}
+	RecordGroupRoutedRequest(m.group.Name, u.backend.Name, RPCRequestSourceWS)
+	log.Info("connected ws upstream", "group", m.group.Name, "name", u.backend.Name, "subscriptions", len(m.subs))
+}
+
+// subscribeUpstream makes the subscription on the upstream connection. The
+// lock must be held.
+func (m *WSMultiplexer) subscribeUpstream(u *wsUpstream, sub *muxSubscription) {
+	err := u.call("eth_subscribe", sub.params, func(res *RPCRes) {
+		var upstreamID string
+		if !res.IsError() {
+			upstreamID, _ = res.Result.(string)
+		}
+		if m.subs[sub.key] != sub {
+			// Every client left while the subscription was made
+			if upstreamID != "" {
+				_ = u.call("eth_unsubscribe", []string{upstreamID}, nil)
+			}
+			return
+		}
+		if upstreamID == "" {
+			err := ErrBackendBadResponse
+			if res.IsError() {
+				err = res.Error
+			}
+			m.dropSubscription(sub, err)
+			return
+		}
+		sub.upstreamID = upstreamID
+		m.byUpstreamID[upstreamID] = sub
+		sub.markReady(nil)
+	})
+	if err != nil {
+		// The read loop fails over to another backend
+		log.Warn("error writing ws upstream subscription", "group", m.group.Name, "name", u.backend.Name, "err", err)
+	}
+}
+
+// dropSubscription removes a subscription the upstream rejected. The lock
+// must be held.
+func (m *WSMultiplexer) dropSubscription(sub *muxSubscription, err error) {
+	if len(sub.clients) > 0 {
+		log.Error(
+			"dropping ws subscription rejected by upstream",
+			"group", m.group.Name,
+			"clients", len(sub.clients),
+			"err", err,
+		)
+	}
+	for id, c := range sub.clients {
+		delete(c.subs, id)
+		m.numClientSub--
+	}
+	sub.clients = make(map[string]*WSMuxClient)
+	delete(m.subs, sub.key)
+	if sub.upstreamID != "" {
+		delete(m.byUpstreamID, sub.upstreamID)
+	}
+	sub.markReady(err)
+	m.recordSubscriptions()
+}
+
+// releaseSubscription removes the subscription once no client uses it and
+// closes the upstream connection once no subscription is left. The lock must
+// be held.
+func (m *WSMultiplexer) releaseSubscription(sub *muxSubscription) {
+	if len(sub.clients) > 0 || sub.waiting > 0 || m.subs[sub.key] != sub {
+		return
+	}
+	delete(m.subs, sub.key)
+	if sub.upstreamID != "" {
+		delete(m.byUpstreamID, sub.upstreamID)
+	}
+	if m.upstream != nil {
+		if len(m.subs) == 0 {
+			m.upstream.close()
+			m.upstream = nil
+		} else if sub.upstreamID != "" {
+			_ = m.upstream.call("eth_unsubscribe", []string{sub.upstreamID}, nil)
+		}
+	}
+	m.recordSubscriptions()
+}
+
+func (m *WSMultiplexer) recordSubscriptions() {
+	RecordWSMuxSubscriptions(m.group.Name, len(m.subs), m.numClientSub)
+}
+
+type wsUpstreamMsg struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+type wsNotification struct {
+	JSONRPC string                   `json:"jsonrpc"`
+	Method  string                   `json:"method"`
+	Params  wsNotificationParamsJSON `json:"params"`
+}
+
+type wsNotificationParamsJSON struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+func (m *WSMultiplexer) readLoop(u *wsUpstream) {
+	for {
+		msgType, msg, err := u.conn.ReadMessage()
+		if err != nil {
+			m.failover(u, err)
+			return
+		}
+		RecordWSMessage(context.Background(), u.backend.Name, SourceBackend)
+		if msgType != websocket.TextMessage && msgType != websocket.BinaryMessage {
+			continue
+		}
+
+		var parsed wsUpstreamMsg
+		if err := json.Unmarshal(msg, &parsed); err != nil {
+			log.Warn("error parsing ws upstream message", "name", u.backend.Name, "err", err)
+			continue
+		}
+
+		m.mu.Lock()
+		if m.upstream != u {
+			m.mu.Unlock()
+			return
+		}
+		if parsed.Method == "eth_subscription" {
+			m.notify(parsed.Params.Subscription, parsed.Params.Result)
+		} else if handler := u.pending[string(parsed.ID)]; handler != nil {
+			delete(u.pending, string(parsed.ID))
+			res, err := ParseRPCRes(bytes.NewReader(msg))
+			if err != nil {
+				res = NewRPCErrorRes(parsed.ID, ErrBackendBadResponse)
+			}
+			handler(res)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// notify fans a notification out to the clients of the subscription, under
+// their own subscription ID. The lock must be held.
+func (m *WSMultiplexer) notify(upstreamID string, result json.RawMessage) {
+	sub := m.byUpstreamID[upstreamID]
+	if sub == nil {
+		return
+	}
+	for id, c := range sub.clients {
+		c.send(mustMarshalJSON(&wsNotification{
+			JSONRPC: JSONRPCVersion,
+			Method:  "eth_subscription",
+			Params: wsNotificationParamsJSON{
+				Subscription: id,
+				Result:       result,
+			},
+		}))
+	}
+}
+
+// failover moves the subscriptions of a broken upstream connection to the
+// next available backend, retrying until one is reachable
+func (m *WSMultiplexer) failover(u *wsUpstream, err error) {
+	m.mu.Lock()
+	if m.upstream != u {
+		m.mu.Unlock()
+		return
+	}
+	log.Warn("lost ws upstream connection", "group", m.group.Name, "name", u.backend.Name, "err", err)
+	u.close()
+	m.upstream = nil
+	m.byUpstreamID = make(map[string]*muxSubscription)
+	for _, sub := range m.subs {
+		sub.upstreamID = ""
+	}
+	m.connecting = true
+	m.mu.Unlock()
+
+	for i := 0; ; i++ {
+		m.mu.Lock()
+		if m.closed || len(m.subs) == 0 {
+			m.connecting = false
+			m.mu.Unlock()
+			return
+		}
+		m.mu.Unlock()
+
+		next, err := m.dial(u.backend)
+		if err == nil {
+			m.mu.Lock()
+			m.connecting = false
+			if m.closed || len(m.subs) == 0 {
+				next.close()
+			} else {
+				m.install(next)
+				RecordWSMuxResubscription(m.group.Name, next.backend.Name)
+			}
+			m.mu.Unlock()
+			return
+		}
+
+		log.Warn("error reconnecting ws upstream", "group", m.group.Name, "err", err)
+		select {
+		case <-time.After(calcBackoff(i)):
+		case <-m.quit:
+			m.mu.Lock()
+			m.connecting = false
+			m.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (m *WSMultiplexer) subscribe(c *WSMuxClient, req *RPCReq) error {
+	var params []interface{}
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params) == 0 {
+		return ErrInvalidParams("invalid subscription params")
+	}
+	// Re-encoding sorts the filter keys, so identical subscriptions share
+	// the key
+	key := string(mustMarshalJSON(params))
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrBackendOffline
+	}
+	sub := m.subs[key]
+	connect := false
+	if sub == nil {
+		sub = &muxSubscription{
+			key:     key,
+			params:  json.RawMessage(key),
+			clients: make(map[string]*WSMuxClient),
+			ready:   make(chan struct{}),
+		}
+		m.subs[key] = sub
+		if m.upstream != nil {
+			m.subscribeUpstream(m.upstream, sub)
+		} else if !m.connecting {
+			m.connecting = true
+			connect = true
+		}
+	}
+	sub.waiting++
+	m.mu.Unlock()
+
+	if connect {
+		u, err := m.dial(nil)
+		m.mu.Lock()
+		m.connecting = false
+		switch {
+		case err != nil:
+			// Nobody else is connecting, so every subscription waiting for
+			// the connection fails
+			for _, s := range m.subs {
+				m.dropSubscription(s, err)
+			}
+		case m.closed || len(m.subs) == 0:
+			u.close()
+		default:
+			m.install(u)
+		}
+		m.mu.Unlock()
+	}
+
+	var err error
+	select {
+	case <-sub.ready:
+		err = sub.err
+	case <-time.After(wsMuxSubscribeTimeout):
+		err = ErrGatewayTimeout
+	case <-c.done:
+		err = ErrGatewayTimeout
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub.waiting--
+	if err == nil && m.subs[key] != sub {
+		err = ErrBackendOffline
+	}
+	if err != nil {
+		m.releaseSubscription(sub)
+		return err
+	}
+
+	id := newSubscriptionID()
+	sub.clients[id] = c
+	c.subs[id] = sub
+	m.numClientSub++
+	m.recordSubscriptions()
+	// The response goes out before any notification since both are sent
+	// with the lock held
+	c.send(mustMarshalJSON(NewRPCRes(req.ID, id)))
+	return nil
+}
+
+func (m *WSMultiplexer) unsubscribe(c *WSMuxClient, req *RPCReq) error {
+	var params []string
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params) != 1 {
+		return ErrInvalidParams("invalid subscription id")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub := c.subs[params[0]]
+	if sub == nil {
+		return ErrSubscriptionNotFound
+	}
+	delete(c.subs, params[0])
+	delete(sub.clients, params[0])
+	m.numClientSub--
+	m.releaseSubscription(sub)
+	c.send(mustMarshalJSON(NewRPCRes(req.ID, true)))
+	return nil
+}
+
+func (m *WSMultiplexer) removeClient(c *WSMuxClient) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.clients, c)
+	for id, sub := range c.subs {
+		delete(sub.clients, id)
+		m.numClientSub--
+		m.releaseSubscription(sub)
+	}
+	c.subs = nil
+	m.recordSubscriptions()
+}
+
+// WSMuxClient is a websocket client served by a WSMultiplexer
+type WSMuxClient struct {
+	mux       *WSMultiplexer
+	ctx       context.Context
+	conn      *websocket.Conn
+	out       chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	// subs are keyed by the subscription ID of the client, guarded by the
+	// multiplexer lock
+	subs map[string]*muxSubscription
+}
+
+func (m *WSMultiplexer) NewClient(ctx context.Context, conn *websocket.Conn) (*WSMuxClient, error) {
+	c := &WSMuxClient{
+		mux:  m,
+		ctx:  ctx,
+		conn: conn,
+		out:  make(chan []byte, wsMuxClientQueueSize),
+		done: make(chan struct{}),
+		subs: make(map[string]*muxSubscription),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrBackendOffline
+	}
+	m.clients[c] = struct{}{}
+	return c, nil
+}
+
+// Serve handles the messages of the client until it disconnects
+func (c *WSMuxClient) Serve() error {
+	go c.writePump()
+	err := c.readPump()
+	c.close()
+	c.mux.removeClient(c)
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		return nil
+	}
+	return err
+}
+
+func (c *WSMuxClient) readPump() error {
+	for {
+		msgType, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		RecordWSMessage(c.ctx, BackendProxyd, SourceClient)
+
+		// Pings are answered by the connection itself
+		if msgType != websocket.TextMessage && msgType != websocket.BinaryMessage {
+			continue
+		}
+
+		rpcRequestsTotal.Inc()
+		c.handleMsg(msg)
+	}
+}
+
+func (c *WSMuxClient) handleMsg(msg []byte) {
+	req, err := ParseRPCReq(msg)
+	if err != nil {
+		c.sendError(nil, MethodUnknown, err)
+		return
+	}
+	if !c.mux.methodWhitelist.Has(req.Method) {
+		c.sendError(req.ID, req.Method, ErrMethodNotWhitelisted)
+		return
+	}
+
+	log.Info(
+		"received WS message",
+		"method", req.Method,
+		"auth", GetAuthCtx(c.ctx),
+		"req_id", GetReqID(c.ctx),
+	)
+
+	switch req.Method {
+	case "eth_subscribe":
+		RecordRPCForward(c.ctx, BackendProxyd, req.Method, RPCRequestSourceWS)
+		if err := c.mux.subscribe(c, req); err != nil {
+			c.sendError(req.ID, req.Method, err)
+		}
+	case "eth_unsubscribe":
+		RecordRPCForward(c.ctx, BackendProxyd, req.Method, RPCRequestSourceWS)
+		if err := c.mux.unsubscribe(c, req); err != nil {
+			c.sendError(req.ID, req.Method, err)
+		}
+	default:
+		res, err := c.mux.forward(detachedContext{c.ctx}, msg)
+		if err != nil {
+			c.sendError(req.ID, req.Method, err)
+			return
+		}
+		c.send(mustMarshalJSON(res))
+	}
+}
+
+func (c *WSMuxClient) sendError(id json.RawMessage, method string, err error) {
+	log.Info(
+		"error handling WS message",
+		"auth", GetAuthCtx(c.ctx),
+		"req_id", GetReqID(c.ctx),
+		"err", err,
+	)
+	RecordRPCError(c.ctx, BackendProxyd, method, err)
+	c.send(mustMarshalJSON(NewRPCErrorRes(id, err)))
+}
+
+// send queues a message for the client, clients too slow to keep up with
+// their notifications are disconnected rather than holding up the others
+func (c *WSMuxClient) send(msg []byte) {
+	select {
+	case <-c.done:
+	case c.out <- msg:
+	default:
+		log.Warn("disconnecting slow WS client", "auth", GetAuthCtx(c.ctx), "req_id", GetReqID(c.ctx))
+		c.close()
+	}
+}
+
+func (c *WSMuxClient) writePump() {
+	for {
+		select {
+		case msg := <-c.out:
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *WSMuxClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// detachedContext keeps the values of the context of the upgrade request,
+// which is canceled as soon as the connection is hijacked
+type detachedContext struct {
+	values context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (d detachedContext) Value(key interface{}) interface{} { return d.values.Value(key) }
+
+func newSubscriptionID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(err)
+	}
+	return hexutil.Encode(id[:])
+}
@@ -38,6 +38,7 @@ func main() {
 		manager.Command(),
 		tssnode.Command(),
 		tssnode.PeerIDCommand(),
+		tssnode.MigrateKeySharesCommand(),
 	)
 
 	rootCmd.PersistentFlags().StringP("config", "c", "config", "configuration file with extension")
@@ -23,7 +23,12 @@ type Configuration struct {
 	L1ReceiptConfirmTimeout        string        `json:"l1_receipt_confirm_timeout" mapstructure:"l1_receipt_confirm_timeout"`
 	L1ConfirmBlocks                int           `json:"l1_confirm_blocks" mapstructure:"l1_confirm_blocks"`
 	L1StartBlockNumber             string        `json:"l1_start_block_number" mapstructure:"l1_start_block_number"`
-	MissSignedNumber               int           `json:"miss_signed_number" mapstructure:"miss_signed_number"`
+	// a node is slashed when it signs less than MinSignedPerWindow of the
+	// last SignedBatchesWindow state batches, it is then not tracked for the
+	// next JailDuration batches
+	SignedBatchesWindow uint64  `json:"signed_batches_window" mapstructure:"signed_batches_window"`
+	MinSignedPerWindow  float64 `json:"min_signed_per_window" mapstructure:"min_signed_per_window"`
+	JailDuration        uint64  `json:"jail_duration" mapstructure:"jail_duration"`
 }
 
 type ManagerConfig struct {
@@ -34,9 +39,29 @@ type ManagerConfig struct {
 	PrivateKey string `json:"private_key" mapstructure:"private_key"`
 
 	KeygenTimeout     string `json:"keygen_timeout" mapstructure:"keygen_timeout"`
+	ReshareTimeout    string `json:"reshare_timeout" mapstructure:"reshare_timeout"`
 	CPKConfirmTimeout string `json:"cpk_confirm_timeout" mapstructure:"cpk_confirm_timeout"`
 	AskTimeout        string `json:"ask_timeout" mapstructure:"ask_timeout"`
 	SignTimeout       string `json:"sign_timeout" mapstructure:"sign_timeout"`
+	// DisableReshare makes every election run a fresh keygen instead of
+	// resharing the active cluster key to the new committee
+	DisableReshare bool `json:"disable_reshare" mapstructure:"disable_reshare"`
+
+	HA HAConfig `json:"ha" mapstructure:"ha"`
+}
+
+// HAConfig runs several managers in active/standby mode, only the manager
+// holding the lease signs, generates keys and submits slashing
+type HAConfig struct {
+	Enable bool `json:"enable" mapstructure:"enable"`
+	// NodeId identifies this manager in the lease, it must be unique
+	NodeId string `json:"node_id" mapstructure:"node_id"`
+	// AdvertiseUrl is the http url the other managers reach this one at
+	AdvertiseUrl string `json:"advertise_url" mapstructure:"advertise_url"`
+	// LockFile is the lease file shared by all the managers
+	LockFile     string `json:"lock_file" mapstructure:"lock_file"`
+	LeaseTimeout string `json:"lease_timeout" mapstructure:"lease_timeout"`
+	SyncInterval string `json:"sync_interval" mapstructure:"sync_interval"`
 }
 
 type NodeConfig struct {
@@ -49,17 +74,43 @@ type NodeConfig struct {
 	DisableHTTP2 bool   `json:"disable_http2" mapstructure:"disable_http2"`
 	PrivateKey   string `json:"private_key" mapstructure:"private_key"`
 
-	PreParamFile    string        `json:"pre_param_file" mapstructure:"pre_param_file"`
-	P2PPort         string        `json:"p2p_port" mapstructure:"p2p_port"`
-	BootstrapPeers  string        `json:"bootstrap_peers" mapstructure:"bootstrap_peers"`
-	ExternalIP      string        `json:"external_ip" mapstructure:"external_ip"`
-	KeyGenTimeout   time.Duration `json:"key_gen_timeout" mapstructure:"key_gen_timeout"`
-	KeySignTimeout  time.Duration `json:"key_sign_timeout" mapstructure:"key_sign_timeout"`
-	PreParamTimeout time.Duration `json:"pre_param_timeout" mapstructure:"pre_param_timeout"`
-	GasLimitScaler  int           `json:"gas_limit_scaler" mapstructure:"gas_limit_scaler"`
+	PreParamFile      string        `json:"pre_param_file" mapstructure:"pre_param_file"`
+	P2PPort           string        `json:"p2p_port" mapstructure:"p2p_port"`
+	BootstrapPeers    string        `json:"bootstrap_peers" mapstructure:"bootstrap_peers"`
+	ExternalIP        string        `json:"external_ip" mapstructure:"external_ip"`
+	KeyGenTimeout     time.Duration `json:"key_gen_timeout" mapstructure:"key_gen_timeout"`
+	KeySignTimeout    time.Duration `json:"key_sign_timeout" mapstructure:"key_sign_timeout"`
+	KeyReshareTimeout time.Duration `json:"key_reshare_timeout" mapstructure:"key_reshare_timeout"`
+	PreParamTimeout   time.Duration `json:"pre_param_timeout" mapstructure:"pre_param_timeout"`
+	GasLimitScaler    int           `json:"gas_limit_scaler" mapstructure:"gas_limit_scaler"`
+
+	Secrets  SecretsManagerConfig `json:"secrets" mapstructure:"secrets"`
+	Shamir   ShamirConfig         `json:"shamir" mapstructure:"shamir"`
+	KeyStore KeyStoreConfig       `json:"key_store" mapstructure:"key_store"`
+}
+
+// KeyStoreConfig selects where the node keeps its key shares, the backend is
+// one of file, vault_file, vault_kv, aws_secrets and aws_shamir. The secrets
+// and shamir enable flags are honored when no backend is set.
+type KeyStoreConfig struct {
+	Backend   string          `json:"backend" mapstructure:"backend"`
+	VaultFile VaultFileConfig `json:"vault_file" mapstructure:"vault_file"`
+	VaultKV   VaultKVConfig   `json:"vault_kv" mapstructure:"vault_kv"`
+}
+
+type VaultFileConfig struct {
+	// Dir defaults to the keyshares folder in the base dir
+	Dir        string `json:"dir" mapstructure:"dir"`
+	Passphrase string `json:"passphrase" mapstructure:"passphrase"`
+}
 
-	Secrets SecretsManagerConfig `json:"secrets" mapstructure:"secrets"`
-	Shamir  ShamirConfig         `json:"shamir" mapstructure:"shamir"`
+// VaultKVConfig points to a kv version 2 secrets engine of a Vault compatible server
+type VaultKVConfig struct {
+	Address string        `json:"address" mapstructure:"address"`
+	Token   string        `json:"token" mapstructure:"token"`
+	Mount   string        `json:"mount" mapstructure:"mount"`
+	Path    string        `json:"path" mapstructure:"path"`
+	Timeout time.Duration `json:"timeout" mapstructure:"timeout"`
 }
 
 type SecretsManagerConfig struct {
@@ -105,19 +156,34 @@ func DefaultConfiguration() Configuration {
 		L1ReceiptConfirmTimeout: "20m",
 		L1ConfirmBlocks:         10,
 		L1StartBlockNumber:      "1",
-		MissSignedNumber:        50,
+		SignedBatchesWindow:     100,
+		MinSignedPerWindow:      0.5,
+		JailDuration:            100,
 		Manager: ManagerConfig{
 			KeygenTimeout:     "120s",
+			ReshareTimeout:    "120s",
 			CPKConfirmTimeout: "2h",
 			AskTimeout:        "60s",
 			SignTimeout:       "60s",
+			HA: HAConfig{
+				LeaseTimeout: "15s",
+				SyncInterval: "5s",
+			},
 		},
 		Node: NodeConfig{
-			P2PPort:         "8000",
-			KeyGenTimeout:   10 * time.Second,
-			KeySignTimeout:  10 * time.Second,
-			PreParamTimeout: 5 * time.Minute,
-			GasLimitScaler:  2,
+			P2PPort:           "8000",
+			KeyGenTimeout:     10 * time.Second,
+			KeySignTimeout:    10 * time.Second,
+			KeyReshareTimeout: 10 * time.Second,
+			PreParamTimeout:   5 * time.Minute,
+			GasLimitScaler:    2,
+			KeyStore: KeyStoreConfig{
+				VaultKV: VaultKVConfig{
+					Mount:   "secret",
+					Path:    "tss",
+					Timeout: 10 * time.Second,
+				},
+			},
 		},
 	}
 }
@@ -132,13 +198,29 @@ func LoadConfig(file string) (Configuration, error) {
 	viper.SetEnvPrefix("tss")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
 	viper.AutomaticEnv()
+	if viper.IsSet("miss_signed_number") {
+		// the absolute miss count can not be turned into a window ratio
+		return Configuration{}, errors.New("miss_signed_number was removed, set signed_batches_window, min_signed_per_window and jail_duration instead")
+	}
 	cfg := DefaultConfiguration()
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return Configuration{}, fmt.Errorf("fail to unmarshal: %w", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		return Configuration{}, err
+	}
 	return cfg, nil
 }
 
+// Validate checks the values that can not be defaulted
+func (c Configuration) Validate() error {
+	// written negated so that NaN is rejected as well
+	if !(c.MinSignedPerWindow >= 0 && c.MinSignedPerWindow <= 1) {
+		return fmt.Errorf("min_signed_per_window must be between 0 and 1, got %v", c.MinSignedPerWindow)
+	}
+	return nil
+}
+
 func GetConfigFromCmd(cmd *cobra.Command) Configuration {
 	if v := cmd.Context().Value("config"); v != nil {
 		clientCtxPtr := v.(*Configuration)
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigSlashingParams(t *testing.T) {
+	dir := t.TempDir()
+	load := func(name, content string) (Configuration, error) {
+		file := filepath.Join(dir, name+".toml")
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+		return LoadConfig(file)
+	}
+
+	cfg, err := load("valid", "signed_batches_window = 20\nmin_signed_per_window = 0.75\n")
+	require.NoError(t, err)
+	require.EqualValues(t, 20, cfg.SignedBatchesWindow)
+	require.Equal(t, 0.75, cfg.MinSignedPerWindow)
+	require.EqualValues(t, 100, cfg.JailDuration)
+
+	_, err = load("above", "min_signed_per_window = 1.5\n")
+	require.ErrorContains(t, err, "min_signed_per_window")
+	_, err = load("negative", "min_signed_per_window = -0.1\n")
+	require.ErrorContains(t, err, "min_signed_per_window")
+
+	_, err = load("legacy", "miss_signed_number = 5\n")
+	require.ErrorContains(t, err, "miss_signed_number was removed")
+}
@@ -17,6 +17,9 @@ const (
 	SignSlash      Method = "signSlash"
 	SignRollBack   Method = "signRollBack"
 	AskRollBack    Method = "askRollBack"
+	AskTxBatch     Method = "askTxBatch"
+	SignTxBatch    Method = "signTxBatch"
+	Reshare        Method = "reshare"
 
 	SlashTypeLiveness byte = 0
 	SlashTypeCulprit  byte = 1
@@ -45,6 +48,23 @@ func (ssr SignStateRequest) String() string {
 	return fmt.Sprintf("start_block: %v, offset_starts_at_index: %v, election_id: %d, state_roots: %s", ssr.StartBlock, ssr.OffsetStartsAtIndex, ssr.ElectionId, srs)
 }
 
+// SignTxBatchRequest asks the tss nodes to attest the transactions of the
+// l2 blocks [StartBlock, StartBlock+len(TxRoots))
+type SignTxBatchRequest struct {
+	StartBlock *big.Int `json:"start_block"`
+	// TxRoots are the transactions roots of the blocks, in block order
+	TxRoots    [][32]byte `json:"tx_roots"`
+	ElectionId uint64     `json:"election_id"`
+}
+
+func (r SignTxBatchRequest) String() string {
+	var roots string
+	for _, root := range r.TxRoots {
+		roots = roots + hex.EncodeToString(root[:]) + " "
+	}
+	return fmt.Sprintf("start_block: %v, election_id: %d, tx_roots: %s", r.StartBlock, r.ElectionId, roots)
+}
+
 type SlashRequest struct {
 	Address    common.Address `json:"address"`
 	BatchIndex uint64         `json:"batch_index"`
@@ -57,6 +77,9 @@ type RollBackRequest struct {
 
 type AskResponse struct {
 	Result bool `json:"result"`
+	// MismatchIndex is the index of the first state root that differs from
+	// the node's l2 chain, it is only set when Result is false
+	MismatchIndex *uint64 `json:"mismatch_index,omitempty"`
 }
 
 type NodeSignRequest struct {
@@ -81,6 +104,22 @@ type KeygenResponse struct {
 	ClusterPublicKey string `json:"cluster_public_key"`
 }
 
+// ReshareRequest moves the shares of ClusterPublicKey from the old committee to the new one
+type ReshareRequest struct {
+	ClusterPublicKey string   `json:"cluster_public_key"`
+	OldNodes         []string `json:"old_nodes"`
+	OldThreshold     int      `json:"old_threshold"`
+	OldEpoch         uint64   `json:"old_epoch"`
+	NewNodes         []string `json:"new_nodes"`
+	NewThreshold     int      `json:"new_threshold"`
+	ElectionId       uint64   `json:"election_id"`
+	Timestamp        int64    `json:"timestamp"`
+}
+
+type ReshareResponse struct {
+	ClusterPublicKey string `json:"cluster_public_key"`
+}
+
 type SignatureData struct {
 	// Ethereum-style recovery byte; only the first byte is relevant
 	SignatureRecovery []byte `json:"signature_recovery,omitempty"`
@@ -31,6 +31,11 @@ var (
 	groupPublicKeyArguments abi.Arguments
 	slashArguments          abi.Arguments
 	rollBackArguments       abi.Arguments
+	txBatchArguments        abi.Arguments
+
+	// txBatchDomain keeps a tx batch digest from ever matching a state batch
+	// digest, both encode a bytes32[] and a uint256
+	txBatchDomain = crypto.Keccak256Hash([]byte("MANTLE_TSS_TX_BATCH"))
 )
 
 type SlashMsg struct {
@@ -79,6 +84,16 @@ func init() {
 		},
 	}
 	rollBackArguments = abi.Arguments{{Type: typUint256}}
+	typByte32, _ := abi.NewType("bytes32", "bytes32", nil)
+	txBatchArguments = abi.Arguments{
+		{
+			Type: typByte32,
+		}, {
+			Type: typByte32Array,
+		}, {
+			Type: typUint256,
+		},
+	}
 
 }
 
@@ -107,6 +122,14 @@ func StateBatchHash(stateRoots [][32]byte, offsetStartsAtIndex *big.Int) ([]byte
 	return crypto.Keccak256Hash(abiEncodedRaw).Bytes(), nil
 }
 
+func TxBatchHash(txRoots [][32]byte, startBlock *big.Int) ([]byte, error) {
+	abiEncodedRaw, err := txBatchArguments.Pack(txBatchDomain, txRoots, startBlock)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256Hash(abiEncodedRaw).Bytes(), nil
+}
+
 func SlashMsgBytes(batchIndex uint64, jailNode common.Address, tssNodes []common.Address, slashType byte) ([]byte, error) {
 	return slashMsgArguments.Pack(SlashMsg{
 		SlashType:  new(big.Int).SetUint64(uint64(slashType)),
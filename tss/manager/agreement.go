@@ -67,6 +67,9 @@ func (m *Manager) agreement(ctx types.Context, request interface{}, method tss.M
 					}
 					continue
 				}
+				if !askResponse.Result && askResponse.MismatchIndex != nil {
+					log.Warn("node disagrees with the state batch", "node", resp.SourceNode, "mismatch index", *askResponse.MismatchIndex)
+				}
 				results[resp.SourceNode] = askResponse.Result
 				if len(errResp)+len(results) == expectedResponseCount {
 					return
@@ -28,7 +28,7 @@ func TestAgreement(t *testing.T) {
 	manager, request := setup(afterMsgSent, nil)
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 3,
 		})
 	ctx, err := manager.agreement(ctx, request, "ask")
@@ -53,7 +53,7 @@ func TestUnAgreement(t *testing.T) {
 	manager, request := setup(afterMsgSent, nil)
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 3,
 		})
 	ctx, err := manager.agreement(ctx, request, "ask")
@@ -84,7 +84,7 @@ func TestOneRefuseAgreement(t *testing.T) {
 	manager, request := setup(afterMsgSent, nil)
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 3,
 		})
 	ctx, err := manager.agreement(ctx, request, "ask")
@@ -112,7 +112,7 @@ func TestSentErrorAgreement(t *testing.T) {
 	manager, request := setup(afterMsgSent, nil)
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 3,
 		})
 	ctx, err := manager.agreement(ctx, request, "ask")
@@ -121,7 +121,7 @@ func TestSentErrorAgreement(t *testing.T) {
 
 	ctx = types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 2,
 		})
 	ctx, err = manager.agreement(ctx, request, "ask")
@@ -147,7 +147,7 @@ func TestErrorRespAgreement(t *testing.T) {
 	manager, request := setup(afterMsgSent, nil)
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 3,
 		})
 	ctx, err := manager.agreement(ctx, request, "ask")
@@ -156,7 +156,7 @@ func TestErrorRespAgreement(t *testing.T) {
 
 	ctx = types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 2,
 		})
 	ctx, err = manager.agreement(ctx, request, "ask")
@@ -182,7 +182,7 @@ func TestTimeoutAgreement(t *testing.T) {
 	manager, request := setup(afterMsgSent, nil)
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 2,
 		})
 	before := time.Now()
@@ -41,7 +41,7 @@ func Command() *cobra.Command {
 
 func run(cmd *cobra.Command) error {
 	config := common.GetConfigFromCmd(cmd)
-	log.Info("config info print", "MissSignedNumber", config.MissSignedNumber)
+	log.Info("config info print", "SignedBatchesWindow", config.SignedBatchesWindow, "MinSignedPerWindow", config.MinSignedPerWindow, "JailDuration", config.JailDuration)
 	log.Info("l1 start block number", "block number", config.L1StartBlockNumber)
 	l1StartBlockNumber, err := strconv.ParseUint(
 		config.L1StartBlockNumber, 10, 32,
@@ -70,7 +70,12 @@ func run(cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
-	observer = observer.SetHook(slash.NewSlashing(managerStore, managerStore, config.MissSignedNumber))
+	slashing := slash.NewSlashing(managerStore, managerStore, slash.Params{
+		SignedBatchesWindow: config.SignedBatchesWindow,
+		MinSignedPerWindow:  config.MinSignedPerWindow,
+		JailDuration:        config.JailDuration,
+	})
+	observer = observer.SetHook(slashing)
 	observer.Start()
 
 	manager, err := NewManager(wsServer, queryService, managerStore, config)
@@ -79,7 +84,7 @@ func run(cmd *cobra.Command) error {
 	}
 	manager.Start()
 
-	registry := router.NewRegistry(manager, managerStore)
+	registry := router.NewRegistry(manager, managerStore, manager, slashing, manager)
 	r := gin.Default()
 	registry.Register(r)
 
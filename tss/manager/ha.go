@@ -0,0 +1,219 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/influxdata/influxdb/pkg/slices"
+
+	"github.com/mantlenetworkio/mantle/l2geth/log"
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
+)
+
+// recentStateBatchesLimit is how many signed state batches the leader keeps
+// for the standby managers, the indexer of a standby needs them to index
+// the batches the leader signed
+const recentStateBatchesLimit = 64
+
+// isLeader reports whether this manager may sign, generate keys and submit
+// slashing. A manager without HA is always the leader. The lease expiry is
+// checked on every call so a manager whose renewal stalls steps down by
+// itself before another one can take over.
+func (m *Manager) isLeader() bool {
+	if m.leaseBackend == nil {
+		return true
+	}
+	return time.Now().UnixNano() < m.leaseExpiry.Load()
+}
+
+func (m *Manager) campaign() {
+	ticker := time.NewTicker(m.leaseTimeout / 3)
+	defer ticker.Stop()
+	for {
+		m.renewLease()
+		select {
+		case <-m.stopChan:
+			m.leaseExpiry.Store(0)
+			if err := m.leaseBackend.Release(m.nodeId); err != nil {
+				log.Error("failed to release leader lease", "err", err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) renewLease() {
+	wasLeader := m.isLeader()
+	lease, owned, err := m.leaseBackend.Acquire(m.nodeId, m.advertiseUrl, m.leaseTimeout)
+	if err != nil {
+		// keep leading until the lease we already hold expires
+		log.Error("failed to acquire leader lease", "err", err)
+		return
+	}
+	if owned {
+		m.leaseExpiry.Store(lease.Expiry.UnixNano())
+	} else {
+		m.leaseExpiry.Store(0)
+	}
+	if owned != wasLeader {
+		log.Info("tss manager leadership changed", "leader", owned, "holder", lease.Holder, "address", lease.Address)
+	}
+	if owned {
+		m.metics.IsLeader.Set(1)
+	} else {
+		m.metics.IsLeader.Set(0)
+	}
+}
+
+func (m *Manager) Leader() (types.LeaderInfo, error) {
+	if m.leaseBackend == nil {
+		return types.LeaderInfo{IsSelf: true}, nil
+	}
+	lease, err := m.leaseBackend.Current()
+	if err != nil {
+		return types.LeaderInfo{}, err
+	}
+	if lease.Expired(time.Now()) {
+		return types.LeaderInfo{}, nil
+	}
+	return types.LeaderInfo{
+		NodeId:      lease.Holder,
+		Address:     lease.Address,
+		LeaseExpiry: lease.Expiry,
+		IsSelf:      lease.Holder == m.nodeId,
+	}, nil
+}
+
+func (m *Manager) ReplicatedState() (types.ReplicatedState, error) {
+	if !m.isLeader() {
+		return types.ReplicatedState{}, types.ErrNotLeader
+	}
+	var state types.ReplicatedState
+	m.sigCacheLock.RLock()
+	for digest, sig := range m.stateSignatureCache {
+		state.StateSignatures = append(state.StateSignatures, types.StateSignature{Digest: digest, Signature: sig})
+	}
+	m.sigCacheLock.RUnlock()
+
+	m.recentLock.Lock()
+	roots := append([][32]byte{}, m.recentBatchRoots...)
+	m.recentLock.Unlock()
+	for _, root := range roots {
+		// read it back from the store, it may have been indexed meanwhile
+		if found, sbi := m.store.GetStateBatch(root); found {
+			state.StateBatches = append(state.StateBatches, sbi)
+		}
+	}
+	state.SlashingInfos = m.store.ListSlashingInfo()
+	state.Culprits = m.store.GetCulprits()
+	return state, nil
+}
+
+func (m *Manager) recordStateBatch(root [32]byte) {
+	m.recentLock.Lock()
+	defer m.recentLock.Unlock()
+	m.recentBatchRoots = append(m.recentBatchRoots, root)
+	if len(m.recentBatchRoots) > recentStateBatchesLimit {
+		m.recentBatchRoots = m.recentBatchRoots[len(m.recentBatchRoots)-recentStateBatchesLimit:]
+	}
+}
+
+// replicate copies the leader state while this manager is a standby
+func (m *Manager) replicate() {
+	ticker := time.NewTicker(m.syncInterval)
+	defer ticker.Stop()
+	for {
+		if !m.isLeader() {
+			if err := m.syncFromLeader(); err != nil {
+				log.Error("failed to sync state from leader", "err", err)
+			}
+		}
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) syncFromLeader() error {
+	leader, err := m.Leader()
+	if err != nil {
+		return err
+	}
+	if leader.NodeId == "" || leader.IsSelf || leader.Address == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(leader.Address, "/")+"/api/v1/ha/state", nil)
+	if err != nil {
+		return err
+	}
+	if len(m.jwtSecret) > 0 {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"iat": &jwt.NumericDate{Time: time.Now()},
+		}).SignedString(m.jwtSecret)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := m.peerClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("leader %s responded %d", leader.NodeId, resp.StatusCode)
+	}
+	var state types.ReplicatedState
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return err
+	}
+	m.applyReplicatedState(state)
+	return nil
+}
+
+// applyReplicatedState merges the leader state into the local one. Slashing
+// infos and culprits are only ever added, a slashing info the leader already
+// settled is dropped by handleSlashing once its record is found on layer1.
+func (m *Manager) applyReplicatedState(state types.ReplicatedState) {
+	m.sigCacheLock.Lock()
+	for key := range m.stateSignatureCache {
+		delete(m.stateSignatureCache, key)
+	}
+	for _, ss := range state.StateSignatures {
+		m.stateSignatureCache[ss.Digest] = ss.Signature
+	}
+	m.sigCacheLock.Unlock()
+
+	for _, sbi := range state.StateBatches {
+		if found, _ := m.store.GetStateBatch(sbi.BatchRoot); found {
+			continue
+		}
+		if err := m.store.SetStateBatch(sbi); err != nil {
+			log.Error("failed to store replicated state batch", "err", err)
+			continue
+		}
+		m.recordStateBatch(sbi.BatchRoot)
+	}
+	for _, si := range state.SlashingInfos {
+		if found, _ := m.store.GetSlashingInfo(si.Address, si.BatchIndex); !found {
+			m.store.SetSlashingInfo(si)
+		}
+	}
+	localCulprits := m.store.GetCulprits()
+	newCulprits := make([]string, 0)
+	for _, culprit := range state.Culprits {
+		if !slices.Exists(localCulprits, culprit) && !slices.Exists(newCulprits, culprit) {
+			newCulprits = append(newCulprits, culprit)
+		}
+	}
+	if len(newCulprits) > 0 {
+		m.store.AddCulprits(newCulprits)
+	}
+}
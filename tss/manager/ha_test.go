@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	tmtypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+
+	tss "github.com/mantlenetworkio/mantle/tss/common"
+	"github.com/mantlenetworkio/mantle/tss/manager/lock"
+	"github.com/mantlenetworkio/mantle/tss/manager/store"
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
+	"github.com/mantlenetworkio/mantle/tss/ws/client"
+	"github.com/mantlenetworkio/mantle/tss/ws/server"
+)
+
+func freeWsAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return "tcp://" + l.Addr().String()
+}
+
+func setupHAManager(t *testing.T, nodeId string, backend lock.Backend, queryService types.TssQueryService) (*Manager, string) {
+	wsAddr := freeWsAddr(t)
+	wsServer, err := server.NewWSServer(wsAddr, queryService)
+	require.NoError(t, err)
+	storage, err := store.NewStorage("")
+	require.NoError(t, err)
+	return &Manager{
+		wsServer:        wsServer,
+		tssQueryService: queryService,
+		store:           storage,
+		metics:          testMetrics,
+
+		askTimeout:  5 * time.Second,
+		signTimeout: 5 * time.Second,
+
+		stateSignatureCache: make(map[[32]byte][]byte),
+		sigCacheLock:        &sync.RWMutex{},
+		stopChan:            make(chan struct{}),
+
+		leaseBackend: backend,
+		nodeId:       nodeId,
+		advertiseUrl: "http://" + nodeId,
+		leaseTimeout: time.Second,
+	}, wsAddr
+}
+
+func TestStandbySignsAfterFailover(t *testing.T) {
+	clusterKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	clusterPubKey := crypto.CompressPubkey(&clusterKey.PublicKey)
+	nodeKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	nodePubKey := hex.EncodeToString(crypto.CompressPubkey(&nodeKey.PublicKey))
+	queryService := mockTssQueryService{info: types.TssCommitteeInfo{
+		ElectionId:    1,
+		ClusterPubKey: hex.EncodeToString(clusterPubKey),
+		TssMembers:    []string{nodePubKey},
+		Threshold:     0,
+	}}
+
+	backend, err := lock.NewFileBackend(filepath.Join(t.TempDir(), "leader.lock"))
+	require.NoError(t, err)
+	leader, leaderAddr := setupHAManager(t, "manager-0", backend, queryService)
+	standby, standbyAddr := setupHAManager(t, "manager-1", backend, queryService)
+
+	// the node is connected to both managers and agrees to sign every tx batch
+	wsClient, err := client.NewWSClient(leaderAddr+","+standbyAddr, "/ws", nodeKey, nodePubKey)
+	require.NoError(t, err)
+	defer wsClient.Stop()
+	reqChan := make(chan tmtypes.RPCRequest)
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	require.NoError(t, wsClient.RegisterResChannel(reqChan, stopChan))
+	go func() {
+		for {
+			select {
+			case req := <-reqChan:
+				var nodeRequest tss.NodeSignRequest
+				var txBatchRequest tss.SignTxBatchRequest
+				nodeRequest.RequestBody = &txBatchRequest
+				if err := json.Unmarshal(req.Params, &nodeRequest); err != nil {
+					t.Error(err)
+					return
+				}
+				var result interface{} = tss.AskResponse{Result: true}
+				if req.Method == tss.SignTxBatch.String() {
+					digest, _ := tss.TxBatchHash(txBatchRequest.TxRoots, txBatchRequest.StartBlock)
+					signature, _ := crypto.Sign(digest, clusterKey)
+					result = tss.SignResponse{Signature: signature}
+				}
+				if err := wsClient.SendMsg(tmtypes.NewRPCSuccessResponse(req.ID, result)); err != nil {
+					t.Error(err)
+					return
+				}
+			case <-stopChan:
+				return
+			}
+		}
+	}()
+	for _, m := range []*Manager{leader, standby} {
+		require.Eventually(t, func() bool {
+			return len(m.availableNodes(queryService.info.TssMembers)) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	leader.renewLease()
+	standby.renewLease()
+	require.True(t, leader.isLeader())
+	require.False(t, standby.isLeader())
+
+	request := tss.SignTxBatchRequest{StartBlock: big.NewInt(1), TxRoots: [][32]byte{{1}}}
+	_, err = leader.SignTxBatch(request)
+	require.NoError(t, err)
+	_, err = standby.SignTxBatch(request)
+	require.ErrorIs(t, err, types.ErrNotLeader)
+
+	// the leader stops renewing its lease, the standby takes it over once it
+	// expires and signs with the nodes it is already connected to
+	require.Eventually(t, func() bool {
+		standby.renewLease()
+		return standby.isLeader()
+	}, 5*time.Second, 50*time.Millisecond)
+	require.False(t, leader.isLeader())
+
+	request = tss.SignTxBatchRequest{StartBlock: big.NewInt(2), TxRoots: [][32]byte{{2}, {3}}}
+	respBz, err := standby.SignTxBatch(request)
+	require.NoError(t, err)
+	var resp tss.BatchSubmitterResponse
+	require.NoError(t, json.Unmarshal(respBz, &resp))
+	digest, err := tss.TxBatchHash(request.TxRoots, request.StartBlock)
+	require.NoError(t, err)
+	require.True(t, crypto.VerifySignature(clusterPubKey, digest, resp.Signature[:64]))
+}
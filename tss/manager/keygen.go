@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,7 +24,7 @@ func (m *Manager) observeElection() {
 	queryTicker := time.NewTicker(m.taskInterval + 30*time.Second)
 	for {
 		log.Info("trying to handle new election...", "stopGenKey", m.stopGenKey)
-		if !m.stopGenKey {
+		if !m.stopGenKey && m.isLeader() {
 			func() {
 				// check if new round election is held(inactive tss members)
 				tssInfo, err := m.tssQueryService.QueryInactiveInfo()
@@ -56,16 +57,37 @@ func (m *Manager) observeElection() {
 					if len(cpkData.Cpk) != 0 && time.Now().Sub(cpkData.CreationTime).Hours() < m.cpkConfirmTimeout.Hours() { // cpk is generated, but has not been confirmed yet
 						return
 					}
-					cpk, err := m.generateKey(tssInfo.TssMembers, tssInfo.Threshold, tssInfo.ElectionId)
-					if err != nil {
-						log.Error("failed to generate key", "err", err)
-						return
+					// keep the cluster key of the active committee if it can be reshared,
+					// otherwise generate a new one
+					var cpk string
+					var keyEpoch uint64
+					if activeInfo, oldEpoch, ok := m.reshareSource(); ok {
+						var culprits []string
+						cpk, culprits, err = m.reshareKey(activeInfo, oldEpoch, tssInfo.TssMembers, tssInfo.Threshold, tssInfo.ElectionId)
+						if len(culprits) > 0 {
+							log.Error("failed to reshare key, found culprits", "culprits", strings.Join(culprits, ","), "err", err)
+							m.store.AddCulprits(culprits)
+							return
+						}
+						if err != nil {
+							log.Warn("failed to reshare key, fall back to keygen", "err", err)
+						} else {
+							keyEpoch = tssInfo.ElectionId
+						}
+					}
+					if len(cpk) == 0 {
+						cpk, err = m.generateKey(tssInfo.TssMembers, tssInfo.Threshold, tssInfo.ElectionId)
+						if err != nil {
+							log.Error("failed to generate key", "err", err)
+							return
+						}
 					}
 
 					if err = m.store.Insert(types.CpkData{
 						Cpk:          cpk,
 						ElectionId:   tssInfo.ElectionId,
 						CreationTime: time.Now(),
+						KeyEpoch:     keyEpoch,
 					}); err != nil {
 						log.Error("failed to get cpk from storage", "err", err)
 					}
@@ -81,12 +103,13 @@ func (m *Manager) observeElection() {
 	}
 }
 
-func (m *Manager) generateKey(tssMembers []string, threshold int, electionId uint64) (string, error) {
+func (m *Manager) generateKey(tssMembers []string, threshold int, electionId uint64) (cpk string, err error) {
 	availableNodes := m.availableNodes(tssMembers)
+	requestId := randomRequestId()
+	defer m.recordSession(types.SessionKeygen, "", requestId, electionId, availableNodes, time.Now(), nil, &err)
 	if len(availableNodes) < len(tssMembers) {
 		return "", errors.New("not enough available nodes to generate CPK")
 	}
-	requestId := randomRequestId()
 	respChan := make(chan server.ResponseMsg)
 	stopChan := make(chan struct{})
 	if err := m.wsServer.RegisterResChannel(requestId, respChan, stopChan); err != nil {
@@ -9,6 +9,7 @@ import (
 	tmtypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 
 	tss "github.com/mantlenetworkio/mantle/tss/common"
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
 	"github.com/mantlenetworkio/mantle/tss/ws/server"
 )
 
@@ -111,4 +112,11 @@ func TestNotEnoughAliveNodesKeygen(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorContains(t, err, "not enough available nodes")
 	require.EqualValues(t, 0, len(cpk))
+
+	// the failed keygen is still recorded
+	sessions := manager.Sessions(types.SessionKeygen, 0)
+	require.Len(t, sessions, 1)
+	require.False(t, sessions[0].Success)
+	require.Equal(t, []string{"a", "b", "c"}, sessions[0].Participants)
+	require.Contains(t, sessions[0].Error, "not enough available nodes")
 }
@@ -0,0 +1,132 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Lease is the leadership of a manager, it is valid until Expiry unless the
+// holder renews it
+type Lease struct {
+	Holder  string    `json:"holder"`
+	Address string    `json:"address"`
+	Expiry  time.Time `json:"expiry"`
+}
+
+func (l Lease) Expired(now time.Time) bool {
+	return l.Holder == "" || !now.Before(l.Expiry)
+}
+
+// Backend stores the lease shared by the managers
+type Backend interface {
+	// Acquire grants the lease to holder when it is free, expired or already
+	// held by holder, and renews it for ttl. It returns the lease in force
+	// afterwards and whether holder owns it.
+	Acquire(holder, address string, ttl time.Duration) (Lease, bool, error)
+	// Release gives up the lease if holder owns it
+	Release(holder string) error
+	// Current returns the lease in force, it may be expired
+	Current() (Lease, error)
+}
+
+// FileBackend keeps the lease in a file guarded by flock, every manager must
+// see the same file
+type FileBackend struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileBackend(path string) (*FileBackend, error) {
+	if path == "" {
+		return nil, errors.New("empty lock file path")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open lock file %s: %w", path, err)
+	}
+	_ = f.Close()
+	return &FileBackend{path: path}, nil
+}
+
+func (b *FileBackend) Acquire(holder, address string, ttl time.Duration) (Lease, bool, error) {
+	var (
+		lease Lease
+		owned bool
+	)
+	err := b.update(func(current Lease) (Lease, bool) {
+		now := time.Now()
+		if current.Holder != holder && !current.Expired(now) {
+			lease = current
+			return current, false
+		}
+		lease = Lease{Holder: holder, Address: address, Expiry: now.Add(ttl)}
+		owned = true
+		return lease, true
+	})
+	return lease, owned, err
+}
+
+func (b *FileBackend) Release(holder string) error {
+	return b.update(func(current Lease) (Lease, bool) {
+		if current.Holder != holder {
+			return current, false
+		}
+		return Lease{}, true
+	})
+}
+
+func (b *FileBackend) Current() (Lease, error) {
+	var lease Lease
+	err := b.update(func(current Lease) (Lease, bool) {
+		lease = current
+		return current, false
+	})
+	return lease, err
+}
+
+// update runs fn on the stored lease while holding the file lock and writes
+// back the lease fn returns when it asks to
+func (b *FileBackend) update(fn func(Lease) (Lease, bool)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	f, err := os.OpenFile(b.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("fail to lock %s: %w", b.path, err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	bz, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	var current Lease
+	if len(bz) > 0 {
+		if err := json.Unmarshal(bz, &current); err != nil {
+			return fmt.Errorf("fail to decode lease in %s: %w", b.path, err)
+		}
+	}
+	next, write := fn(current)
+	if !write {
+		return nil
+	}
+	if bz, err = json.Marshal(next); err != nil {
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(bz, 0); err != nil {
+		return err
+	}
+	return f.Sync()
+}
@@ -0,0 +1,62 @@
+package lock
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lease")
+	a, err := NewFileBackend(path)
+	require.NoError(t, err)
+	b, err := NewFileBackend(path)
+	require.NoError(t, err)
+
+	lease, owned, err := a.Acquire("a", "http://a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, owned)
+	require.EqualValues(t, "http://a", lease.Address)
+
+	lease, owned, err = b.Acquire("b", "http://b", time.Minute)
+	require.NoError(t, err)
+	require.False(t, owned)
+	require.EqualValues(t, "a", lease.Holder)
+
+	// renewing keeps the lease
+	_, owned, err = a.Acquire("a", "http://a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, owned)
+
+	// only the holder can release
+	require.NoError(t, b.Release("b"))
+	current, err := b.Current()
+	require.NoError(t, err)
+	require.EqualValues(t, "a", current.Holder)
+
+	require.NoError(t, a.Release("a"))
+	current, err = b.Current()
+	require.NoError(t, err)
+	require.True(t, current.Expired(time.Now()))
+
+	_, owned, err = b.Acquire("b", "http://b", time.Minute)
+	require.NoError(t, err)
+	require.True(t, owned)
+}
+
+func TestFileBackendExpiry(t *testing.T) {
+	backend, err := NewFileBackend(filepath.Join(t.TempDir(), "lease"))
+	require.NoError(t, err)
+
+	_, owned, err := backend.Acquire("a", "http://a", 10*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, owned)
+	time.Sleep(20 * time.Millisecond)
+
+	lease, owned, err := backend.Acquire("b", "http://b", time.Minute)
+	require.NoError(t, err)
+	require.True(t, owned)
+	require.EqualValues(t, "b", lease.Holder)
+}
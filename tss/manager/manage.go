@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
@@ -10,8 +11,10 @@ import (
 	"math"
 	"math/big"
 	"math/rand"
+	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -20,10 +23,12 @@ import (
 
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/influxdata/influxdb/pkg/slices"
+	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
 	"github.com/mantlenetworkio/mantle/l2geth/crypto"
 	"github.com/mantlenetworkio/mantle/l2geth/log"
 	tss "github.com/mantlenetworkio/mantle/tss/common"
 	"github.com/mantlenetworkio/mantle/tss/index"
+	"github.com/mantlenetworkio/mantle/tss/manager/lock"
 	"github.com/mantlenetworkio/mantle/tss/manager/metics"
 	"github.com/mantlenetworkio/mantle/tss/manager/types"
 	"github.com/mantlenetworkio/mantle/tss/slash"
@@ -45,6 +50,7 @@ type Manager struct {
 	taskInterval          time.Duration
 	confirmReceiptTimeout time.Duration
 	keygenTimeout         time.Duration
+	reshareTimeout        time.Duration
 	cpkConfirmTimeout     time.Duration
 	askTimeout            time.Duration
 	signTimeout           time.Duration
@@ -52,8 +58,21 @@ type Manager struct {
 	stateSignatureCache map[[32]byte][]byte
 	sigCacheLock        *sync.RWMutex
 	stopGenKey          bool
+	disableReshare      bool
 	stopChan            chan struct{}
 	metics              *metics.Metrics
+
+	// leaseBackend is nil unless the manager runs in HA mode
+	leaseBackend     lock.Backend
+	nodeId           string
+	advertiseUrl     string
+	leaseTimeout     time.Duration
+	syncInterval     time.Duration
+	leaseExpiry      atomic.Int64
+	jwtSecret        []byte
+	peerClient       *http.Client
+	recentBatchRoots [][32]byte
+	recentLock       sync.Mutex
 }
 
 func NewManager(wsServer server.IWebsocketManager,
@@ -72,6 +91,10 @@ func NewManager(wsServer server.IWebsocketManager,
 	if err != nil {
 		return nil, err
 	}
+	reshareTimeoutDur, err := time.ParseDuration(config.Manager.ReshareTimeout)
+	if err != nil {
+		return nil, err
+	}
 	cpkConfirmTimeoutDur, err := time.ParseDuration(config.Manager.CPKConfirmTimeout)
 	if err != nil {
 		return nil, err
@@ -107,7 +130,7 @@ func NewManager(wsServer server.IWebsocketManager,
 		return nil, err
 	}
 
-	return &Manager{
+	manager := &Manager{
 		wsServer:                  wsServer,
 		tssQueryService:           tssQueryService,
 		store:                     store,
@@ -122,20 +145,63 @@ func NewManager(wsServer server.IWebsocketManager,
 		taskInterval:          taskIntervalDur,
 		confirmReceiptTimeout: receiptConfirmTimeoutDur,
 		keygenTimeout:         keygenTimeoutDur,
+		reshareTimeout:        reshareTimeoutDur,
 		cpkConfirmTimeout:     cpkConfirmTimeoutDur,
 		askTimeout:            askTimeoutDur,
 		signTimeout:           signTimeoutDur,
 
 		stateSignatureCache: make(map[[32]byte][]byte),
 		sigCacheLock:        &sync.RWMutex{},
+		disableReshare:      config.Manager.DisableReshare,
 		stopChan:            make(chan struct{}),
 		metics:              metics.PrometheusMetrics("tssmanager"),
-	}, nil
+	}
+	if config.Manager.HA.Enable {
+		if err = manager.setupHA(config); err != nil {
+			return nil, err
+		}
+	}
+	return manager, nil
+}
+
+func (m *Manager) setupHA(config tss.Configuration) error {
+	haConfig := config.Manager.HA
+	if len(haConfig.NodeId) == 0 || len(haConfig.AdvertiseUrl) == 0 {
+		return errors.New("ha mode needs node_id and advertise_url")
+	}
+	leaseTimeout, err := time.ParseDuration(haConfig.LeaseTimeout)
+	if err != nil {
+		return err
+	}
+	syncInterval, err := time.ParseDuration(haConfig.SyncInterval)
+	if err != nil {
+		return err
+	}
+	backend, err := lock.NewFileBackend(haConfig.LockFile)
+	if err != nil {
+		return err
+	}
+	if len(config.Manager.JwtSecret) != 0 {
+		if m.jwtSecret, err = hexutil.Decode(config.Manager.JwtSecret); err != nil {
+			return err
+		}
+	}
+	m.leaseBackend = backend
+	m.nodeId = haConfig.NodeId
+	m.advertiseUrl = haConfig.AdvertiseUrl
+	m.leaseTimeout = leaseTimeout
+	m.syncInterval = syncInterval
+	m.peerClient = &http.Client{Timeout: syncInterval}
+	return nil
 }
 
 // Start launch a manager
 func (m *Manager) Start() {
 	log.Info("manager is starting......")
+	if m.leaseBackend != nil {
+		go m.campaign()
+		go m.replicate()
+	}
 	go m.observeElection()
 	go m.slashing()
 }
@@ -154,6 +220,9 @@ func (m *Manager) recoverGenerateKey() {
 
 func (m *Manager) SignStateBatch(request tss.SignStateRequest) ([]byte, error) {
 	log.Info("received sign state request", "start block", request.StartBlock, "len", len(request.StateRoots), "index", request.OffsetStartsAtIndex)
+	if !m.isLeader() {
+		return nil, types.ErrNotLeader
+	}
 	digestBz, err := tss.StateBatchHash(request.StateRoots, request.OffsetStartsAtIndex)
 	if err != nil {
 		return nil, err
@@ -284,6 +353,9 @@ func (m *Manager) SignStateBatch(request tss.SignStateRequest) ([]byte, error) {
 
 func (m *Manager) SignRollBack(request tss.SignStateRequest) ([]byte, error) {
 	log.Info("received roll back request", "request", request.String())
+	if !m.isLeader() {
+		return nil, types.ErrNotLeader
+	}
 
 	tssInfo, err := m.tssQueryService.QueryActiveInfo()
 	if err != nil {
@@ -334,8 +406,70 @@ func (m *Manager) SignRollBack(request tss.SignStateRequest) ([]byte, error) {
 	return responseBytes, nil
 }
 
-func (m *Manager) SignTxBatch() error {
-	return errors.New("not support for now")
+func (m *Manager) SignTxBatch(request tss.SignTxBatchRequest) ([]byte, error) {
+	log.Info("received sign tx batch request", "request", request.String())
+	if !m.isLeader() {
+		return nil, types.ErrNotLeader
+	}
+	digestBz, err := tss.TxBatchHash(request.TxRoots, request.StartBlock)
+	if err != nil {
+		return nil, err
+	}
+	if found, tbi := m.store.GetTxBatch(request.StartBlock.Uint64()); found && bytes.Equal(tbi.BatchHash[:], digestBz) {
+		log.Info("get stored tx batch signature", "start block", tbi.StartBlock, "end block", tbi.EndBlock)
+		return json.Marshal(tss.BatchSubmitterResponse{Signature: tbi.Signature})
+	}
+
+	tssInfo, err := m.tssQueryService.QueryActiveInfo()
+	if err != nil {
+		return nil, err
+	}
+	availableNodes := m.availableNodes(tssInfo.TssMembers)
+	if len(availableNodes) < tssInfo.Threshold+1 {
+		return nil, errors.New("not enough available nodes to sign tx batch")
+	}
+
+	ctx := types.NewContext().
+		WithAvailableNodes(availableNodes).
+		WithTssInfo(tssInfo).
+		WithRequestId(randomRequestId()).
+		WithElectionId(tssInfo.ElectionId)
+
+	// ask tss nodes for the agreement
+	ctx, err = m.agreement(ctx, request, tss.AskTxBatch)
+	if err != nil {
+		return nil, err
+	}
+	if len(ctx.Approvers()) < ctx.TssInfos().Threshold+1 {
+		return nil, errors.New("failed to sign tx batch, approvals " + strings.Join(ctx.Approvers(), ",") + " ,unApprovals " + strings.Join(ctx.UnApprovers(), ","))
+	}
+
+	request.ElectionId = tssInfo.ElectionId
+	resp, culprits, err := m.sign(ctx, request, digestBz, tss.SignTxBatch)
+	if err != nil {
+		m.store.AddCulprits(culprits)
+		return nil, err
+	}
+	tbi := types.TxBatchInfo{
+		StartBlock: request.StartBlock.Uint64(),
+		EndBlock:   request.StartBlock.Uint64() + uint64(len(request.TxRoots)),
+		Signature:  resp.Signature,
+		ElectionId: tssInfo.ElectionId,
+	}
+	copy(tbi.BatchHash[:], digestBz)
+	if err = m.store.SetTxBatch(tbi); err != nil {
+		log.Error("failed to store the signed tx batch", "start block", tbi.StartBlock, "err", err)
+	}
+
+	response := tss.BatchSubmitterResponse{
+		Signature: resp.Signature,
+	}
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		log.Error("batch submitter response failed to marshal !")
+		return nil, err
+	}
+	return responseBytes, nil
 }
 
 func (m *Manager) availableNodes(tssMembers []string) []string {
@@ -373,6 +507,7 @@ func (m *Manager) afterSignStateBatch(ctx types.Context, stateBatch [][32]byte,
 	if err = m.store.SetStateBatch(sbi); err != nil {
 		return err
 	}
+	m.recordStateBatch(batchRoot)
 	return nil
 }
 
@@ -14,6 +14,7 @@ type Metrics struct {
 	SlashCount         metrics.Gauge
 	ActiveMembersCount metrics.Gauge
 	ApproveNumber      metrics.Gauge
+	IsLeader           metrics.Gauge
 }
 
 func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
@@ -66,6 +67,13 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "active_counter",
 			Help:      "active node behavior",
 		}, labels).With(labelsAndValues...)
+	var leader = prometheus.NewGaugeFrom(
+		stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "ha",
+			Name:      "is_leader",
+			Help:      "1 when this manager holds the leader lease",
+		}, labels).With(labelsAndValues...)
 
 	return &Metrics{
 		OnlineNodesCount:   online,
@@ -74,6 +82,7 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 		ActiveMembersCount: active,
 		RollbackCount:      rollback,
 		ApproveNumber:      approve,
+		IsLeader:           leader,
 	}
 
 }
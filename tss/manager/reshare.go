@@ -0,0 +1,175 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/slices"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmtypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+
+	"github.com/mantlenetworkio/mantle/l2geth/log"
+	tss "github.com/mantlenetworkio/mantle/tss/common"
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
+	"github.com/mantlenetworkio/mantle/tss/ws/server"
+)
+
+// reshareSource returns the active committee and the key epoch of its shares
+// when the cluster key of the active committee can be reshared to a new one
+func (m *Manager) reshareSource() (*types.TssCommitteeInfo, uint64, bool) {
+	if m.disableReshare {
+		return nil, 0, false
+	}
+	activeInfo, err := m.tssQueryService.QueryActiveInfo()
+	if err != nil {
+		log.Warn("failed to query active info, can not reshare", "err", err)
+		return nil, 0, false
+	}
+	if len(activeInfo.ClusterPubKey) == 0 {
+		return nil, 0, false
+	}
+	cpkData, err := m.store.GetByElectionId(activeInfo.ElectionId)
+	if err != nil {
+		log.Warn("failed to get the cpk of the active election from storage", "err", err)
+		return nil, 0, false
+	}
+	if len(cpkData.Cpk) != 0 && !strings.EqualFold(cpkData.Cpk, activeInfo.ClusterPubKey) {
+		log.Warn("the stored cpk of the active election does not match l1", "election_id", activeInfo.ElectionId)
+		return nil, 0, false
+	}
+	return activeInfo, cpkData.KeyEpoch, true
+}
+
+// reshareKey moves the shares of the active cluster key to the members of the new election,
+// the nodes blamed by more than the threshold of the participants are returned as culprits
+func (m *Manager) reshareKey(activeInfo *types.TssCommitteeInfo, oldEpoch uint64, tssMembers []string, threshold int, electionId uint64) (cpk string, culprits []string, err error) {
+	oldNodes := m.availableNodes(activeInfo.TssMembers)
+	if len(oldNodes) <= activeInfo.Threshold {
+		return "", nil, errors.New("not enough available nodes of the active committee to reshare CPK")
+	}
+	newNodes := m.availableNodes(tssMembers)
+	if len(newNodes) < len(tssMembers) {
+		return "", nil, errors.New("not enough available nodes to reshare CPK")
+	}
+	participants := append([]string{}, oldNodes...)
+	for _, node := range newNodes {
+		if !slices.ExistsIgnoreCase(participants, node) {
+			participants = append(participants, node)
+		}
+	}
+
+	requestId := randomRequestId()
+	defer m.recordSession(types.SessionReshare, "", requestId, electionId, participants, time.Now(), &culprits, &err)
+	respChan := make(chan server.ResponseMsg)
+	stopChan := make(chan struct{})
+	if err := m.wsServer.RegisterResChannel(requestId, respChan, stopChan); err != nil {
+		log.Error("failed to register response channel", "err", err)
+		return "", nil, err
+	}
+
+	sendError := make(chan struct{})
+	clusterPublicKeys := make(map[string]string, 0)
+	responseNodes := make(map[string]struct{}, 0)
+	counter := &Counter{}
+	var anyError error
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		cctx, cancel := context.WithTimeout(context.Background(), m.reshareTimeout)
+		defer func() {
+			log.Info("exit accept reshare response goroutine")
+			cancel()
+			close(stopChan)
+			wg.Done()
+		}()
+		for {
+			select {
+			case <-sendError:
+				anyError = errors.New("failed to send request to node")
+				log.Error("failed to send request to node")
+				return
+			case <-cctx.Done():
+				anyError = errors.New("wait nodes for reshare response timeout")
+				log.Error("wait nodes for reshare response timeout")
+				return
+			case resp := <-respChan:
+				log.Info("received reshare response", "response", resp.RpcResponse.String(), "node", resp.SourceNode)
+				if _, ok := responseNodes[resp.SourceNode]; ok || !slices.ExistsIgnoreCase(participants, resp.SourceNode) {
+					continue
+				}
+				responseNodes[resp.SourceNode] = struct{}{}
+				if resp.RpcResponse.Error != nil {
+					if resp.RpcResponse.Error.Code == tss.CulpritErrorCode {
+						culprits := deDuplication(strings.Split(resp.RpcResponse.Error.Data, ","))
+						for _, culprit := range culprits {
+							if slices.ExistsIgnoreCase(participants, culprit) {
+								counter.increment(culprit)
+							}
+						}
+					}
+					anyError = errors.New(resp.RpcResponse.Error.Error())
+					log.Error("returns error", "node", resp.SourceNode)
+					continue
+				}
+				var reshareResp tss.ReshareResponse
+				if err := tmjson.Unmarshal(resp.RpcResponse.Result, &reshareResp); err != nil {
+					anyError = err
+					log.Error("failed to Unmarshal ReshareResponse", "err", err)
+					continue
+				}
+				clusterPublicKeys[resp.SourceNode] = reshareResp.ClusterPublicKey
+			default:
+				if len(responseNodes) == len(participants) {
+					return
+				}
+			}
+		}
+	}()
+
+	m.callReshare(participants, tss.ReshareRequest{
+		ClusterPublicKey: activeInfo.ClusterPubKey,
+		OldNodes:         oldNodes,
+		OldThreshold:     activeInfo.Threshold,
+		OldEpoch:         oldEpoch,
+		NewNodes:         newNodes,
+		NewThreshold:     threshold,
+		ElectionId:       electionId,
+	}, requestId, sendError)
+	wg.Wait()
+
+	if anyError != nil {
+		return "", counter.satisfied(activeInfo.Threshold + 1), anyError
+	}
+	if len(clusterPublicKeys) != len(participants) {
+		return "", nil, errors.New("timeout")
+	}
+	for node, cpk := range clusterPublicKeys {
+		if !strings.EqualFold(cpk, activeInfo.ClusterPubKey) {
+			return "", nil, fmt.Errorf("node %s reshared a different CPK", node)
+		}
+	}
+	return activeInfo.ClusterPubKey, nil, nil
+}
+
+func (m *Manager) callReshare(participants []string, request tss.ReshareRequest, requestId string, sendError chan struct{}) {
+	for _, node := range participants {
+		nodeRequest := request
+		nodeRequest.Timestamp = time.Now().UnixMilli()
+		requestBz, _ := json.Marshal(nodeRequest)
+		go func(node string, requestBz []byte) {
+			requestMsg := server.RequestMsg{
+				TargetNode: node,
+				RpcRequest: tmtypes.NewRPCRequest(tmtypes.JSONRPCStringID(requestId), tss.Reshare.String(), requestBz),
+			}
+			if err := m.wsServer.SendMsg(requestMsg); err != nil {
+				sendError <- struct{}{}
+			}
+		}(node, requestBz)
+	}
+}
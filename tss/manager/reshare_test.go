@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmtypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+
+	tss "github.com/mantlenetworkio/mantle/tss/common"
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
+	"github.com/mantlenetworkio/mantle/tss/ws/server"
+)
+
+func TestReshare(t *testing.T) {
+	var afterMsgSent afterMsgSendFunc = func(request server.RequestMsg, respCh chan server.ResponseMsg) error {
+		reshareResp := tss.ReshareResponse{
+			ClusterPublicKey: "abcd",
+		}
+		rpcResp := tmtypes.NewRPCSuccessResponse(request.RpcRequest.ID, reshareResp)
+		respCh <- server.ResponseMsg{
+			RpcResponse: rpcResp,
+			SourceNode:  request.TargetNode,
+		}
+		return nil
+	}
+	var queryAliveNodes queryAliveNodesFunc = func() []string {
+		return []string{"a", "b", "c", "d", "e"}
+	}
+	manager, _ := setup(afterMsgSent, queryAliveNodes)
+	activeInfo := &types.TssCommitteeInfo{
+		ElectionId:    1,
+		ClusterPubKey: "abcd",
+		TssMembers:    []string{"a", "b", "c"},
+		Threshold:     1,
+	}
+	cpk, culprits, err := manager.reshareKey(activeInfo, 0, []string{"c", "d", "e"}, 1, 2)
+	require.NoError(t, err)
+	require.Empty(t, culprits)
+	require.EqualValues(t, "abcd", cpk)
+}
+
+func TestReshareDifferentCPK(t *testing.T) {
+	var afterMsgSent afterMsgSendFunc = func(request server.RequestMsg, respCh chan server.ResponseMsg) error {
+		reshareResp := tss.ReshareResponse{
+			ClusterPublicKey: "abcd",
+		}
+		if request.TargetNode == "d" {
+			reshareResp.ClusterPublicKey = "abc"
+		}
+		rpcResp := tmtypes.NewRPCSuccessResponse(request.RpcRequest.ID, reshareResp)
+		respCh <- server.ResponseMsg{
+			RpcResponse: rpcResp,
+			SourceNode:  request.TargetNode,
+		}
+		return nil
+	}
+	var queryAliveNodes queryAliveNodesFunc = func() []string {
+		return []string{"a", "b", "c", "d"}
+	}
+	manager, _ := setup(afterMsgSent, queryAliveNodes)
+	activeInfo := &types.TssCommitteeInfo{
+		ElectionId:    1,
+		ClusterPubKey: "abcd",
+		TssMembers:    []string{"a", "b", "c"},
+		Threshold:     1,
+	}
+	cpk, _, err := manager.reshareKey(activeInfo, 0, []string{"b", "c", "d"}, 1, 2)
+	require.ErrorContains(t, err, "reshared a different CPK")
+	require.EqualValues(t, 0, len(cpk))
+}
+
+func TestReshareCulprits(t *testing.T) {
+	var afterMsgSent afterMsgSendFunc = func(request server.RequestMsg, respCh chan server.ResponseMsg) error {
+		rpcResp := tmtypes.NewRPCErrorResponse(request.RpcRequest.ID, tss.CulpritErrorCode, "find culprits", "a")
+		if request.TargetNode == "a" {
+			rpcResp = tmtypes.NewRPCErrorResponse(request.RpcRequest.ID, tss.CulpritErrorCode, "find culprits", "b")
+		}
+		respCh <- server.ResponseMsg{
+			RpcResponse: rpcResp,
+			SourceNode:  request.TargetNode,
+		}
+		return nil
+	}
+	var queryAliveNodes queryAliveNodesFunc = func() []string {
+		return []string{"a", "b", "c", "d"}
+	}
+	manager, _ := setup(afterMsgSent, queryAliveNodes)
+	activeInfo := &types.TssCommitteeInfo{
+		ElectionId:    1,
+		ClusterPubKey: "abcd",
+		TssMembers:    []string{"a", "b", "c"},
+		Threshold:     1,
+	}
+	cpk, culprits, err := manager.reshareKey(activeInfo, 0, []string{"b", "c", "d"}, 1, 2)
+	require.Error(t, err)
+	require.EqualValues(t, 0, len(cpk))
+	require.Equal(t, []string{"a"}, culprits)
+}
+
+func TestReshareNotEnoughOldNodes(t *testing.T) {
+	var queryAliveNodes queryAliveNodesFunc = func() []string {
+		return []string{"a", "c", "d"}
+	}
+	manager, _ := setup(nil, queryAliveNodes)
+	activeInfo := &types.TssCommitteeInfo{
+		ElectionId:    1,
+		ClusterPubKey: "abcd",
+		TssMembers:    []string{"a", "b"},
+		Threshold:     1,
+	}
+	_, _, err := manager.reshareKey(activeInfo, 0, []string{"c", "d"}, 1, 2)
+	require.ErrorContains(t, err, "not enough available nodes of the active committee")
+}
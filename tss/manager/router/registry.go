@@ -11,20 +11,27 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
 	"github.com/mantlenetworkio/mantle/l2geth/log"
 	tss "github.com/mantlenetworkio/mantle/tss/common"
 	"github.com/mantlenetworkio/mantle/tss/manager/types"
 )
 
 type Registry struct {
-	signService  types.SignService
-	adminService types.AdminService
+	signService     types.SignService
+	adminService    types.AdminService
+	haService       types.HAService
+	livenessService types.LivenessService
+	sessionService  types.SessionService
 }
 
-func NewRegistry(signService types.SignService, adminService types.AdminService) *Registry {
+func NewRegistry(signService types.SignService, adminService types.AdminService, haService types.HAService, livenessService types.LivenessService, sessionService types.SessionService) *Registry {
 	return &Registry{
-		signService:  signService,
-		adminService: adminService,
+		signService:     signService,
+		adminService:    adminService,
+		haService:       haService,
+		livenessService: livenessService,
+		sessionService:  sessionService,
 	}
 }
 
@@ -56,6 +63,10 @@ func (registry *Registry) SignStateHandler() gin.HandlerFunc {
 			c.String(http.StatusBadRequest, "invalid request type %d, expected request type: 0 and 1", request.Type)
 			return
 		}
+		if errors.Is(err, types.ErrNotLeader) {
+			registry.notLeader(c)
+			return
+		}
 		if err != nil {
 			c.String(http.StatusInternalServerError, "failed to sign state")
 			log.Error("failed to sign state", "error", err)
@@ -67,6 +78,37 @@ func (registry *Registry) SignStateHandler() gin.HandlerFunc {
 	}
 }
 
+func (registry *Registry) SignTxBatchHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request tss.SignTxBatchRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, errors.New("invalid request body"))
+			return
+		}
+		if request.StartBlock == nil || request.StartBlock.Cmp(big.NewInt(0)) < 0 {
+			c.JSON(http.StatusBadRequest, errors.New("StartBlock must not be nil or negative"))
+			return
+		}
+		if len(request.TxRoots) == 0 {
+			c.String(http.StatusBadRequest, "empty tx roots")
+			return
+		}
+		signature, err := registry.signService.SignTxBatch(request)
+		if errors.Is(err, types.ErrNotLeader) {
+			registry.notLeader(c)
+			return
+		}
+		if err != nil {
+			c.String(http.StatusInternalServerError, "failed to sign tx batch")
+			log.Error("failed to sign tx batch", "error", err)
+			return
+		}
+		if _, err = c.Writer.Write(signature); err != nil {
+			log.Error("failed to write signature to response writer", "error", err)
+		}
+	}
+}
+
 func (registry *Registry) ResetHeightHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		heightStr := c.PostForm("height")
@@ -120,6 +162,179 @@ func (registry *Registry) DeleteSlashHandler() gin.HandlerFunc {
 	}
 }
 
+// notLeader answers a standby manager request, the leader address lets the
+// client go straight to the leader
+func (registry *Registry) notLeader(c *gin.Context) {
+	if leader, err := registry.haService.Leader(); err == nil && leader.Address != "" {
+		c.Header("X-Tss-Leader", leader.Address)
+	}
+	c.String(http.StatusServiceUnavailable, types.ErrNotLeader.Error())
+}
+
+func (registry *Registry) LeaderHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		leader, err := registry.haService.Leader()
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			log.Error("failed to get leader", "error", err)
+			return
+		}
+		c.JSON(http.StatusOK, leader)
+	}
+}
+
+func (registry *Registry) ReplicatedStateHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state, err := registry.haService.ReplicatedState()
+		if errors.Is(err, types.ErrNotLeader) {
+			registry.notLeader(c)
+			return
+		}
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			log.Error("failed to get replicated state", "error", err)
+			return
+		}
+		c.JSON(http.StatusOK, state)
+	}
+}
+
+// LivenessHandler shows the signed batches window of the node given by
+// ?address=, or of every tracked node
+func (registry *Registry) LivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		addressStr := c.Query("address")
+		if len(addressStr) == 0 {
+			c.JSON(http.StatusOK, registry.livenessService.ListLiveness())
+			return
+		}
+		if !common.IsHexAddress(addressStr) {
+			c.String(http.StatusBadRequest, "wrong format address")
+			return
+		}
+		liveness, found := registry.livenessService.Liveness(common.HexToAddress(addressStr))
+		if !found {
+			c.String(http.StatusNotFound, "node is not tracked")
+			return
+		}
+		c.JSON(http.StatusOK, liveness)
+	}
+}
+
+// SessionsHandler lists the recent keygen, reshare and sign sessions,
+// ?kind= filters them and ?limit= caps the number returned
+func (registry *Registry) SessionsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kind := c.Query("kind")
+		switch kind {
+		case "", types.SessionKeygen, types.SessionReshare, types.SessionSign:
+		default:
+			c.String(http.StatusBadRequest, "invalid kind %s, expected kind: keygen, reshare and sign", kind)
+			return
+		}
+		var limit int
+		if limitStr := c.Query("limit"); len(limitStr) != 0 {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit < 0 {
+				c.String(http.StatusBadRequest, "wrong format limit")
+				return
+			}
+		}
+		c.JSON(http.StatusOK, registry.sessionService.Sessions(kind, limit))
+	}
+}
+
+// StateBatchHandler shows the stored state batch given by ?index= or ?root=
+func (registry *Registry) StateBatchHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var root [32]byte
+		if indexStr := c.Query("index"); len(indexStr) != 0 {
+			batchIndex, err := strconv.ParseUint(indexStr, 10, 64)
+			if err != nil {
+				c.String(http.StatusBadRequest, "wrong format index")
+				return
+			}
+			var found bool
+			if found, root = registry.adminService.GetIndexStateBatch(batchIndex); !found {
+				c.String(http.StatusNotFound, "state batch is not indexed")
+				return
+			}
+		} else if rootStr := c.Query("root"); len(rootStr) != 0 {
+			rootBz, err := hexutil.Decode(rootStr)
+			if err != nil || len(rootBz) != 32 {
+				c.String(http.StatusBadRequest, "wrong format root")
+				return
+			}
+			copy(root[:], rootBz)
+		} else {
+			c.String(http.StatusBadRequest, "empty index and root")
+			return
+		}
+		found, stateBatch := registry.adminService.GetStateBatch(root)
+		if !found {
+			c.String(http.StatusNotFound, "state batch not found")
+			return
+		}
+		c.JSON(http.StatusOK, stateBatch)
+	}
+}
+
+// TxBatchHandler shows the tx batch attestation starting at the block given
+// by ?start=
+func (registry *Registry) TxBatchHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startBlock, err := strconv.ParseUint(c.Query("start"), 10, 64)
+		if err != nil {
+			c.String(http.StatusBadRequest, "wrong format start")
+			return
+		}
+		found, txBatch := registry.adminService.GetTxBatch(startBlock)
+		if !found {
+			c.String(http.StatusNotFound, "tx batch not found")
+			return
+		}
+		c.JSON(http.StatusOK, txBatch)
+	}
+}
+
+// SigningInfoHandler shows the signing info of the node given by ?address=,
+// or of every node
+func (registry *Registry) SigningInfoHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		addressStr := c.Query("address")
+		if len(addressStr) == 0 {
+			c.JSON(http.StatusOK, registry.adminService.ListSigningInfo())
+			return
+		}
+		if !common.IsHexAddress(addressStr) {
+			c.String(http.StatusBadRequest, "wrong format address")
+			return
+		}
+		found, signingInfo := registry.adminService.GetSigningInfo(common.HexToAddress(addressStr))
+		if !found {
+			c.String(http.StatusNotFound, "signing info not found")
+			return
+		}
+		c.JSON(http.StatusOK, signingInfo)
+	}
+}
+
+// SlashingInfoHandler lists the pending slashing and the culprits waiting to be slashed
+func (registry *Registry) SlashingInfoHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"slashing": registry.adminService.ListSlashingInfo(),
+			"culprits": registry.adminService.GetCulprits(),
+		})
+	}
+}
+
+func (registry *Registry) AliveNodesHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, registry.sessionService.AliveNodes())
+	}
+}
+
 func (registry *Registry) PrometheusHandler() gin.HandlerFunc {
 	h := promhttp.InstrumentMetricHandler(
 		prometheus.DefaultRegisterer, promhttp.HandlerFor(
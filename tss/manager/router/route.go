@@ -13,10 +13,21 @@ func (registry *Registry) Register(r *gin.Engine) {
 
 	v1Router := r.Group("/api/v1")
 	v1Router.POST("/sign/state", registry.SignStateHandler())
+	v1Router.POST("/sign/txbatch", registry.SignTxBatchHandler())
 	v1Router.GET("/metrics", registry.PrometheusHandler())
 
 	v1Router.GET("/admin/height", registry.GetHeightHandler())
 	v1Router.POST("/admin/reset/height", registry.ResetHeightHandler())
 	v1Router.DELETE("/admin/delete/slash", registry.DeleteSlashHandler())
+	v1Router.GET("/admin/liveness", registry.LivenessHandler())
+	v1Router.GET("/admin/sessions", registry.SessionsHandler())
+	v1Router.GET("/admin/state/batch", registry.StateBatchHandler())
+	v1Router.GET("/admin/tx/batch", registry.TxBatchHandler())
+	v1Router.GET("/admin/signing/info", registry.SigningInfoHandler())
+	v1Router.GET("/admin/slashing/info", registry.SlashingInfoHandler())
+	v1Router.GET("/admin/alive/nodes", registry.AliveNodesHandler())
+
+	v1Router.GET("/ha/leader", registry.LeaderHandler())
+	v1Router.GET("/ha/state", registry.ReplicatedStateHandler())
 
 }
@@ -0,0 +1,43 @@
+package manager
+
+import (
+	"time"
+
+	"github.com/mantlenetworkio/mantle/l2geth/log"
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
+)
+
+func (m *Manager) recordSession(kind, method, requestId string, electionId uint64, participants []string, start time.Time, culprits *[]string, err *error) {
+	session := types.Session{
+		RequestId:    requestId,
+		Kind:         kind,
+		Method:       method,
+		ElectionId:   electionId,
+		Participants: participants,
+		StartTime:    start,
+		DurationMs:   time.Since(start).Milliseconds(),
+		Success:      *err == nil,
+	}
+	if culprits != nil {
+		session.Culprits = *culprits
+	}
+	if *err != nil {
+		session.Error = (*err).Error()
+	}
+	if err := m.store.AddSession(session); err != nil {
+		log.Error("failed to store session", "request_id", requestId, "err", err)
+	}
+}
+
+func (m *Manager) Sessions(kind string, limit int) []types.Session {
+	sessions, err := m.store.ListSessions(kind, limit)
+	if err != nil {
+		log.Error("failed to list sessions", "kind", kind, "err", err)
+		return make([]types.Session, 0)
+	}
+	return sessions
+}
+
+func (m *Manager) AliveNodes() []string {
+	return m.wsServer.AliveNodes()
+}
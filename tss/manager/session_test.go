@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mantlenetworkio/mantle/tss/manager/store"
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
+)
+
+func TestSessionLog(t *testing.T) {
+	storage, err := store.NewStorage("")
+	require.NoError(t, err)
+	manager := Manager{store: storage}
+	err = nil
+	manager.recordSession(types.SessionKeygen, "", "1", 1, []string{"a", "b"}, time.Now(), nil, &err)
+	culprits := []string{"b"}
+	err = errors.New("failed to generate signature")
+	manager.recordSession(types.SessionSign, "askSlash", "2", 1, []string{"a", "b"}, time.Now(), &culprits, &err)
+
+	sessions := manager.Sessions("", 0)
+	require.Len(t, sessions, 2)
+	require.EqualValues(t, "2", sessions[0].RequestId)
+	require.False(t, sessions[0].Success)
+	require.EqualValues(t, culprits, sessions[0].Culprits)
+	require.EqualValues(t, "failed to generate signature", sessions[0].Error)
+	require.True(t, sessions[1].Success)
+
+	sessions = manager.Sessions(types.SessionKeygen, 0)
+	require.Len(t, sessions, 1)
+	require.EqualValues(t, "1", sessions[0].RequestId)
+
+	err = nil
+	for i := 0; i < store.MaxSessions+10; i++ {
+		manager.recordSession(types.SessionSign, "", "", 2, nil, time.Now(), nil, &err)
+	}
+	require.Len(t, manager.Sessions("", 0), store.MaxSessions)
+	require.Len(t, manager.Sessions(types.SessionKeygen, 0), 0)
+	require.Len(t, manager.Sessions(types.SessionSign, 5), 5)
+}
+
+func TestSessionLogPersisted(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := store.NewStorage(dir)
+	require.NoError(t, err)
+	manager := Manager{store: storage}
+	for _, requestId := range []string{"1", "2", "3"} {
+		manager.recordSession(types.SessionSign, "", requestId, 1, nil, time.Now(), nil, &err)
+	}
+	require.NoError(t, storage.Close())
+
+	storage, err = store.NewStorage(dir)
+	require.NoError(t, err)
+	defer storage.Close()
+	manager = Manager{store: storage}
+	manager.recordSession(types.SessionKeygen, "", "4", 2, nil, time.Now(), nil, &err)
+
+	sessions := manager.Sessions("", 0)
+	require.Len(t, sessions, 4)
+	for i, requestId := range []string{"4", "3", "2", "1"} {
+		require.Equal(t, requestId, sessions[i].RequestId)
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"time"
 
 	tss "github.com/mantlenetworkio/mantle/tss/common"
+	"github.com/mantlenetworkio/mantle/tss/manager/metics"
 	"github.com/mantlenetworkio/mantle/tss/manager/store"
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
 	"github.com/mantlenetworkio/mantle/tss/ws/server"
 )
 
+// testMetrics is shared by every test manager, the gauges register once
+var testMetrics = metics.PrometheusMetrics("tssmanager_test")
+
 type afterMsgSendFunc func(server.RequestMsg, chan server.ResponseMsg) error
 type queryAliveNodesFunc func() []string
 
@@ -34,6 +39,26 @@ func (mock *mockWsManager) SendMsg(request server.RequestMsg) error {
 	return mock.afterMsgSent(request, mock.responseCh)
 }
 
+// mockTssQueryService serves the same committee for every election
+type mockTssQueryService struct {
+	info types.TssCommitteeInfo
+}
+
+func (mock mockTssQueryService) QueryActiveInfo() (*types.TssCommitteeInfo, error) {
+	info := mock.info
+	return &info, nil
+}
+
+func (mock mockTssQueryService) QueryInactiveInfo() (*types.TssCommitteeInfo, error) {
+	info := mock.info
+	return &info, nil
+}
+
+func (mock mockTssQueryService) QueryTssGroupMembers() (*types.TssCommitteeInfo, error) {
+	info := mock.info
+	return &info, nil
+}
+
 func setup(afterMsgSent afterMsgSendFunc, queryAliveNodes queryAliveNodesFunc) (*Manager, tss.SignStateRequest) {
 	mock := mockWsManager{
 		afterMsgSent:    afterMsgSent,
@@ -46,10 +71,12 @@ func setup(afterMsgSent afterMsgSendFunc, queryAliveNodes queryAliveNodesFunc) (
 	manager := &Manager{
 		wsServer: &mock,
 		store:    storage,
+		metics:   testMetrics,
 
 		askTimeout:        5 * time.Second,
 		signTimeout:       5 * time.Second,
 		keygenTimeout:     5 * time.Second,
+		reshareTimeout:    5 * time.Second,
 		cpkConfirmTimeout: 5 * time.Second,
 	}
 	request := tss.SignStateRequest{
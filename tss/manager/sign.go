@@ -43,7 +43,8 @@ func (c *Counter) satisfied(minNumber int) []string {
 	return ret
 }
 
-func (m *Manager) sign(ctx types.Context, request interface{}, digestBz []byte, method tss.Method) (tss.SignResponse, []string, error) {
+func (m *Manager) sign(ctx types.Context, request interface{}, digestBz []byte, method tss.Method) (signResp tss.SignResponse, culprits []string, err error) {
+	defer m.recordSession(types.SessionSign, method.String(), ctx.RequestId(), ctx.ElectionId(), ctx.Approvers(), time.Now(), &culprits, &err)
 	respChan := make(chan server.ResponseMsg)
 	stopChan := make(chan struct{})
 
@@ -139,7 +140,6 @@ func (m *Manager) sign(ctx types.Context, request interface{}, digestBz []byte,
 	m.sendToNodes(ctx, request, method, errSendChan)
 	wg.Wait()
 
-	var culprits []string
 	if validSignResponse == nil {
 		culprits = counter.satisfied(ctx.TssInfos().Threshold + 1)
 		return tss.SignResponse{}, culprits, errors.New("failed to generate signature")
@@ -2,7 +2,10 @@ package manager
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"math/big"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -20,7 +23,7 @@ func TestSign(t *testing.T) {
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
 		WithApprovers([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold:     3,
 			ClusterPubKey: publicKey,
 		})
@@ -67,7 +70,7 @@ func TestErrorSend(t *testing.T) {
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
 		WithApprovers([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold:     3,
 			ClusterPubKey: publicKey,
 		})
@@ -91,7 +94,7 @@ func TestWrongSignature(t *testing.T) {
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
 		WithApprovers([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold:     3,
 			ClusterPubKey: publicKey,
 		})
@@ -121,7 +124,7 @@ func TestSignTimeout(t *testing.T) {
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
 		WithApprovers([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 3,
 		})
 	afterMsgSent := func(request server.RequestMsg, respCh chan server.ResponseMsg) error {
@@ -153,7 +156,7 @@ func TestSignTimeout(t *testing.T) {
 		return nil
 	}
 	manager, request = setup(afterMsgSent, nil)
-	ctx = ctx.WithTssInfo(types.TssCommitteeInfo{
+	ctx = ctx.WithTssInfo(&types.TssCommitteeInfo{
 		Threshold:     3,
 		ClusterPubKey: publicKey,
 	})
@@ -170,7 +173,7 @@ func TestCulprits(t *testing.T) {
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
 		WithApprovers([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 3,
 		})
 	afterMsgSent := func(request server.RequestMsg, respCh chan server.ResponseMsg) error {
@@ -200,7 +203,7 @@ func TestCulprits(t *testing.T) {
 	ctx = types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
 		WithApprovers([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold: 2,
 		})
 
@@ -217,7 +220,7 @@ func TestSignSlash(t *testing.T) {
 	ctx := types.NewContext().
 		WithAvailableNodes([]string{"a", "b", "c", "d"}).
 		WithApprovers([]string{"a", "b", "c", "d"}).
-		WithTssInfo(types.TssCommitteeInfo{
+		WithTssInfo(&types.TssCommitteeInfo{
 			Threshold:     3,
 			ClusterPubKey: publicKey,
 		})
@@ -264,6 +267,69 @@ func TestSignSlash(t *testing.T) {
 	require.True(t, cost.Seconds()-manager.signTimeout.Seconds() >= 0)
 }
 
+func TestSignTxBatch(t *testing.T) {
+	priK, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	pubKey := btcec.PublicKey(priK.PublicKey)
+	request := tss.SignTxBatchRequest{
+		StartBlock: big.NewInt(10),
+		TxRoots:    [][32]byte{{1}, {2}},
+	}
+	digest, err := tss.TxBatchHash(request.TxRoots, request.StartBlock)
+	require.NoError(t, err)
+	signature, err := crypto.Sign(digest, priK)
+	require.NoError(t, err)
+
+	var signRequests int32
+	afterMsgSent := func(request server.RequestMsg, respCh chan server.ResponseMsg) error {
+		var result interface{} = tss.AskResponse{Result: true}
+		if request.RpcRequest.Method == tss.SignTxBatch.String() {
+			atomic.AddInt32(&signRequests, 1)
+			result = tss.SignResponse{Signature: signature}
+		}
+		respCh <- server.ResponseMsg{
+			RpcResponse: tmtypes.NewRPCSuccessResponse(request.RpcRequest.ID, result),
+			SourceNode:  request.TargetNode,
+		}
+		return nil
+	}
+	nodes := []string{"a", "b", "c"}
+	manager, _ := setup(afterMsgSent, func() []string { return nodes })
+	manager.tssQueryService = mockTssQueryService{info: types.TssCommitteeInfo{
+		ElectionId:    3,
+		ClusterPubKey: hex.EncodeToString(pubKey.SerializeCompressed()),
+		TssMembers:    nodes,
+		Threshold:     1,
+	}}
+
+	respBz, err := manager.SignTxBatch(request)
+	require.NoError(t, err)
+	var resp tss.BatchSubmitterResponse
+	require.NoError(t, json.Unmarshal(respBz, &resp))
+	require.EqualValues(t, signature, resp.Signature)
+	require.EqualValues(t, len(nodes), atomic.LoadInt32(&signRequests))
+
+	found, txBatch := manager.store.GetTxBatch(10)
+	require.True(t, found)
+	require.EqualValues(t, 12, txBatch.EndBlock)
+	require.EqualValues(t, 3, txBatch.ElectionId)
+	require.EqualValues(t, digest, txBatch.BatchHash[:])
+	require.EqualValues(t, signature, txBatch.Signature)
+
+	// the stored attestation is served without another signing round
+	cachedBz, err := manager.SignTxBatch(request)
+	require.NoError(t, err)
+	require.Equal(t, respBz, cachedBz)
+	require.EqualValues(t, len(nodes), atomic.LoadInt32(&signRequests))
+
+	// other transactions from the same start block get a signing round of
+	// their own, the mocked signature does not match them
+	request.TxRoots = [][32]byte{{1}, {3}}
+	_, err = manager.SignTxBatch(request)
+	require.Error(t, err)
+	require.EqualValues(t, 2*len(nodes), atomic.LoadInt32(&signRequests))
+}
+
 func mockSign() (digest []byte, signature []byte, compressedPublicKey string) {
 	priK, err := crypto.GenerateKey()
 	if err != nil {
@@ -39,8 +39,10 @@ func (m *Manager) slashing() {
 	for {
 		signingInfos := m.store.ListSlashingInfo()
 		m.metics.SlashCount.Set(float64(len(signingInfos)))
-		for _, si := range signingInfos {
-			m.handleSlashing(si)
+		if m.isLeader() {
+			for _, si := range signingInfos {
+				m.handleSlashing(si)
+			}
 		}
 		select {
 		case <-m.stopChan:
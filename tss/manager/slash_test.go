@@ -5,8 +5,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	
+
 	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
 	tss "github.com/mantlenetworkio/mantle/tss/common"
 	"github.com/mantlenetworkio/mantle/tss/index"
@@ -14,128 +15,195 @@ import (
 	"github.com/mantlenetworkio/mantle/tss/slash"
 )
 
+func indexBatch(t *testing.T, storage *store.Storage, slashing slash.Slashing, batchIndex, electionId uint64, node string, absent bool) {
+	stateBatch := index.StateBatchInfo{
+		BatchRoot:  [32]byte{byte(batchIndex)},
+		ElectionId: electionId,
+		BatchIndex: batchIndex,
+	}
+	if absent {
+		stateBatch.AbsentNodes = []string{node}
+	} else {
+		stateBatch.WorkingNodes = []string{node}
+	}
+	require.NoError(t, storage.SetStateBatch(stateBatch))
+	require.NoError(t, storage.IndexStateBatch(batchIndex, stateBatch.BatchRoot))
+	require.NoError(t, slashing.AfterStateBatchIndexed(stateBatch.BatchRoot))
+}
+
 func TestLivenessDetect(t *testing.T) {
 	storage, err := store.NewStorage("")
 	require.NoError(t, err)
 
-	slashing := slash.NewSlashing(storage, storage, 10)
+	// at least 5 of the last 10 batches have to be signed
+	slashing := slash.NewSlashing(storage, storage, slash.Params{
+		SignedBatchesWindow: 10,
+		MinSignedPerWindow:  0.5,
+		JailDuration:        3,
+	})
 	priK, err := crypto.GenerateKey()
+	require.NoError(t, err)
 	nodePublicKey := hexutil.Encode(crypto.CompressPubkey(&priK.PublicKey))
 	address := crypto.PubkeyToAddress(priK.PublicKey)
-	// index: 0 -> 5
-	for i := 0; i < 6; i++ {
-		err = storage.SetStateBatch(index.StateBatchInfo{
-			BatchRoot:   [32]byte{byte(i)},
-			ElectionId:  1,
-			AbsentNodes: []string{nodePublicKey},
-			BatchIndex:  uint64(i),
-		})
-		require.NoError(t, err)
-		err = storage.IndexStateBatch(uint64(i), [32]byte{byte(i)})
-		require.NoError(t, err)
-		err = slashing.AfterStateBatchIndexed([32]byte{byte(i)})
-		require.NoError(t, err)
 
-		if i == 5 {
-			found, slashingInfo := storage.GetSlashingInfo(address, uint64(i))
-			require.True(t, found)
-			require.EqualValues(t, address, slashingInfo.Address)
-			require.EqualValues(t, i, slashingInfo.BatchIndex)
-			require.EqualValues(t, 1, slashingInfo.ElectionId)
-			require.EqualValues(t, tss.SlashTypeLiveness, slashingInfo.SlashType)
-
-			storage.RemoveSlashingInfo(address, uint64(i))
-		} else {
-			found, _ := storage.GetSlashingInfo(address, uint64(i))
-			require.False(t, found)
-		}
+	// index: 0 -> 8, the window is not full yet
+	for i := uint64(0); i <= 8; i++ {
+		indexBatch(t, storage, slashing, i, 1, nodePublicKey, true)
+		found, _ := storage.GetSlashingInfo(address, i)
+		require.False(t, found)
 	}
+	liveness, found := slashing.Liveness(address)
+	require.True(t, found)
+	require.EqualValues(t, 9, liveness.MissedBlocksCounter)
+	require.EqualValues(t, 5, liveness.MaxMissed)
+	require.EqualValues(t, 0, liveness.RemainingMisses)
+	require.Len(t, liveness.Missed, 9)
+
+	// index: 9, the full window is judged
+	indexBatch(t, storage, slashing, 9, 1, nodePublicKey, true)
+	found, slashingInfo := storage.GetSlashingInfo(address, 9)
+	require.True(t, found)
+	require.EqualValues(t, address, slashingInfo.Address)
+	require.EqualValues(t, 1, slashingInfo.ElectionId)
+	require.EqualValues(t, tss.SlashTypeLiveness, slashingInfo.SlashType)
+	storage.RemoveSlashingInfo(address, 9)
 
 	found, signingInfo := storage.GetSigningInfo(address)
 	require.True(t, found)
-	require.EqualValues(t, 6, signingInfo.MissedBlocksCounter)
-
-	// index: 6
-	err = storage.SetStateBatch(index.StateBatchInfo{
-		BatchRoot:   [32]byte{byte(6)},
-		ElectionId:  2,
-		AbsentNodes: []string{nodePublicKey},
-		BatchIndex:  uint64(6),
-	})
-	require.NoError(t, err)
-	err = slashing.AfterStateBatchIndexed([32]byte{byte(6)})
-	require.NoError(t, err)
+	require.EqualValues(t, 0, signingInfo.MissedBlocksCounter)
+	require.EqualValues(t, 13, signingInfo.JailedUntil)
 
+	// index: 10 -> 12, jailed
+	for i := uint64(10); i <= 12; i++ {
+		indexBatch(t, storage, slashing, i, 1, nodePublicKey, true)
+		found, _ := storage.GetSlashingInfo(address, i)
+		require.False(t, found)
+	}
 	found, signingInfo = storage.GetSigningInfo(address)
 	require.True(t, found)
-	require.EqualValues(t, 1, signingInfo.MissedBlocksCounter)
-
-	// index: 7-15, absent from 7-10
-	for i := 7; i <= 15; i++ {
-		if i <= 10 {
-			err = storage.SetStateBatch(index.StateBatchInfo{
-				BatchRoot:   [32]byte{byte(i)},
-				ElectionId:  2,
-				AbsentNodes: []string{nodePublicKey},
-				BatchIndex:  uint64(i),
-			})
-		} else {
-			err = storage.SetStateBatch(index.StateBatchInfo{
-				BatchRoot:    [32]byte{byte(i)},
-				ElectionId:   2,
-				WorkingNodes: []string{nodePublicKey},
-				BatchIndex:   uint64(i),
-			})
-		}
-		require.NoError(t, err)
-		err = storage.IndexStateBatch(uint64(i), [32]byte{byte(i)})
-		require.NoError(t, err)
-		err = slashing.AfterStateBatchIndexed([32]byte{byte(i)})
-		require.NoError(t, err)
+	require.EqualValues(t, 0, signingInfo.IndexOffset)
+
+	// index: 13 -> 22, a new election, absent from 13 -> 17
+	for i := uint64(13); i <= 22; i++ {
+		indexBatch(t, storage, slashing, i, 2, nodePublicKey, i <= 17)
 	}
 	found, signingInfo = storage.GetSigningInfo(address)
 	require.True(t, found)
+	require.EqualValues(t, 2, signingInfo.ElectionId)
 	require.EqualValues(t, 5, signingInfo.MissedBlocksCounter)
 	require.False(t, storage.IsInSlashing(address))
 
-	// index: 16-20
-	for i := 16; i <= 20; i++ {
-		err = storage.SetStateBatch(index.StateBatchInfo{
-			BatchRoot:   [32]byte{byte(i)},
-			ElectionId:  2,
-			AbsentNodes: []string{nodePublicKey},
-			BatchIndex:  uint64(i),
-		})
-		require.NoError(t, err)
-		err = storage.IndexStateBatch(uint64(i), [32]byte{byte(i)})
-		require.NoError(t, err)
-		err = slashing.AfterStateBatchIndexed([32]byte{byte(i)})
-		require.NoError(t, err)
+	// index: 23 -> 27, the misses of 13 -> 17 slide out of the window
+	for i := uint64(23); i <= 27; i++ {
+		indexBatch(t, storage, slashing, i, 2, nodePublicKey, true)
 	}
 	found, signingInfo = storage.GetSigningInfo(address)
 	require.True(t, found)
-
 	require.EqualValues(t, 5, signingInfo.MissedBlocksCounter)
 	require.False(t, storage.IsInSlashing(address))
 
-	// index: 21
-	err = storage.SetStateBatch(index.StateBatchInfo{
-		BatchRoot:   [32]byte{byte(21)},
-		ElectionId:  2,
-		AbsentNodes: []string{nodePublicKey},
-		BatchIndex:  uint64(21),
-	})
+	livenesses := slashing.ListLiveness()
+	require.Len(t, livenesses, 1)
+	require.Equal(t, []bool{false, false, false, false, false, true, true, true, true, true}, livenesses[0].Missed)
+
+	// index: 28, the signed batch 18 slides out of the window
+	indexBatch(t, storage, slashing, 28, 2, nodePublicKey, true)
+	require.True(t, storage.IsInSlashing(address))
+	found, slashingInfo = storage.GetSlashingInfo(address, 28)
+	require.True(t, found)
+	require.EqualValues(t, 2, slashingInfo.ElectionId)
+	require.EqualValues(t, tss.SlashTypeLiveness, slashingInfo.SlashType)
+}
+
+func TestLivenessJailAcrossElection(t *testing.T) {
+	storage, err := store.NewStorage("")
 	require.NoError(t, err)
-	err = storage.IndexStateBatch(uint64(21), [32]byte{byte(21)})
+
+	slashing := slash.NewSlashing(storage, storage, slash.Params{
+		SignedBatchesWindow: 2,
+		MinSignedPerWindow:  1,
+		JailDuration:        5,
+	})
+	priK, err := crypto.GenerateKey()
 	require.NoError(t, err)
-	err = slashing.AfterStateBatchIndexed([32]byte{byte(21)})
+	nodePublicKey := hexutil.Encode(crypto.CompressPubkey(&priK.PublicKey))
+	address := crypto.PubkeyToAddress(priK.PublicKey)
+
+	// index: 0 -> 1, slashed at 1 and jailed until 7
+	indexBatch(t, storage, slashing, 0, 1, nodePublicKey, true)
+	indexBatch(t, storage, slashing, 1, 1, nodePublicKey, true)
+	require.True(t, storage.IsInSlashing(address))
+	storage.RemoveSlashingInfo(address, 1)
+
+	// index: 2 -> 6, a new election does not release the node
+	for i := uint64(2); i <= 6; i++ {
+		indexBatch(t, storage, slashing, i, 2, nodePublicKey, true)
+		found, _ := storage.GetSlashingInfo(address, i)
+		require.False(t, found)
+	}
+	found, signingInfo := storage.GetSigningInfo(address)
+	require.True(t, found)
+	require.EqualValues(t, 2, signingInfo.ElectionId)
+	require.EqualValues(t, 7, signingInfo.JailedUntil)
+	require.EqualValues(t, 7, signingInfo.StartBatchIndex)
+	require.EqualValues(t, 0, signingInfo.IndexOffset)
+
+	// index: 7 -> 8, tracked again and slashed for the new downtime
+	indexBatch(t, storage, slashing, 7, 2, nodePublicKey, true)
+	indexBatch(t, storage, slashing, 8, 2, nodePublicKey, true)
+	found, slashingInfo := storage.GetSlashingInfo(address, 8)
+	require.True(t, found)
+	require.EqualValues(t, 2, slashingInfo.ElectionId)
+}
+
+func TestLivenessWindowAcrossElection(t *testing.T) {
+	storage, err := store.NewStorage("")
 	require.NoError(t, err)
 
-	found, signingInfo = storage.GetSigningInfo(address)
+	slashing := slash.NewSlashing(storage, storage, slash.Params{
+		SignedBatchesWindow: 4,
+		MinSignedPerWindow:  0.5,
+		JailDuration:        5,
+	})
+	newNode := func() (string, common.Address) {
+		priK, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		return hexutil.Encode(crypto.CompressPubkey(&priK.PublicKey)), crypto.PubkeyToAddress(priK.PublicKey)
+	}
+	member, memberAddress := newNode()
+	rejoined, rejoinedAddress := newNode()
+
+	// index: 0 -> 1, both nodes miss the batches of election 1
+	for i := uint64(0); i <= 1; i++ {
+		indexBatch(t, storage, slashing, i, 1, member, true)
+		indexBatch(t, storage, slashing, i, 1, rejoined, true)
+	}
+
+	// index: 2 -> 3, the member keeps its missed batches in election 2 and is
+	// slashed once its window is full
+	indexBatch(t, storage, slashing, 2, 2, member, true)
+	found, signingInfo := storage.GetSigningInfo(memberAddress)
 	require.True(t, found)
-	require.EqualValues(t, 6, signingInfo.MissedBlocksCounter)
-	require.True(t, storage.IsInSlashing(address))
-	found, slashingInfo := storage.GetSlashingInfo(address, 21)
+	require.EqualValues(t, 2, signingInfo.ElectionId)
+	require.EqualValues(t, 0, signingInfo.StartBatchIndex)
+	require.EqualValues(t, 3, signingInfo.IndexOffset)
+	require.EqualValues(t, 3, signingInfo.MissedBlocksCounter)
+	indexBatch(t, storage, slashing, 3, 2, member, false)
+	found, slashingInfo := storage.GetSlashingInfo(memberAddress, 3)
 	require.True(t, found)
-	require.EqualValues(t, tss.SlashTypeLiveness, slashingInfo.SlashType)
+	require.EqualValues(t, 2, slashingInfo.ElectionId)
+	_, signingInfo = storage.GetSigningInfo(memberAddress)
+	require.EqualValues(t, 0, signingInfo.MissedBlocksCounter)
+	require.EqualValues(t, 9, signingInfo.JailedUntil)
+
+	// the other node left the committee for batch 2 and starts over when it
+	// joins again at batch 3
+	indexBatch(t, storage, slashing, 3, 2, rejoined, false)
+	found, signingInfo = storage.GetSigningInfo(rejoinedAddress)
+	require.True(t, found)
+	require.EqualValues(t, 2, signingInfo.ElectionId)
+	require.EqualValues(t, 3, signingInfo.StartBatchIndex)
+	require.EqualValues(t, 1, signingInfo.IndexOffset)
+	require.EqualValues(t, 0, signingInfo.MissedBlocksCounter)
+	require.False(t, storage.IsInSlashing(rejoinedAddress))
 }
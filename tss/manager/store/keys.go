@@ -15,6 +15,8 @@ var (
 	SlashingInfoKeyPrefix            = []byte{0x06}
 	ScannedHeightKeyPrefix           = []byte{0x07}
 	CulpritsKeyPrefix                = []byte{0x08}
+	SessionKeyPrefix                 = []byte{0x09}
+	TxBatchKeyPrefix                 = []byte{0x0a}
 )
 
 func getCPKDataKey(electionId uint64) []byte {
@@ -66,3 +68,16 @@ func getScannedHeightKey() []byte {
 func getCulpritsKey() []byte {
 	return CulpritsKeyPrefix
 }
+
+// key: prefix + sequence number
+func getSessionKey(seq uint64) []byte {
+	seqBz := make([]byte, 8)
+	binary.BigEndian.PutUint64(seqBz, seq)
+	return append(SessionKeyPrefix, seqBz...)
+}
+
+func getTxBatchKey(startBlock uint64) []byte {
+	startBlockBz := make([]byte, 8)
+	binary.BigEndian.PutUint64(startBlockBz, startBlock)
+	return append(TxBatchKeyPrefix, startBlockBz...)
+}
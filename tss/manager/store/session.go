@@ -0,0 +1,65 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
+)
+
+// MaxSessions is the number of the most recent sessions the store keeps
+const MaxSessions = 256
+
+// AddSession stores the session after the previous ones and drops the oldest
+// sessions beyond MaxSessions
+func (s *Storage) AddSession(session types.Session) error {
+	bz, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	s.sessionLock.Lock()
+	defer s.sessionLock.Unlock()
+
+	var keys [][]byte
+	iterator := s.db.NewIterator(util.BytesPrefix(SessionKeyPrefix), nil)
+	for iterator.Next() {
+		keys = append(keys, append([]byte{}, iterator.Key()...))
+	}
+	iterator.Release()
+	if err := iterator.Error(); err != nil {
+		return err
+	}
+
+	var seq uint64
+	if len(keys) > 0 {
+		seq = binary.BigEndian.Uint64(keys[len(keys)-1][len(SessionKeyPrefix):]) + 1
+	}
+	batch := new(leveldb.Batch)
+	batch.Put(getSessionKey(seq), bz)
+	for i := 0; i <= len(keys)-MaxSessions; i++ {
+		batch.Delete(keys[i])
+	}
+	return s.db.Write(batch, nil)
+}
+
+func (s *Storage) ListSessions(kind string, limit int) ([]types.Session, error) {
+	sessions := make([]types.Session, 0)
+	iterator := s.db.NewIterator(util.BytesPrefix(SessionKeyPrefix), nil)
+	defer iterator.Release()
+	for ok := iterator.Last(); ok; ok = iterator.Prev() {
+		if limit > 0 && len(sessions) == limit {
+			break
+		}
+		var session types.Session
+		if err := json.Unmarshal(iterator.Value(), &session); err != nil {
+			return nil, err
+		}
+		if len(kind) == 0 || session.Kind == kind {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, iterator.Error()
+}
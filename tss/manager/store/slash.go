@@ -32,6 +32,19 @@ func (s *Storage) GetSigningInfo(address common.Address) (bool, slash.SigningInf
 	return true, signingInfo
 }
 
+func (s *Storage) ListSigningInfo() (signingInfos []slash.SigningInfo) {
+	iterator := s.db.NewIterator(util.BytesPrefix(SigningInfoKeyPrefix), nil)
+	defer iterator.Release()
+	for iterator.Next() {
+		var signingInfo slash.SigningInfo
+		if err := json.Unmarshal(iterator.Value(), &signingInfo); err != nil {
+			panic(err)
+		}
+		signingInfos = append(signingInfos, signingInfo)
+	}
+	return
+}
+
 func (s *Storage) GetNodeMissedBatchBitArray(address common.Address, index uint64) bool {
 	bz, err := s.db.Get(getNodeMissedBatchBitArrayKey(address, index), nil)
 	if err != nil {
@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/storage"
@@ -9,6 +10,9 @@ import (
 
 type Storage struct {
 	db *leveldb.DB
+
+	// sessionLock serializes the sequence numbers of the sessions
+	sessionLock sync.Mutex
 }
 
 func NewStorage(levelDbFolder string) (*Storage, error) {
@@ -0,0 +1,27 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/mantlenetworkio/mantle/tss/manager/types"
+)
+
+func (s *Storage) SetTxBatch(info types.TxBatchInfo) error {
+	bz, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(getTxBatchKey(info.StartBlock), bz, nil)
+}
+
+func (s *Storage) GetTxBatch(startBlock uint64) (bool, types.TxBatchInfo) {
+	bz, err := s.db.Get(getTxBatchKey(startBlock), nil)
+	if err != nil {
+		return handleError2(types.TxBatchInfo{}, err)
+	}
+	var tbi types.TxBatchInfo
+	if err = json.Unmarshal(bz, &tbi); err != nil {
+		return false, types.TxBatchInfo{}
+	}
+	return true, tbi
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
 
 	tss "github.com/mantlenetworkio/mantle/tss/common"
@@ -11,13 +13,43 @@ import (
 type SignService interface {
 	SignStateBatch(request tss.SignStateRequest) ([]byte, error)
 	SignRollBack(request tss.SignStateRequest) ([]byte, error)
-	SignTxBatch() error
+	SignTxBatch(request tss.SignTxBatchRequest) ([]byte, error)
 }
 
 type AdminService interface {
 	ResetScanHeight(height uint64) error
 	GetScannedHeight() (uint64, error)
 	RemoveSlashingInfo(common.Address, uint64)
+
+	GetStateBatch(root [32]byte) (bool, index.StateBatchInfo)
+	GetIndexStateBatch(index uint64) (bool, [32]byte)
+	GetTxBatch(startBlock uint64) (bool, TxBatchInfo)
+	GetSigningInfo(common.Address) (bool, slash.SigningInfo)
+	ListSigningInfo() []slash.SigningInfo
+	ListSlashingInfo() []slash.SlashingInfo
+	GetCulprits() []string
+}
+
+type SessionService interface {
+	// Sessions returns the recent sessions of the kind newest first, every kind when kind is empty
+	Sessions(kind string, limit int) []Session
+	AliveNodes() []string
+}
+
+// ErrNotLeader is returned by a standby manager for the work only the
+// leader does
+var ErrNotLeader = errors.New("tss manager is not the leader")
+
+type HAService interface {
+	// Leader returns the lease of the current leader manager
+	Leader() (LeaderInfo, error)
+	// ReplicatedState is the state the standby managers copy from the leader
+	ReplicatedState() (ReplicatedState, error)
+}
+
+type LivenessService interface {
+	Liveness(common.Address) (slash.NodeLiveness, bool)
+	ListLiveness() []slash.NodeLiveness
 }
 
 type TssQueryService interface {
@@ -31,8 +63,24 @@ type CPKStore interface {
 	GetByElectionId(uint64) (CpkData, error)
 }
 
+// SessionStore keeps the most recent keygen, reshare and sign sessions
+type SessionStore interface {
+	AddSession(Session) error
+	// ListSessions returns the sessions of the kind newest first, every kind when kind is empty
+	ListSessions(kind string, limit int) ([]Session, error)
+}
+
+// TxBatchStore keeps the attestation of the last tx batch signed from every
+// start block
+type TxBatchStore interface {
+	SetTxBatch(TxBatchInfo) error
+	GetTxBatch(startBlock uint64) (bool, TxBatchInfo)
+}
+
 type ManagerStore interface {
 	CPKStore
+	SessionStore
+	TxBatchStore
 	index.StateBatchStore
 	index.ScanHeightStore
 	slash.SlashingStore
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/mantlenetworkio/mantle/l2geth/common"
+	"github.com/mantlenetworkio/mantle/tss/index"
+	"github.com/mantlenetworkio/mantle/tss/slash"
 )
 
 type TssCommitteeInfo struct {
@@ -18,6 +20,58 @@ type CpkData struct {
 	Cpk          string    `json:"cpk"`
 	ElectionId   uint64    `json:"election_id"`
 	CreationTime time.Time `json:"creation_time"`
+	// KeyEpoch is the election the shares of Cpk were last dealt at, 0 for a keygen
+	KeyEpoch uint64 `json:"key_epoch"`
+}
+
+const (
+	SessionKeygen  = "keygen"
+	SessionReshare = "reshare"
+	SessionSign    = "sign"
+)
+
+// Session is a keygen, reshare or signing round the manager ran with the nodes
+type Session struct {
+	RequestId    string    `json:"request_id"`
+	Kind         string    `json:"kind"`
+	Method       string    `json:"method,omitempty"`
+	ElectionId   uint64    `json:"election_id"`
+	Participants []string  `json:"participants"`
+	Culprits     []string  `json:"culprits,omitempty"`
+	StartTime    time.Time `json:"start_time"`
+	DurationMs   int64     `json:"duration_ms"`
+	Success      bool      `json:"success"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// TxBatchInfo is the attestation the tss nodes signed for the transactions of
+// the l2 blocks [StartBlock, EndBlock)
+type TxBatchInfo struct {
+	StartBlock uint64   `json:"start_block"`
+	EndBlock   uint64   `json:"end_block"`
+	BatchHash  [32]byte `json:"batch_hash"`
+	Signature  []byte   `json:"signature"`
+	ElectionId uint64   `json:"election_id"`
+}
+
+type LeaderInfo struct {
+	NodeId      string    `json:"node_id"`
+	Address     string    `json:"address"`
+	LeaseExpiry time.Time `json:"lease_expiry"`
+	IsSelf      bool      `json:"is_self"`
+}
+
+type StateSignature struct {
+	Digest    [32]byte `json:"digest"`
+	Signature []byte   `json:"signature"`
+}
+
+// ReplicatedState is the leader state that can not be rebuilt from layer1
+type ReplicatedState struct {
+	StateSignatures []StateSignature       `json:"state_signatures"`
+	StateBatches    []index.StateBatchInfo `json:"state_batches"`
+	SlashingInfos   []slash.SlashingInfo   `json:"slashing_infos"`
+	Culprits        []string               `json:"culprits"`
 }
 
 type TgTssMember struct {
@@ -25,6 +25,7 @@ import (
 	"github.com/mantlenetworkio/mantle/tss/node/tsslib"
 	"github.com/mantlenetworkio/mantle/tss/node/tsslib/common"
 	"github.com/mantlenetworkio/mantle/tss/node/tsslib/conversion"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/storage"
 	"github.com/mantlenetworkio/mantle/tss/slash"
 )
 
@@ -88,6 +89,16 @@ func runNode(cmd *cobra.Command) error {
 		return err
 	}
 
+	pubkey := crypto.CompressPubkey(&privKey.PublicKey)
+	pubkeyHex := hex.EncodeToString(pubkey)
+
+	backend := storage.KeyShareBackend(cfg.Node)
+	keyShareStore, err := storage.NewKeyShareStore(backend, cfg.Node, pubkeyHex)
+	if err != nil {
+		log.Error().Err(err).Msgf("fail to create %s key share store", backend)
+		return err
+	}
+
 	tssInstance, err := tsslib.NewTss(
 		cfg.Node.BootstrapPeers,
 		waitPeersFullConnected,
@@ -95,16 +106,15 @@ func runNode(cmd *cobra.Command) error {
 		privKey,
 		cfg.Node.BaseDir,
 		common.TssConfig{
-			PreParamTimeout: cfg.Node.PreParamTimeout,
-			KeyGenTimeout:   cfg.Node.KeyGenTimeout,
-			KeySignTimeout:  cfg.Node.KeySignTimeout,
-			EnableMonitor:   false,
+			PreParamTimeout:   cfg.Node.PreParamTimeout,
+			KeyGenTimeout:     cfg.Node.KeyGenTimeout,
+			KeySignTimeout:    cfg.Node.KeySignTimeout,
+			KeyReshareTimeout: cfg.Node.KeyReshareTimeout,
+			EnableMonitor:     false,
 		},
 		cfg.Node.PreParamFile,
 		cfg.Node.ExternalIP,
-		cfg.Node.Secrets.Enable,
-		cfg.Node.Secrets.SecretId,
-		cfg.Node.Shamir,
+		keyShareStore,
 		store,
 	)
 	if err != nil {
@@ -116,9 +126,6 @@ func runNode(cmd *cobra.Command) error {
 		return err
 	}
 
-	pubkey := crypto.CompressPubkey(&privKey.PublicKey)
-	pubkeyHex := hex.EncodeToString(pubkey)
-
 	localPubkeyBytes := crypto.FromECDSAPub(&privKey.PublicKey)
 	// bytes len is 64
 	localPubkeyBytes = localPubkeyBytes[1:]
@@ -196,3 +203,52 @@ func PeerIDCommand() *cobra.Command {
 	cmd.Flags().String("pri-key", "", "hex-encoded Ethereum private key")
 	return cmd
 }
+
+func MigrateKeySharesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-key-shares",
+		Short: "copy the key shares of the node from one storage backend to another",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			pubKeys, _ := cmd.Flags().GetStringSlice("pub-keys")
+			cfg := tss.GetConfigFromCmd(cmd)
+
+			if len(from) == 0 {
+				from = storage.KeyShareBackend(cfg.Node)
+			}
+			if len(to) == 0 {
+				return errors.New("need to specify the backend to migrate the key shares to")
+			}
+			if from == to {
+				return errors.New("the source and the destination backend are the same")
+			}
+			if len(cfg.Node.PrivateKey) == 0 {
+				return errors.New("need to config private key")
+			}
+			privKey, err := crypto.HexToECDSA(cfg.Node.PrivateKey)
+			if err != nil {
+				return err
+			}
+			localPartyKey := hex.EncodeToString(crypto.CompressPubkey(&privKey.PublicKey))
+
+			fromStore, err := storage.NewKeyShareStore(from, cfg.Node, localPartyKey)
+			if err != nil {
+				return fmt.Errorf("fail to create %s key share store: %w", from, err)
+			}
+			toStore, err := storage.NewKeyShareStore(to, cfg.Node, localPartyKey)
+			if err != nil {
+				return fmt.Errorf("fail to create %s key share store: %w", to, err)
+			}
+			migrated, err := storage.MigrateKeyShares(fromStore, toStore, pubKeys)
+			for _, pubKey := range migrated {
+				fmt.Printf("migrated key share of %s from %s to %s\n", pubKey, from, to)
+			}
+			return err
+		},
+	}
+	cmd.Flags().String("from", "", "backend to read the key shares from, the configured backend by default")
+	cmd.Flags().String("to", "", "backend to write the key shares to: file, vault_file, vault_kv, aws_secrets or aws_shamir")
+	cmd.Flags().StringSlice("pub-keys", nil, "pool pub keys to migrate, all the shares of the source backend by default")
+	return cmd
+}
@@ -40,6 +40,10 @@ func (p *Processor) ProcessMessage() {
 					if err := p.writeChan(p.keygenRequestChan, rpcReq); err != nil {
 						logger.Err(err).Msg("failed to write msg to keygen channel,channel blocked")
 					}
+				} else if rpcReq.Method == common.Reshare.String() {
+					if err := p.writeChan(p.reshareRequestChan, rpcReq); err != nil {
+						logger.Err(err).Msg("failed to write msg to reshare channel,channel blocked")
+					}
 				} else if rpcReq.Method == common.AskSlash.String() {
 					if err := p.writeChan(p.askSlashChan, rpcReq); err != nil {
 						logger.Err(err).Msg("failed to write msg to ask slash channel,channel blocked")
@@ -56,6 +60,14 @@ func (p *Processor) ProcessMessage() {
 					if err := p.writeChan(p.askRollBackChan, rpcReq); err != nil {
 						logger.Err(err).Msg("failed to write msg to ask roll back channel,channel blocked")
 					}
+				} else if rpcReq.Method == common.AskTxBatch.String() {
+					if err := p.writeChan(p.askTxBatchChan, rpcReq); err != nil {
+						logger.Err(err).Msg("failed to write msg to ask tx batch channel,channel blocked")
+					}
+				} else if rpcReq.Method == common.SignTxBatch.String() {
+					if err := p.writeChan(p.signTxBatchChan, rpcReq); err != nil {
+						logger.Err(err).Msg("failed to write msg to sign tx batch channel,channel blocked")
+					}
 				} else {
 					logger.Error().Msgf("unknown rpc request method : %s ", rpcReq.Method)
 				}
@@ -17,6 +17,17 @@ import (
 // this method will return an error.
 func DialL2EthClientWithTimeout(ctx context.Context, url string, disableHTTP2 bool) (
 	*ethclient.Client, error) {
+	rpcClient, err := DialL2RpcClientWithTimeout(ctx, url, disableHTTP2)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(rpcClient), nil
+}
+
+// DialL2RpcClientWithTimeout is DialL2EthClientWithTimeout for callers that
+// need the raw rpc client, e.g. to batch requests
+func DialL2RpcClientWithTimeout(ctx context.Context, url string, disableHTTP2 bool) (
+	*rpc.Client, error) {
 
 	ctxt, cancel := context.WithTimeout(ctx, dial.DefaultTimeout)
 	defer cancel()
@@ -30,13 +41,8 @@ func DialL2EthClientWithTimeout(ctx context.Context, url string, disableHTTP2 bo
 			}
 		}
 
-		rpcClient, err := rpc.DialHTTPWithClient(url, httpClient)
-		if err != nil {
-			return nil, err
-		}
-
-		return ethclient.NewClient(rpcClient), nil
+		return rpc.DialHTTPWithClient(url, httpClient)
 	}
 
-	return ethclient.DialContext(ctxt, url)
+	return rpc.DialContext(ctxt, url)
 }
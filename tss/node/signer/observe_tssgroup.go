@@ -42,6 +42,7 @@ func (p *Processor) ObserveTssGroup() {
 				}
 			}
 		}()
+		p.activateKeyShare()
 
 		select {
 		case <-p.stopChan:
@@ -50,3 +51,16 @@ func (p *Processor) ObserveTssGroup() {
 		}
 	}
 }
+
+// activateKeyShare switches to the key share a reshare dealt to the active
+// committee, the old share is kept until the committee is active on L1
+func (p *Processor) activateKeyShare() {
+	tssInfo, err := p.tssQueryService.QueryActiveInfo()
+	if err != nil {
+		log.Error("failed to query active info", "err", err)
+		return
+	}
+	if _, err := p.tssServer.ActivateKeyShare(tssInfo.ClusterPubKey, tssInfo.ElectionId); err != nil {
+		log.Warn("failed to activate key share", "election id", tssInfo.ElectionId, "err", err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/mantlenetworkio/mantle/bss-core/dial"
 	l2ethclient "github.com/mantlenetworkio/mantle/l2geth/ethclient"
+	"github.com/mantlenetworkio/mantle/l2geth/rpc"
 	"github.com/mantlenetworkio/mantle/tss/bindings/tgm"
 	"github.com/mantlenetworkio/mantle/tss/bindings/tsh"
 	"github.com/mantlenetworkio/mantle/tss/common"
@@ -35,6 +36,7 @@ type Processor struct {
 	tssServer                 tsslib.Server
 	wsClient                  *client.WSClients
 	l2Client                  *l2ethclient.Client
+	l2RpcClient               *rpc.Client
 	l1Client                  *ethclient.Client
 	ctx                       context.Context
 	cancel                    func()
@@ -45,14 +47,17 @@ type Processor struct {
 	askSlashChan              chan tdtypes.RPCRequest
 	signSlashChan             chan tdtypes.RPCRequest
 	keygenRequestChan         chan tdtypes.RPCRequest
+	reshareRequestChan        chan tdtypes.RPCRequest
 	askRollBackChan           chan tdtypes.RPCRequest
 	signRollBackChan          chan tdtypes.RPCRequest
+	askTxBatchChan            chan tdtypes.RPCRequest
+	signTxBatchChan           chan tdtypes.RPCRequest
 	waitSignLock              *sync.RWMutex
 	waitSignMsgs              map[string]common.SignStateRequest
 	waitSignSlashLock         *sync.RWMutex
 	waitSignSlashMsgs         map[string]map[uint64]common.SlashRequest
 	cacheVerifyLock           *sync.RWMutex
-	cacheVerify               *types.Cache[string, bool]
+	cacheVerify               *types.Cache[uint64, *rpcHeader]
 	cacheSignLock             *sync.RWMutex
 	cacheSign                 *types.Cache[string, []byte]
 	nodeStore                 types.NodeStore
@@ -95,7 +100,11 @@ func NewProcessor(cfg common.Configuration, contx context.Context, tssInstance t
 	if err != nil {
 		return nil, err
 	}
-	l2Client, err := DialL2EthClientWithTimeout(ctx, cfg.Node.L2EthRpc, cfg.Node.DisableHTTP2)
+	l2RpcClient, err := DialL2RpcClientWithTimeout(ctx, cfg.Node.L2EthRpc, cfg.Node.DisableHTTP2)
+	if err != nil {
+		return nil, err
+	}
+	l2Client := l2ethclient.NewClient(l2RpcClient)
 	tssStakingSlashingCaller, err := tsh.NewTssStakingSlashingCaller(ethc.HexToAddress(cfg.TssStakingSlashContractAddress), l1Cli)
 	if err != nil {
 		return nil, err
@@ -122,6 +131,7 @@ func NewProcessor(cfg common.Configuration, contx context.Context, tssInstance t
 		logger:                    log.With().Str("module", "signer").Logger(),
 		wsClient:                  wsClient,
 		l2Client:                  l2Client,
+		l2RpcClient:               l2RpcClient,
 		l1Client:                  l1Cli,
 		ctx:                       ctx,
 		cancel:                    cancel,
@@ -130,14 +140,17 @@ func NewProcessor(cfg common.Configuration, contx context.Context, tssInstance t
 		askSlashChan:              make(chan tdtypes.RPCRequest, 1),
 		signSlashChan:             make(chan tdtypes.RPCRequest, 1),
 		keygenRequestChan:         make(chan tdtypes.RPCRequest, 1),
+		reshareRequestChan:        make(chan tdtypes.RPCRequest, 1),
 		askRollBackChan:           make(chan tdtypes.RPCRequest, 1),
 		signRollBackChan:          make(chan tdtypes.RPCRequest, 1),
+		askTxBatchChan:            make(chan tdtypes.RPCRequest, 1),
+		signTxBatchChan:           make(chan tdtypes.RPCRequest, 1),
 		waitSignLock:              &sync.RWMutex{},
 		waitSignMsgs:              make(map[string]common.SignStateRequest),
 		waitSignSlashLock:         &sync.RWMutex{},
 		waitSignSlashMsgs:         make(map[string]map[uint64]common.SlashRequest),
 		cacheVerifyLock:           &sync.RWMutex{},
-		cacheVerify:               types.NewCache[string, bool](1000),
+		cacheVerify:               types.NewCache[uint64, *rpcHeader](verifyCacheSize),
 		cacheSignLock:             &sync.RWMutex{},
 		cacheSign:                 types.NewCache[string, []byte](10),
 		nodeStore:                 nodeStore,
@@ -158,7 +171,7 @@ func NewProcessor(cfg common.Configuration, contx context.Context, tssInstance t
 func (p *Processor) Start() {
 	p.logger.Info().Msg("Signer is starting")
 	//The concurrency number needs to be equal to the total number of threads launched by the run() function.
-	p.wg.Add(10)
+	p.wg.Add(13)
 	p.run()
 }
 
@@ -166,7 +179,7 @@ func (p *Processor) Stop() {
 	p.logger.Info().Msg("going to stop signer")
 	defer p.logger.Info().Msg("signer stopped")
 	close(p.stopChan)
-	p.wsClient.Cli.Stop()
+	p.wsClient.Stop()
 	p.cancel()
 	p.l2Client.Close()
 	p.l1Client.Close()
@@ -180,8 +193,11 @@ func (p *Processor) run() {
 	go p.Sign()
 	go p.SignSlash()
 	go p.Keygen()
+	go p.Reshare()
 	go p.deleteSlashing()
 	go p.SignRollBack()
 	go p.VerifyRollBack()
 	go p.ObserveTssGroup()
+	go p.VerifyTxBatch()
+	go p.SignTxBatch()
 }
@@ -0,0 +1,146 @@
+package signer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/influxdata/influxdb/pkg/slices"
+	tdtypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+
+	"github.com/mantlenetworkio/mantle/l2geth/log"
+	tsscommon "github.com/mantlenetworkio/mantle/tss/common"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/common"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/reshare"
+)
+
+func (p *Processor) Reshare() {
+	defer p.wg.Done()
+	logger := p.logger.With().Str("step", "reshare").Logger()
+
+	logger.Info().Msg("start to reshare ")
+
+	go func() {
+		defer func() {
+			logger.Info().Msg("exit reshare process")
+		}()
+		for {
+			select {
+			case <-p.stopChan:
+				return
+			case req := <-p.reshareRequestChan:
+				var resId = req.ID.(tdtypes.JSONRPCStringID).String()
+				logger.Info().Msgf("dealing resId (%s) ", resId)
+
+				var reshareR tsscommon.ReshareRequest
+				if err := json.Unmarshal(req.Params, &reshareR); err != nil {
+					logger.Error().Msg("failed to unmarshal reshare request")
+					RpcResponse := tdtypes.NewRPCErrorResponse(req.ID, 201, "failed", err.Error())
+					if err = p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+				if !p.verifyReshare(reshareR) {
+					logger.Error().Msg("verify election in reshare request is false")
+					RpcResponse := tdtypes.NewRPCErrorResponse(req.ID, 201, "failed", "verify election in reshare request is false")
+					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+
+				reshareReq := reshare.NewRequest(
+					reshareR.ClusterPublicKey,
+					reshareR.OldNodes,
+					reshareR.OldThreshold,
+					reshareR.OldEpoch,
+					reshareR.NewNodes,
+					reshareR.NewThreshold,
+					reshareR.ElectionId,
+				)
+				resp, err := p.tssServer.Reshare(reshareReq)
+				if err != nil || resp.Status != common.Success {
+					var RpcResponse tdtypes.RPCResponse
+					failReason := resp.FailReason
+					if err != nil {
+						logger.Err(err).Msg("failed to reshare !")
+						failReason = err.Error()
+					}
+					if len(resp.AbnormalPubKeys) > 0 {
+						RpcResponse = tdtypes.NewRPCErrorResponse(req.ID, tsscommon.CulpritErrorCode, failReason, strings.Join(resp.AbnormalPubKeys, ","))
+					} else {
+						RpcResponse = tdtypes.NewRPCErrorResponse(req.ID, 202, "failed", failReason)
+					}
+					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+
+				reshareResponse := tsscommon.ReshareResponse{
+					ClusterPublicKey: resp.PubKey,
+				}
+				RpcResponse := tdtypes.NewRPCSuccessResponse(tdtypes.JSONRPCStringID(resId), reshareResponse)
+				if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+					logger.Error().Err(err).Msg("failed to send msg to manager")
+				}
+				// only the members of the new committee hold a share of the cluster key now
+				if slices.ExistsIgnoreCase(reshareR.NewNodes, hex.EncodeToString(p.localPubKeyByte)) {
+					logger.Info().Msgf("reshare start to set group publickey for l1 contract")
+					if err := p.setGroupPublicKey(p.localPubKeyByte, resp.PubKeyByte); err != nil {
+						logger.Err(err).Msg("failed to send tss group manager transactionx")
+					}
+				}
+			}
+		}
+	}()
+}
+
+// verifyReshare checks the request moves the active cluster key from members
+// of the active committee to the inactive committee, at their thresholds
+func (p *Processor) verifyReshare(reshare tsscommon.ReshareRequest) bool {
+	inactiveInfo, err := p.tssQueryService.QueryInactiveInfo()
+	if err != nil {
+		log.Error("failed to query inactive info", "err", err)
+		return false
+	}
+	if inactiveInfo.ElectionId != reshare.ElectionId {
+		return false
+	}
+	if reshare.NewThreshold != inactiveInfo.Threshold || !sameMembers(reshare.NewNodes, inactiveInfo.TssMembers) {
+		log.Error("the new committee of the reshare request does not match the inactive committee", "election id", reshare.ElectionId)
+		return false
+	}
+	activeInfo, err := p.tssQueryService.QueryActiveInfo()
+	if err != nil {
+		log.Error("failed to query active info", "err", err)
+		return false
+	}
+	if !strings.EqualFold(activeInfo.ClusterPubKey, reshare.ClusterPublicKey) {
+		return false
+	}
+	if reshare.OldThreshold != activeInfo.Threshold || !isMembersOf(reshare.OldNodes, activeInfo.TssMembers) {
+		log.Error("the old committee of the reshare request does not match the active committee", "election id", activeInfo.ElectionId)
+		return false
+	}
+	return true
+}
+
+// sameMembers returns whether both lists hold the same members
+func sameMembers(nodes, members []string) bool {
+	return len(nodes) == len(members) && isMembersOf(nodes, members) && isMembersOf(members, nodes)
+}
+
+// isMembersOf returns whether every node is one of the members, at most once
+func isMembersOf(nodes, members []string) bool {
+	seen := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		key := strings.ToLower(node)
+		if _, ok := seen[key]; ok || !slices.ExistsIgnoreCase(members, node) {
+			return false
+		}
+		seen[key] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package signer
+
+import (
+	"encoding/json"
+	"strings"
+
+	tdtypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
+	tsscommon "github.com/mantlenetworkio/mantle/tss/common"
+)
+
+func (p *Processor) SignTxBatch() {
+	defer p.wg.Done()
+	logger := p.logger.With().Str("step", "sign tx batch Message").Logger()
+
+	logger.Info().Msg("start to sign tx batch message ")
+
+	go func() {
+		defer func() {
+			logger.Info().Msg("exit sign tx batch process")
+		}()
+		for {
+			select {
+			case <-p.stopChan:
+				return
+			case req := <-p.signTxBatchChan:
+				var resId = req.ID.(tdtypes.JSONRPCStringID).String()
+				logger.Info().Msgf("dealing resId (%s) ", resId)
+
+				var nodeSignRequest tsscommon.NodeSignRequest
+				rawMsg := json.RawMessage{}
+				nodeSignRequest.RequestBody = &rawMsg
+
+				if err := json.Unmarshal(req.Params, &nodeSignRequest); err != nil {
+					logger.Error().Msg("failed to unmarshal tx batch request")
+					RpcResponse := tdtypes.NewRPCErrorResponse(req.ID, 201, "failed", err.Error())
+					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+				var requestBody tsscommon.SignTxBatchRequest
+				if err := json.Unmarshal(rawMsg, &requestBody); err != nil {
+					logger.Error().Msg("failed to umarshal tx batch params request body")
+					RpcResponse := tdtypes.NewRPCErrorResponse(req.ID, 201, "failed", err.Error())
+					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+				// the headers are cached by the ask step, so checking the batch
+				// again here is cheap and keeps a node from signing a batch it
+				// has not verified
+				verified, _, err := p.verifyTxBatch(requestBody, logger)
+				if err == nil && !verified {
+					err = errTxBatchMismatch
+				}
+				if err != nil {
+					logger.Err(err).Msg("refuse to sign tx batch")
+					RpcResponse := tdtypes.NewRPCErrorResponse(req.ID, 201, "failed", err.Error())
+					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+				nodeSignRequest.RequestBody = requestBody
+				hashTx, err := tsscommon.TxBatchHash(requestBody.TxRoots, requestBody.StartBlock)
+				if err != nil {
+					logger.Err(err).Msg("failed to encode tx batch msg")
+					RpcResponse := tdtypes.NewRPCErrorResponse(req.ID, 201, "failed", err.Error())
+					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+
+				var signResponse tsscommon.SignResponse
+
+				hashStr := hexutil.Encode(hashTx)
+				signByte, ok := p.GetSign(hashStr)
+				if ok {
+					logger.Info().Msg("singer get tx batch signature from cache")
+					signResponse = tsscommon.SignResponse{
+						Signature: signByte,
+					}
+				} else {
+					data, culprits, err := p.handleSign(nodeSignRequest, hashTx, logger)
+					if err != nil {
+						logger.Error().Msgf("tx batch %s sign failed ", hashStr)
+						var errorRes tdtypes.RPCResponse
+						if len(culprits) > 0 {
+							respData := strings.Join(culprits, ",")
+							errorRes = tdtypes.NewRPCErrorResponse(req.ID, tsscommon.CulpritErrorCode, err.Error(), respData)
+							p.nodeStore.AddCulprits(culprits)
+						} else {
+							errorRes = tdtypes.NewRPCErrorResponse(req.ID, 201, "sign failed", err.Error())
+						}
+						if er := p.wsClient.SendMsg(errorRes); er != nil {
+							logger.Err(er).Msg("failed to send msg to tss manager")
+						}
+						continue
+					}
+					signResponse = tsscommon.SignResponse{
+						Signature: data,
+					}
+					bol := p.CacheSign(hashStr, data)
+					logger.Info().Msgf("cache tx batch sign byte behavior %t ", bol)
+				}
+
+				RpcResponse := tdtypes.NewRPCSuccessResponse(req.ID, signResponse)
+				if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+					logger.Err(err).Msg("failed to sendMsg to tss manager ")
+				} else {
+					logger.Info().Msg("send tx batch sign response successfully")
+				}
+			}
+		}
+	}()
+}
@@ -2,18 +2,33 @@ package signer
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/big"
-	"sync"
 
 	"github.com/rs/zerolog"
 
+	ethc "github.com/mantlenetworkio/mantle/l2geth/common"
 	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
-	"github.com/mantlenetworkio/mantle/l2geth/core/types"
+	"github.com/mantlenetworkio/mantle/l2geth/rpc"
 	"github.com/mantlenetworkio/mantle/tss/common"
 
 	tdtypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 )
 
+const (
+	// verifyCacheSize is the number of l2 block headers kept by the node
+	verifyCacheSize = 1000
+	// verifyBatchSize is the number of blocks queried in one batched rpc call
+	verifyBatchSize = 100
+	verifyRetries   = 3
+)
+
+// rpcHeader is the part of an l2 block the verification needs
+type rpcHeader struct {
+	Root   ethc.Hash `json:"stateRoot"`
+	TxRoot ethc.Hash `json:"transactionsRoot"`
+}
+
 func (p *Processor) Verify() {
 	defer p.wg.Done()
 	logger := p.logger.With().Str("step", "verify event").Logger()
@@ -60,9 +75,7 @@ func (p *Processor) Verify() {
 					}
 					continue
 				} else {
-					wg := &sync.WaitGroup{}
-					wg.Add(1)
-					result, err := p.verify(askRequest.StartBlock, size-1, askRequest.StateRoots[size-1], logger, wg)
+					result, mismatchIndex, err := p.verify(askRequest.StartBlock, askRequest.StateRoots, logger)
 					if !result {
 						if err != nil {
 							logger.Error().Msgf("failed to verify block %s", err.Error())
@@ -87,7 +100,8 @@ func (p *Processor) Verify() {
 						}
 					}
 					askResponse := common.AskResponse{
-						Result: result,
+						Result:        result,
+						MismatchIndex: mismatchIndex,
 					}
 					RpcResponse = tdtypes.NewRPCSuccessResponse(resId, askResponse)
 					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
@@ -101,44 +115,123 @@ func (p *Processor) Verify() {
 	}()
 }
 
-func (p *Processor) verify(start *big.Int, index int, stateRoot [32]byte, logger zerolog.Logger, wg *sync.WaitGroup) (bool, error) {
-	defer wg.Done()
+// verify compares every state root of the request with the l2 chain and
+// returns the index of the first one that differs
+func (p *Processor) verify(start *big.Int, stateRoots [][32]byte, logger zerolog.Logger) (bool, *uint64, error) {
+	startBlock := start.Uint64()
+	logger.Info().Msgf("start to verify blocks from %d to %d", startBlock, startBlock+uint64(len(stateRoots))-1)
+	index, err := p.verifyHeaders(startBlock, len(stateRoots), func(i int, header *rpcHeader) bool {
+		if stateRoots[i] == header.Root {
+			return true
+		}
+		logger.Info().Msgf("block number (%d) state root doesn't same, state root (%s) , block root (%s)", startBlock+uint64(i), hexutil.Encode(stateRoots[i][:]), header.Root.Hex())
+		return false
+	}, logger)
+	if err != nil || index != nil {
+		return false, index, err
+	}
+	logger.Info().Msgf("blocks from %d to %d verify success", startBlock, startBlock+uint64(len(stateRoots))-1)
+	return true, nil, nil
+}
 
-	offset := new(big.Int).SetInt64(int64(index))
-	blockNumber := offset.Add(offset, start)
-	logger.Info().Msgf("start to query block by number %d", blockNumber)
+// verifyHeaders runs match on the headers of count blocks from start and
+// returns the index of the first one that does not match. A cached header
+// may be stale after an l2 rollback, so it is queried again before it is
+// reported as a mismatch.
+func (p *Processor) verifyHeaders(start uint64, count int, match func(int, *rpcHeader) bool, logger zerolog.Logger) (*uint64, error) {
+	headers, err := p.l2Headers(start, count, logger)
+	if err != nil {
+		return nil, err
+	}
+	for i, header := range headers {
+		if match(i, header) {
+			continue
+		}
+		blockNumber := start + uint64(i)
+		fresh, err := p.fetchHeaders([]uint64{blockNumber}, logger)
+		if err != nil {
+			return nil, err
+		}
+		p.CacheVerify(blockNumber, fresh[0])
+		if match(i, fresh[0]) {
+			continue
+		}
+		index := uint64(i)
+		return &index, nil
+	}
+	return nil, nil
+}
 
-	value, ok := p.GetVerify(blockNumber.String())
-	if ok {
-		if value {
-			return value, nil
+// l2Headers returns the headers of count blocks from start, the blocks that
+// are not cached are fetched in batched rpc calls
+func (p *Processor) l2Headers(start uint64, count int, logger zerolog.Logger) ([]*rpcHeader, error) {
+	headers := make([]*rpcHeader, count)
+	missing := make([]uint64, 0)
+	for i := 0; i < count; i++ {
+		if header, ok := p.GetVerify(start + uint64(i)); ok {
+			headers[i] = header
+		} else {
+			missing = append(missing, start+uint64(i))
 		}
 	}
+	for len(missing) > 0 {
+		n := len(missing)
+		if n > verifyBatchSize {
+			n = verifyBatchSize
+		}
+		fetched, err := p.fetchHeaders(missing[:n], logger)
+		if err != nil {
+			return nil, err
+		}
+		for i, blockNumber := range missing[:n] {
+			headers[blockNumber-start] = fetched[i]
+			p.CacheVerify(blockNumber, fetched[i])
+		}
+		missing = missing[n:]
+	}
+	return headers, nil
+}
 
-	var block *types.Block
-	var err error
-	for i := 0; i < 3; i++ {
-		block, err = p.l2Client.BlockByNumber(p.ctx, blockNumber)
-		if err == nil {
+func (p *Processor) fetchHeaders(blockNumbers []uint64, logger zerolog.Logger) ([]*rpcHeader, error) {
+	var (
+		headers []*rpcHeader
+		err     error
+	)
+	for i := 0; i < verifyRetries; i++ {
+		if headers, err = p.batchHeaders(blockNumbers); err == nil {
 			break
-		} else {
-			logger.Info().Msgf("retry to query block by number %d, times %d", blockNumber, i)
 		}
+		logger.Info().Msgf("retry to query blocks from %d, times %d", blockNumbers[0], i)
 	}
 	if err != nil {
-		logger.Err(err).Msgf("failed to get block by (%d) ", blockNumber)
-		return false, err
-	} else {
-		if hexutil.Encode(stateRoot[:]) != block.Root().String() {
-			logger.Info().Msgf("block number (%d) state root doesn't same, state root (%s) , block root (%s)", blockNumber, hexutil.Encode(stateRoot[:]), block.Root().String())
-			p.CacheVerify(blockNumber.String(), false)
-			return false, nil
-		} else {
-			logger.Info().Msgf("block number (%d) verify success", blockNumber)
-			p.CacheVerify(blockNumber.String(), true)
-			return true, nil
+		logger.Err(err).Msgf("failed to get blocks from (%d) ", blockNumbers[0])
+		return nil, err
+	}
+	return headers, nil
+}
+
+func (p *Processor) batchHeaders(blockNumbers []uint64) ([]*rpcHeader, error) {
+	headers := make([]*rpcHeader, len(blockNumbers))
+	reqs := make([]rpc.BatchElem, len(blockNumbers))
+	for i, blockNumber := range blockNumbers {
+		reqs[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(blockNumber), false},
+			Result: &headers[i],
+		}
+	}
+	if err := p.l2RpcClient.BatchCallContext(p.ctx, reqs); err != nil {
+		return nil, err
+	}
+	for i, req := range reqs {
+		if req.Error != nil {
+			return nil, fmt.Errorf("failed to get block %d: %w", blockNumbers[i], req.Error)
+		}
+		if headers[i] == nil {
+			return nil, fmt.Errorf("block %d not found", blockNumbers[i])
 		}
 	}
+	return headers, nil
 }
 
 func (p *Processor) UpdateWaitSignEvents(uniqueId string, msg common.SignStateRequest) {
@@ -147,13 +240,13 @@ func (p *Processor) UpdateWaitSignEvents(uniqueId string, msg common.SignStateRe
 	p.waitSignMsgs[uniqueId] = msg
 }
 
-func (p *Processor) CacheVerify(key string, value bool) bool {
+func (p *Processor) CacheVerify(key uint64, value *rpcHeader) bool {
 	p.cacheVerifyLock.Lock()
 	defer p.cacheVerifyLock.Unlock()
 	return p.cacheVerify.Set(key, value)
 }
 
-func (p *Processor) GetVerify(key string) (bool, bool) {
+func (p *Processor) GetVerify(key uint64) (*rpcHeader, bool) {
 	p.cacheVerifyLock.RLock()
 	defer p.cacheVerifyLock.RUnlock()
 	return p.cacheVerify.Get(key)
@@ -0,0 +1,84 @@
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/rs/zerolog"
+	tdtypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+
+	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
+	"github.com/mantlenetworkio/mantle/tss/common"
+)
+
+var errTxBatchMismatch = errors.New("tx batch does not match the l2 chain")
+
+func (p *Processor) VerifyTxBatch() {
+	defer p.wg.Done()
+	logger := p.logger.With().Str("step", "verify tx batch event").Logger()
+	logger.Info().Msg("start to verify tx batch events ")
+
+	go func() {
+		defer func() {
+			logger.Info().Msg("exit verify tx batch event process")
+		}()
+		for {
+			select {
+			case <-p.stopChan:
+				return
+			case req := <-p.askTxBatchChan:
+				var RpcResponse tdtypes.RPCResponse
+				var askRequest common.SignTxBatchRequest
+				if err := json.Unmarshal(req.Params, &askRequest); err != nil {
+					logger.Error().Msg("failed to unmarshal ask tx batch request")
+					RpcResponse = tdtypes.NewRPCErrorResponse(req.ID, 201, "failed to unmarshal ", err.Error())
+					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+				result, mismatchIndex, err := p.verifyTxBatch(askRequest, logger)
+				if err != nil {
+					logger.Error().Msgf("failed to verify tx batch %s", err.Error())
+					RpcResponse = tdtypes.NewRPCErrorResponse(req.ID, 201, "get error when verify ", err.Error())
+					if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+						logger.Error().Err(err).Msg("failed to send msg to manager")
+					}
+					continue
+				}
+				askResponse := common.AskResponse{
+					Result:        result,
+					MismatchIndex: mismatchIndex,
+				}
+				RpcResponse = tdtypes.NewRPCSuccessResponse(req.ID, askResponse)
+				if err := p.wsClient.SendMsg(RpcResponse); err != nil {
+					logger.Error().Err(err).Msg("failed to send msg to manager")
+				}
+			}
+		}
+	}()
+}
+
+// verifyTxBatch compares the transactions root of every block of the batch
+// with the l2 chain and returns the index of the first one that differs
+func (p *Processor) verifyTxBatch(request common.SignTxBatchRequest, logger zerolog.Logger) (bool, *uint64, error) {
+	if request.StartBlock == nil || request.StartBlock.Cmp(big.NewInt(0)) < 0 {
+		return false, nil, errors.New("StartBlock must not be nil or negative")
+	}
+	if len(request.TxRoots) == 0 {
+		return false, nil, errors.New("tx roots size is empty")
+	}
+	startBlock := request.StartBlock.Uint64()
+	index, err := p.verifyHeaders(startBlock, len(request.TxRoots), func(i int, header *rpcHeader) bool {
+		if request.TxRoots[i] == header.TxRoot {
+			return true
+		}
+		logger.Info().Msgf("block number (%d) tx root doesn't same, tx root (%s) , block tx root (%s)", startBlock+uint64(i), hexutil.Encode(request.TxRoots[i][:]), header.TxRoot.Hex())
+		return false
+	}, logger)
+	if err != nil || index != nil {
+		return false, index, err
+	}
+	return true, nil, nil
+}
@@ -15,6 +15,7 @@ const (
 	InternalError       = "fail to start the join party "
 	GenerateNewKeyError = "fail to generate new key"
 	SignatureError      = "fail to signature message"
+	ReshareKeyError     = "fail to reshare key"
 )
 
 var (
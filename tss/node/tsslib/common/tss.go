@@ -27,6 +27,9 @@ type TssCommon struct {
 
 	partyLock sync.RWMutex
 	partyInfo *abnormal2.PartyInfo
+	// localParties is only set by a reshare, a node in both the old and the
+	// new committee runs one party for each
+	localParties []tss.Party
 
 	partyIDtoP2PIDMap      *sync.Map // map[string]peer.ID
 	unConfirmedMessagesMap *sync.Map // map[string]*LocalCacheItem
@@ -188,6 +191,47 @@ func (t *TssCommon) getPartyInfo() *abnormal2.PartyInfo {
 	return t.partyInfo
 }
 
+func (t *TssCommon) SetLocalParties(parties ...tss.Party) {
+	t.partyLock.Lock()
+	defer t.partyLock.Unlock()
+	t.localParties = parties
+}
+
+// expectedTaskDonePeers returns the number of peers that notify the end of
+// the task. In a reshare a node in both committees has two parties but is a
+// single peer, so the peers are counted instead of the parties.
+func (t *TssCommon) expectedTaskDonePeers() int {
+	t.partyLock.RLock()
+	reshare := len(t.localParties) > 0
+	t.partyLock.RUnlock()
+	if !reshare {
+		return len(t.partyInfo.PartyIDMap) - 1
+	}
+	t.P2PPeersLock.RLock()
+	defer t.P2PPeersLock.RUnlock()
+	return len(t.P2PPeers)
+}
+
+// addressedParties returns the local parties the message is sent to
+func (t *TssCommon) addressedParties(partyInfo *abnormal2.PartyInfo, routing *tss.MessageRouting) []tss.Party {
+	t.partyLock.RLock()
+	localParties := t.localParties
+	t.partyLock.RUnlock()
+	if len(localParties) == 0 {
+		return []tss.Party{partyInfo.Party}
+	}
+	var parties []tss.Party
+	for _, party := range localParties {
+		for _, to := range routing.To {
+			if to.Id == party.PartyID().Id {
+				parties = append(parties, party)
+				break
+			}
+		}
+	}
+	return parties
+}
+
 func (t *TssCommon) GetLocalPeerID() string {
 	return t.localPeerID
 }
@@ -296,6 +340,7 @@ func (t *TssCommon) updateLocal(wireMsg *messages.WireMessage) error {
 	//	return errors.New("cannot find the party")
 	//}
 	localMsgParty := partyInfo.Party
+	localMsgParties := t.addressedParties(partyInfo, bulkMsg.Routing)
 	rPartyID, ok := partyInfo.PartyIDMap[bulkMsg.Routing.From.Id]
 	if !ok {
 		t.logger.Error().Msg("error in find the partyID")
@@ -341,8 +386,10 @@ func (t *TssCommon) updateLocal(wireMsg *messages.WireMessage) error {
 			return err
 		}
 
-		job := newJob(localMsgParty, bulkMsg.WiredBulkMsg, round.MsgIdentifier, partyID, bulkMsg.Routing.IsBroadcast)
-		tssJobChan <- job
+		for _, party := range localMsgParties {
+			job := newJob(party, bulkMsg.WiredBulkMsg, round.MsgIdentifier, partyID, bulkMsg.Routing.IsBroadcast)
+			tssJobChan <- job
+		}
 	}
 
 	close(tssJobChan)
@@ -388,13 +435,28 @@ func (t *TssCommon) sendBulkMsg(wiredMsgType string, tssMsgType messages.TSSMess
 		peerIDs = t.P2PPeers
 		t.P2PPeersLock.RUnlock()
 	} else {
+		toLocal := false
 		for _, each := range r.To {
 			peerID, ok := t.partyIDtoP2PIDMap.Load(each.Id)
 			if !ok {
 				t.logger.Error().Msg("error in find the P2P ID")
 				continue
 			}
-			peerIDs = append(peerIDs, peerID.(peer.ID))
+			// in a reshare the other local party can be a receiver
+			if peerID.(peer.ID).String() == t.localPeerID {
+				toLocal = true
+				continue
+			}
+			if !containsPeer(peerIDs, peerID.(peer.ID)) {
+				peerIDs = append(peerIDs, peerID.(peer.ID))
+			}
+		}
+		if toLocal {
+			go func() {
+				if err := t.updateLocal(&wireMsg); err != nil {
+					t.logger.Error().Err(err).Msg("fail to apply the message to the local party")
+				}
+			}()
 		}
 	}
 	t.renderToP2P(&messages.BroadcastMsgChan{
@@ -551,7 +613,7 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 	}
 
 	switch wrappedMsg.MessageType {
-	case messages.TSSKeyGenMsg, messages.TSSKeySignMsg:
+	case messages.TSSKeyGenMsg, messages.TSSKeySignMsg, messages.TSSKeyReshareMsg:
 		var wireMsg messages.WireMessage
 		if err := json.Unmarshal(wrappedMsg.Payload, &wireMsg); nil != err {
 			return fmt.Errorf("fail to unmarshal wire message: %w", err)
@@ -570,7 +632,7 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 				return fmt.Errorf("duplicated notification from peer %s ignored", peerID)
 			}
 			t.finishedPeers[peerID] = true
-			if len(t.finishedPeers) == len(t.partyInfo.PartyIDMap)-1 {
+			if len(t.finishedPeers) == t.expectedTaskDonePeers() {
 				t.logger.Debug().Msg("we get the confirm of the nodes that generate the signature")
 				close(t.taskDone)
 			}
@@ -604,7 +666,7 @@ func (t *TssCommon) processTSSMsg(wireMsg *messages.WireMessage, msgType message
 		t.logger.Error().Msg("error in find the data owner")
 		return errors.New("error in find the data owner")
 	}
-	keyBytes := dataOwner.GetKey()
+	keyBytes := conversion.PartyPubKey(dataOwner)
 
 	ok = verifySignature(keyBytes, wireMsg.Message, wireMsg.Sig, t.msgID)
 	if !ok {
@@ -11,8 +11,10 @@ import (
 	"strings"
 
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/binance-chain/tss-lib/ecdsa/resharing"
 	"github.com/binance-chain/tss-lib/ecdsa/signing"
 	"github.com/binance-chain/tss-lib/tss"
 
@@ -110,6 +112,42 @@ func GetMsgRound(msg []byte, partyID *tss.PartyID, isBroadcast bool) (abnormal.R
 			RoundMsg: messages2.KEYSIGN9,
 		}, nil
 
+	case *resharing.DGRound1Message:
+		return abnormal.RoundInfo{
+			Index:    0,
+			RoundMsg: messages2.RESHARE1,
+		}, nil
+
+	case *resharing.DGRound2Message1:
+		return abnormal.RoundInfo{
+			Index:    1,
+			RoundMsg: messages2.RESHARE2a,
+		}, nil
+
+	case *resharing.DGRound2Message2:
+		return abnormal.RoundInfo{
+			Index:    2,
+			RoundMsg: messages2.RESHARE2b,
+		}, nil
+
+	case *resharing.DGRound3Message1:
+		return abnormal.RoundInfo{
+			Index:    3,
+			RoundMsg: messages2.RESHARE3aUnicast,
+		}, nil
+
+	case *resharing.DGRound3Message2:
+		return abnormal.RoundInfo{
+			Index:    4,
+			RoundMsg: messages2.RESHARE3b,
+		}, nil
+
+	case *resharing.DGRound4Message:
+		return abnormal.RoundInfo{
+			Index:    5,
+			RoundMsg: messages2.RESHARE4,
+		}, nil
+
 	default:
 		return abnormal.RoundInfo{}, errors.New("unknown round")
 	}
@@ -128,6 +166,11 @@ func checkUnicast(round abnormal.RoundInfo) bool {
 		}
 		return false
 	}
+	// reshare unicast blame, the shares of the new committee are only
+	// verified against the commitments of the next message
+	if strings.Contains(round.RoundMsg, "DGR") {
+		return index == 3 || index == 4
+	}
 	// keysign unicast blame
 	if index < 5 {
 		return true
@@ -187,6 +230,15 @@ func Contains(s []*tss.PartyID, e *tss.PartyID) bool {
 	return false
 }
 
+func containsPeer(peers []peer.ID, p peer.ID) bool {
+	for _, each := range peers {
+		if each == p {
+			return true
+		}
+	}
+	return false
+}
+
 func MsgToHashInt(digest []byte) *big.Int {
 	return hashToInt(digest, btcec.S256())
 }
@@ -9,6 +9,8 @@ type TssConfig struct {
 	KeyGenTimeout time.Duration
 	// KeySignTimeoutSeconds defines how long do we wait keysign
 	KeySignTimeout time.Duration
+	// KeyReshareTimeout defines how long do we wait the reshare parties to pass messages along
+	KeyReshareTimeout time.Duration
 	// Pre-parameter define the pre-parameter generations timeout
 	PreParamTimeout time.Duration
 	// enable the tss monitor
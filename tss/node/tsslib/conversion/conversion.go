@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/binance-chain/tss-lib/crypto"
 	"github.com/binance-chain/tss-lib/tss"
@@ -17,7 +18,18 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
+// pubKeyLength is the length of a compressed secp256k1 public key
+const pubKeyLength = 33
+
 func GetParties(keys []string, localPartyKey string) ([]*tss.PartyID, *tss.PartyID, error) {
+	return GetPartiesAtEpoch(keys, localPartyKey, 0)
+}
+
+// GetPartiesAtEpoch builds the parties of a committee whose shares were dealt
+// at the given key epoch. A reshare deals the shares of the new committee at a
+// new epoch, so a node in both committees gets two distinct parties. The epoch
+// is stored above the public key in the party key, epoch 0 is a fresh keygen.
+func GetPartiesAtEpoch(keys []string, localPartyKey string, epoch uint64) ([]*tss.PartyID, *tss.PartyID, error) {
 	var localPartyID *tss.PartyID
 	var unSortedPartiesID []*tss.PartyID
 	sort.Strings(keys)
@@ -27,11 +39,16 @@ func GetParties(keys []string, localPartyKey string) ([]*tss.PartyID, *tss.Party
 			return nil, nil, fmt.Errorf("fail to get account pub key (%s): %w", item, err)
 		}
 		key := new(big.Int).SetBytes(pkBytes)
+		id := strconv.Itoa(idx)
+		if epoch > 0 {
+			key.Or(key, new(big.Int).Lsh(new(big.Int).SetUint64(epoch), 8*pubKeyLength))
+			id = fmt.Sprintf("%d-%d", epoch, idx)
+		}
 		// Set up the parameters
 		// Note: The `id` and `moniker` fields are for convenience to allow you to easily track participants.
 		// The `id` should be a unique string representing this party in the network and `moniker` can be anything (even left blank).
 		// The `uniqueKey` is a unique identifying key for this peer (such as its p2p public key) as a big.Int.
-		partyID := tss.NewPartyID(strconv.Itoa(idx), "", key)
+		partyID := tss.NewPartyID(id, "", key)
 		if item == localPartyKey {
 			localPartyID = partyID
 		}
@@ -45,6 +62,26 @@ func GetParties(keys []string, localPartyKey string) ([]*tss.PartyID, *tss.Party
 	return partiesID, localPartyID, nil
 }
 
+// GetCommittee builds the parties of a reshare committee, the local node does
+// not have to be a member
+func GetCommittee(keys []string, epoch uint64) ([]*tss.PartyID, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("empty committee")
+	}
+	partiesID, _, err := GetPartiesAtEpoch(append([]string{}, keys...), keys[0], epoch)
+	return partiesID, err
+}
+
+// FindParty returns the party of the node public key, or nil
+func FindParty(partiesID []*tss.PartyID, pubKey string) *tss.PartyID {
+	for _, partyID := range partiesID {
+		if strings.EqualFold(hex.EncodeToString(PartyPubKey(partyID)), pubKey) {
+			return partyID
+		}
+	}
+	return nil
+}
+
 func SetupPartyIDMap(partiesID []*tss.PartyID) map[string]*tss.PartyID {
 	partyIDMap := make(map[string]*tss.PartyID)
 	for _, id := range partiesID {
@@ -69,9 +106,16 @@ func GetPeerIDFromPartyID(partyID *tss.PartyID) (peer.ID, error) {
 	if partyID == nil || !partyID.ValidateBasic() {
 		return "", errors.New("invalid partyID")
 	}
-	pkBytes := partyID.KeyInt().Bytes()
+	return GetPeerIDFromSecp256PubKey(PartyPubKey(partyID))
+}
 
-	return GetPeerIDFromSecp256PubKey(pkBytes)
+// PartyPubKey returns the node public key of the party, without its key epoch
+func PartyPubKey(partyID *tss.PartyID) []byte {
+	key := partyID.GetKey()
+	if len(key) > pubKeyLength {
+		return key[len(key)-pubKeyLength:]
+	}
+	return key
 }
 
 func GetPeerIDFromSecp256PubKey(pk []byte) (peer.ID, error) {
@@ -91,10 +135,13 @@ func GetPeersID(partyIDtoP2PID map[string]peer.ID, localPeerID string) []peer.ID
 		return nil
 	}
 	peerIDs := make([]peer.ID, 0, len(partyIDtoP2PID)-1)
+	seen := make(map[peer.ID]bool, len(partyIDtoP2PID))
 	for _, value := range partyIDtoP2PID {
-		if value.String() == localPeerID {
+		// a node in both committees of a reshare has two parties
+		if value.String() == localPeerID || seen[value] {
 			continue
 		}
+		seen[value] = true
 		peerIDs = append(peerIDs, value)
 	}
 	return peerIDs
@@ -132,8 +179,7 @@ func PartyIDtoPubKey(party *tss.PartyID) (string, error) {
 	if party == nil || !party.ValidateBasic() {
 		return "", errors.New("invalid party")
 	}
-	partyKeyBytes := party.GetKey()
-	pubKey := hex.EncodeToString(partyKeyBytes)
+	pubKey := hex.EncodeToString(PartyPubKey(party))
 
 	return pubKey, nil
 }
@@ -38,10 +38,7 @@ func (t *TssServer) Keygen(req keygen2.Request) (keygen2.Response, error) {
 		t.preParams,
 		msgID,
 		t.stateManager,
-		t.secretsEnable,
-		t.secretsManager,
-		t.shamirEnable,
-		t.shamirManager,
+		t.keyShareStore,
 		t.privateKey,
 		t.p2pCommunication,
 		req.ThresHold,
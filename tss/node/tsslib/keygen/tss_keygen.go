@@ -32,10 +32,7 @@ type TssKeyGen struct {
 	stopChan        chan struct{} // channel to indicate whether we should stop
 	localParty      *tss.PartyID
 	stateManager    storage2.LocalStateManager
-	secretsEnable   bool
-	secretsManager  storage2.SecretsManager
-	shamirEnable    bool
-	shamirManager   storage2.ShamirManager
+	keyShareStore   storage2.KeyShareStore
 	commStopChan    chan struct{}
 	p2pComm         *p2p.Communication
 }
@@ -48,10 +45,7 @@ func NewTssKeyGen(localP2PID string,
 	preParam *keygen.LocalPreParams,
 	msgID string,
 	stateManager storage2.LocalStateManager,
-	secretsEnable bool,
-	secretsManager storage2.SecretsManager,
-	shamirEnable bool,
-	shamirManager storage2.ShamirManager,
+	keyShareStore storage2.KeyShareStore,
 	privateKey *ecdsa.PrivateKey,
 	p2pComm *p2p.Communication,
 	thresHold int) *TssKeyGen {
@@ -66,10 +60,7 @@ func NewTssKeyGen(localP2PID string,
 		stopChan:        stopChan,
 		localParty:      nil,
 		stateManager:    stateManager,
-		secretsEnable:   secretsEnable,
-		secretsManager:  secretsManager,
-		shamirEnable:    shamirEnable,
-		shamirManager:   shamirManager,
+		keyShareStore:   keyShareStore,
 		commStopChan:    make(chan struct{}),
 		p2pComm:         p2pComm,
 	}
@@ -211,23 +202,8 @@ func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 			keyGenLocalStateItem.LocalData = msg
 			keyGenLocalStateItem.PubKey = pubKey
 
-			if tKeyGen.shamirEnable {
-				if err := tKeyGen.shamirManager.PutKeyFile(keyGenLocalStateItem); err != nil {
-					return nil, fmt.Errorf("fail to put keygen result with shamir manager : %w", err)
-				}
-			} else if tKeyGen.secretsEnable {
-				if err := tKeyGen.secretsManager.PutKeyFile(keyGenLocalStateItem); err != nil {
-					return nil, fmt.Errorf("fail to put keygen result to secrets manager map : %w", err)
-				}
-
-				if err := tKeyGen.secretsManager.Save(); err != nil {
-					return nil, fmt.Errorf("fail to put keygen result to secrets manager :%w", err)
-				}
-
-			} else {
-				if err := tKeyGen.stateManager.SaveLocalState(keyGenLocalStateItem); err != nil {
-					return nil, fmt.Errorf("fail to save keygen result to storage: %w", err)
-				}
+			if err := tKeyGen.keyShareStore.PutKeyShare(keyGenLocalStateItem); err != nil {
+				return nil, fmt.Errorf("fail to save keygen result to key share store: %w", err)
 			}
 
 			address := tKeyGen.p2pComm.ExportPeerAddress()
@@ -64,7 +64,7 @@ func (tKeySign *TssKeySign) GetTssCommonStruct() *common2.TssCommon {
 
 // signMessage
 func (tKeySign *TssKeySign) SignMessage(msgToSign []byte, localStateItem storage.KeygenLocalState, parties []string) (*tsscommon.SignatureData, error) {
-	partiesID, localPartyID, err := conversion.GetParties(parties, localStateItem.LocalPartyKey)
+	partiesID, localPartyID, err := conversion.GetPartiesAtEpoch(parties, localStateItem.LocalPartyKey, localStateItem.KeyEpoch)
 	if err != nil {
 		return nil, fmt.Errorf("fail to form key sign party: %w", err)
 	}
@@ -81,7 +81,7 @@ func (tKeySign *TssKeySign) SignMessage(msgToSign []byte, localStateItem storage
 	m := common2.MsgToHashInt(msgToSign)
 
 	moniker := m.String()
-	partiesID, eachLocalPartyID, err := conversion.GetParties(parties, localStateItem.LocalPartyKey)
+	partiesID, eachLocalPartyID, err := conversion.GetPartiesAtEpoch(parties, localStateItem.LocalPartyKey, localStateItem.KeyEpoch)
 	ctx := tss.NewPeerContext(partiesID)
 	if err != nil {
 		return nil, fmt.Errorf("error to create parties in batch signging %w\n", err)
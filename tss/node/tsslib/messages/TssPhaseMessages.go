@@ -15,6 +15,13 @@ const (
 	KEYSIGN7         = "SignRound7Message"
 	KEYSIGN8         = "SignRound8Message"
 	KEYSIGN9         = "SignRound9Message"
+	RESHARE1         = "DGRound1Message"
+	RESHARE2a        = "DGRound2Message1"
+	RESHARE2b        = "DGRound2Message2"
+	RESHARE3aUnicast = "DGRound3Message1"
+	RESHARE3b        = "DGRound3Message2"
+	RESHARE4         = "DGRound4Message"
 	TSSKEYGENROUNDS  = 4
 	TSSKEYSIGNROUNDS = 8
+	TSSRESHAREROUNDS = 5
 )
@@ -13,6 +13,7 @@ const (
 	TSSKeyGenMsg TSSMessageTpe = iota
 	TSSKeySignMsg
 	TSSTaskDone
+	TSSKeyReshareMsg
 	Unknown
 )
 
@@ -22,6 +23,8 @@ func (msgType TSSMessageTpe) String() string {
 		return "TSSKeyGenMsg"
 	case TSSKeySignMsg:
 		return "TSSKeySignMsg"
+	case TSSKeyReshareMsg:
+		return "TSSKeyReshareMsg"
 	default:
 		return "Unknown"
 
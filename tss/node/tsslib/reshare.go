@@ -0,0 +1,122 @@
+package tsslib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/slices"
+
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/abnormal"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/common"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/conversion"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/messages"
+	reshare2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/reshare"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/storage"
+)
+
+func (t *TssServer) Reshare(req reshare2.Request) (reshare2.Response, error) {
+	t.tssKeyGenLocker.Lock()
+	defer t.tssKeyGenLocker.Unlock()
+	msgID, err := t.requestToMsgId(req)
+	if err != nil {
+		return reshare2.Response{}, err
+	}
+
+	if err = t.requestCheck(req); err != nil {
+		return reshare2.Response{}, err
+	}
+	t.logger.Info().
+		Str("old keys", strings.Join(req.OldKeys, ",")).
+		Str("old threshold", strconv.Itoa(req.OldThreshold)).
+		Str("new keys", strings.Join(req.NewKeys, ",")).
+		Str("new threshold", strconv.Itoa(req.NewThreshold)).
+		Msg("received reshare request")
+
+	var localState *storage.KeygenLocalState
+	if slices.ExistsIgnoreCase(req.OldKeys, t.localNodePubKey) {
+		state, err := t.getLocalState(req.PoolPubKey)
+		if err != nil {
+			return reshare2.Response{}, err
+		}
+		if state.KeyEpoch != req.OldEpoch {
+			return reshare2.Response{}, fmt.Errorf("the local share is at key epoch %d, not %d", state.KeyEpoch, req.OldEpoch)
+		}
+		localState = &state
+	}
+
+	reshareInstance := reshare2.NewTssReshare(
+		t.p2pCommunication.GetLocalPeerID(),
+		t.conf,
+		t.localNodePubKey,
+		t.p2pCommunication.BroadcastMsgChan,
+		t.stopChan,
+		t.preParams,
+		msgID,
+		t.stateManager,
+		t.keyShareStore,
+		t.privateKey,
+		t.p2pCommunication,
+		req.NewThreshold,
+	)
+
+	reshareMsgChannel := reshareInstance.GetTssReshareChannels()
+	t.p2pCommunication.SetSubscribe(messages.TSSKeyReshareMsg, msgID, reshareMsgChannel)
+	t.p2pCommunication.SetSubscribe(messages.TSSTaskDone, msgID, reshareMsgChannel)
+
+	defer func() {
+		t.p2pCommunication.CancelSubscribe(messages.TSSKeyReshareMsg, msgID)
+		t.p2pCommunication.CancelSubscribe(messages.TSSTaskDone, msgID)
+
+		t.p2pCommunication.ReleaseStream(msgID)
+	}()
+	abnormalMgr := reshareInstance.GetTssCommonStruct().GetAbnormalMgr()
+
+	beforeReshare := time.Now()
+	k, err := reshareInstance.Reshare(req, localState)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("err in reshare")
+		return reshare2.NewResponse(
+			"", nil, common.Fail,
+			abnormal.ReshareKeyError,
+			abnormalMgr.GetAbnormalNodePubKeys()), err
+	}
+	t.logger.Info().Msgf("reshare finished in %s", time.Since(beforeReshare))
+
+	pubkey, _, pubkeyByte, err := conversion.GetTssPubKey(k)
+	if err != nil {
+		return reshare2.NewResponse(
+			"",
+			nil,
+			common.Fail,
+			abnormal.ReshareKeyError,
+			abnormalMgr.GetAbnormalNodePubKeys()), err
+	}
+
+	return reshare2.NewResponse(
+		pubkey,
+		pubkeyByte,
+		common.Success,
+		"",
+		abnormalMgr.GetAbnormalNodePubKeys(),
+	), nil
+}
+
+// ActivateKeyShare puts the share a reshare dealt at the key epoch in use,
+// it is called once the committee of the epoch is active on L1
+func (t *TssServer) ActivateKeyShare(poolPubKey string, keyEpoch uint64) (bool, error) {
+	t.tssKeyGenLocker.Lock()
+	defer t.tssKeyGenLocker.Unlock()
+	activated, err := storage.ActivateKeyShare(t.keyShareStore, poolPubKey, keyEpoch)
+	if err != nil {
+		return false, err
+	}
+	if activated {
+		t.logger.Info().
+			Str("pool pub key", poolPubKey).
+			Uint64("key epoch", keyEpoch).
+			Msg("activated the reshared key share")
+	}
+	return activated, nil
+}
@@ -0,0 +1,24 @@
+package reshare
+
+type Request struct {
+	PoolPubKey   string   `json:"pool_pub_key"`
+	OldKeys      []string `json:"old_keys"`
+	OldThreshold int      `json:"old_threshold"`
+	// OldEpoch is the key epoch the shares of the old committee were dealt at
+	OldEpoch     uint64   `json:"old_epoch"`
+	NewKeys      []string `json:"new_keys"`
+	NewThreshold int      `json:"new_threshold"`
+	NewEpoch     uint64   `json:"new_epoch"`
+}
+
+func NewRequest(poolPubKey string, oldKeys []string, oldThreshold int, oldEpoch uint64, newKeys []string, newThreshold int, newEpoch uint64) Request {
+	return Request{
+		PoolPubKey:   poolPubKey,
+		OldKeys:      oldKeys,
+		OldThreshold: oldThreshold,
+		OldEpoch:     oldEpoch,
+		NewKeys:      newKeys,
+		NewThreshold: newThreshold,
+		NewEpoch:     newEpoch,
+	}
+}
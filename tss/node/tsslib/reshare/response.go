@@ -0,0 +1,23 @@
+package reshare
+
+import (
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/common"
+)
+
+type Response struct {
+	PubKey          string        `json:"pubKey"`
+	PubKeyByte      []byte        `json:"pubKey_byte"`
+	Status          common.Status `json:"status"`
+	FailReason      string        `json:"fail_reason"`
+	AbnormalPubKeys []string      `json:"abnormal_pub_keys"`
+}
+
+func NewResponse(pubkey string, pubkeyByte []byte, status common.Status, failReason string, abnormalPubkeys []string) Response {
+	return Response{
+		PubKey:          pubkey,
+		PubKeyByte:      pubkeyByte,
+		Status:          status,
+		FailReason:      failReason,
+		AbnormalPubKeys: abnormalPubkeys,
+	}
+}
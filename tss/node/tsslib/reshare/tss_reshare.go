@@ -0,0 +1,310 @@
+package reshare
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/influxdata/influxdb/pkg/slices"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	bcrypto "github.com/binance-chain/tss-lib/crypto"
+	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/binance-chain/tss-lib/ecdsa/resharing"
+	"github.com/binance-chain/tss-lib/tss"
+
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/abnormal"
+	common2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/common"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/conversion"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/messages"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/p2p"
+	storage2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/storage"
+)
+
+type TssReshare struct {
+	logger          zerolog.Logger
+	localNodePubKey string
+	preParams       *keygen.LocalPreParams
+	tssCommonStruct *common2.TssCommon
+	stopChan        chan struct{} // channel to indicate whether we should stop
+	localParties    []tss.Party
+	partyIDMap      map[string]*tss.PartyID
+	stateManager    storage2.LocalStateManager
+	keyShareStore   storage2.KeyShareStore
+	commStopChan    chan struct{}
+	p2pComm         *p2p.Communication
+}
+
+func NewTssReshare(localP2PID string,
+	conf common2.TssConfig,
+	localNodePubKey string,
+	broadcastChan chan *messages.BroadcastMsgChan,
+	stopChan chan struct{},
+	preParam *keygen.LocalPreParams,
+	msgID string,
+	stateManager storage2.LocalStateManager,
+	keyShareStore storage2.KeyShareStore,
+	privateKey *ecdsa.PrivateKey,
+	p2pComm *p2p.Communication,
+	thresHold int) *TssReshare {
+	return &TssReshare{
+		logger: log.With().
+			Str("module", "reshare").
+			Str("msgID", msgID).Logger(),
+		localNodePubKey: localNodePubKey,
+		preParams:       preParam,
+		tssCommonStruct: common2.NewTssCommon(localP2PID, broadcastChan, conf, msgID, privateKey, thresHold),
+		stopChan:        stopChan,
+		stateManager:    stateManager,
+		keyShareStore:   keyShareStore,
+		commStopChan:    make(chan struct{}),
+		p2pComm:         p2pComm,
+	}
+}
+
+func (tReshare *TssReshare) GetTssReshareChannels() chan *p2p.Message {
+	return tReshare.tssCommonStruct.TssMsg
+}
+
+func (tReshare *TssReshare) GetTssCommonStruct() *common2.TssCommon {
+	return tReshare.tssCommonStruct
+}
+
+// Reshare moves the shares of the pool key from the old committee to the new
+// one, the pool key stays the same. localState is the share of this node in
+// the old committee, it is nil when the node only joins the new committee.
+// A node in both committees runs one party for each.
+func (tReshare *TssReshare) Reshare(req Request, localState *storage2.KeygenLocalState) (*bcrypto.ECPoint, error) {
+	isOld := slices.ExistsIgnoreCase(req.OldKeys, tReshare.localNodePubKey)
+	isNew := slices.ExistsIgnoreCase(req.NewKeys, tReshare.localNodePubKey)
+	if isOld && localState == nil {
+		return nil, errors.New("the old committee member has no share to reshare")
+	}
+	if isNew && tReshare.preParams == nil {
+		tReshare.logger.Error().Msg("error, empty pre-parameters")
+		return nil, errors.New("error, empty pre-parameters")
+	}
+
+	oldPartiesID, err := conversion.GetCommittee(req.OldKeys, req.OldEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get old committee parties: %w", err)
+	}
+	newPartiesID, err := conversion.GetCommittee(req.NewKeys, req.NewEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get new committee parties: %w", err)
+	}
+	oldCtx := tss.NewPeerContext(oldPartiesID)
+	newCtx := tss.NewPeerContext(newPartiesID)
+
+	outCh := make(chan tss.Message, 2*(len(oldPartiesID)+len(newPartiesID)))
+	oldEndCh := make(chan keygen.LocalPartySaveData, 1)
+	newEndCh := make(chan keygen.LocalPartySaveData, 1)
+	errChan := make(chan struct{})
+
+	tReshare.localParties = nil
+	if isOld {
+		localPartyID := conversion.FindParty(oldPartiesID, tReshare.localNodePubKey)
+		params := tss.NewReSharingParameters(btcec.S256(), oldCtx, newCtx, localPartyID,
+			len(oldPartiesID), req.OldThreshold, len(newPartiesID), req.NewThreshold)
+		tReshare.localParties = append(tReshare.localParties, resharing.NewLocalParty(params, localState.LocalData, outCh, oldEndCh))
+	}
+	if isNew {
+		localPartyID := conversion.FindParty(newPartiesID, tReshare.localNodePubKey)
+		params := tss.NewReSharingParameters(btcec.S256(), oldCtx, newCtx, localPartyID,
+			len(oldPartiesID), req.OldThreshold, len(newPartiesID), req.NewThreshold)
+		save := keygen.NewLocalPartySaveData(len(newPartiesID))
+		save.LocalPreParams = *tReshare.preParams
+		tReshare.localParties = append(tReshare.localParties, resharing.NewLocalParty(params, save, outCh, newEndCh))
+	}
+	if len(tReshare.localParties) == 0 {
+		return nil, errors.New("local party is not in the old or the new committee")
+	}
+
+	abnormalMgr := tReshare.tssCommonStruct.GetAbnormalMgr()
+	partyIDMap := conversion.SetupPartyIDMap(append(append([]*tss.PartyID{}, oldPartiesID...), newPartiesID...))
+	tReshare.partyIDMap = partyIDMap
+	partyIDtoP2PIDMaps, err := conversion.GeneratePartyIDtoP2PIDMaps(partyIDMap)
+	if err != nil {
+		tReshare.logger.Err(err).Msgf("error in creating mapping between partyID and P2P ID")
+		return nil, err
+	}
+	tReshare.tssCommonStruct.InsertPartyIDtoP2PID(partyIDtoP2PIDMaps)
+	tReshare.tssCommonStruct.SetPartyInfo(&abnormal.PartyInfo{
+		Party:      tReshare.localParties[0],
+		PartyIDMap: partyIDMap,
+	})
+	tReshare.tssCommonStruct.SetLocalParties(tReshare.localParties...)
+	abnormalMgr.SetPartyInfo(tReshare.localParties[0], partyIDMap)
+	tReshare.tssCommonStruct.P2PPeersLock.Lock()
+	tReshare.tssCommonStruct.P2PPeers = conversion.GetPeersID(tReshare.tssCommonStruct.GetPartyIDtoP2PID(), tReshare.tssCommonStruct.GetLocalPeerID())
+	tReshare.tssCommonStruct.P2PPeersLock.Unlock()
+
+	var reshareWg sync.WaitGroup
+	reshareWg.Add(len(tReshare.localParties) + 1)
+	var closeErrChan sync.Once
+	for _, party := range tReshare.localParties {
+		go func(party tss.Party) {
+			defer reshareWg.Done()
+			if err := party.Start(); nil != err {
+				tReshare.logger.Error().Err(err).Msg("fail to start reshare party")
+				closeErrChan.Do(func() { close(errChan) })
+			}
+		}(party)
+	}
+	go tReshare.tssCommonStruct.ProcessInboundMessages(tReshare.commStopChan, &reshareWg)
+
+	r, err := tReshare.processReshare(req, localState, errChan, outCh, oldEndCh, newEndCh)
+	if err != nil {
+		close(tReshare.commStopChan)
+		return nil, fmt.Errorf("fail to process key reshare: %w", err)
+	}
+	select {
+	case <-time.After(time.Second * 5):
+		close(tReshare.commStopChan)
+
+	case <-tReshare.tssCommonStruct.GetTaskDone():
+		close(tReshare.commStopChan)
+	}
+
+	reshareWg.Wait()
+	return r, err
+}
+
+func (tReshare *TssReshare) processReshare(req Request,
+	localState *storage2.KeygenLocalState,
+	errChan chan struct{},
+	outCh <-chan tss.Message,
+	oldEndCh, newEndCh <-chan keygen.LocalPartySaveData) (*bcrypto.ECPoint, error) {
+	defer tReshare.logger.Debug().Msg("finished reshare process")
+	tReshare.logger.Debug().Msg("start to read messages from local parties")
+	tssConf := tReshare.tssCommonStruct.GetConf()
+	abnormalMgr := tReshare.tssCommonStruct.GetAbnormalMgr()
+	pending := len(tReshare.localParties)
+	var newSave *keygen.LocalPartySaveData
+	for pending > 0 {
+		select {
+		case <-errChan: // when a reshare party return
+			tReshare.logger.Error().Msg("key reshare failed")
+			return nil, errors.New("error channel closed fail to start local party")
+
+		case <-tReshare.stopChan: // when TSS processor receive signal to quit
+			return nil, errors.New("received exit signal")
+
+		case <-time.After(tssConf.KeyReshareTimeout):
+			// we bail out after KeyReshareTimeout
+			tReshare.logger.Error().Msgf("fail to reshare message with %s", tssConf.KeyReshareTimeout.String())
+			tReshare.blameWaitingFor()
+			if abnormalMgr.GetLastMsg() == nil {
+				tReshare.logger.Error().Msg("fail to start the reshare, the last produced message of this node is none")
+				return nil, errors.New("timeout before shared message is generated")
+			}
+			return nil, abnormal.ErrTssTimeOut
+
+		case msg := <-outCh:
+			abnormalMgr.SetLastMsg(msg)
+			err := tReshare.tssCommonStruct.ProcessOutCh(msg, messages.TSSKeyReshareMsg)
+			if err != nil {
+				tReshare.logger.Error().Err(err).Msg("fail to process the message")
+				return nil, err
+			}
+
+		case <-oldEndCh:
+			tReshare.logger.Debug().Msg("old committee party finished")
+			pending--
+
+		case msg := <-newEndCh:
+			tReshare.logger.Debug().Msg("new committee party finished")
+			newSave = &msg
+			pending--
+		}
+	}
+
+	if err := tReshare.tssCommonStruct.NotifyTaskDone(); err != nil {
+		tReshare.logger.Error().Err(err).Msg("fail to broadcast the reshare done")
+	}
+
+	var state storage2.KeygenLocalState
+	if newSave != nil {
+		pubKey, _, _, err := conversion.GetTssPubKey(newSave.ECDSAPub)
+		if err != nil {
+			return nil, fmt.Errorf("fail to get the reshared pubkey: %w", err)
+		}
+		if pubKey != req.PoolPubKey {
+			return nil, fmt.Errorf("reshared pool pubkey %s does not match %s", pubKey, req.PoolPubKey)
+		}
+		state = storage2.KeygenLocalState{
+			PubKey:    pubKey,
+			LocalData: *newSave,
+		}
+	} else {
+		// the node leaves the committee, its share is dropped once the new
+		// committee is active so that it can not sign any more
+		retired := keygen.NewLocalPartySaveData(0)
+		retired.LocalPreParams = localState.LocalData.LocalPreParams
+		retired.ECDSAPub = localState.LocalData.ECDSAPub
+		state = storage2.KeygenLocalState{
+			PubKey:    localState.PubKey,
+			LocalData: retired,
+		}
+	}
+	state.ParticipantKeys = req.NewKeys
+	state.LocalPartyKey = tReshare.localNodePubKey
+	state.Threshold = req.NewThreshold
+	state.KeyEpoch = req.NewEpoch
+	// the old committee keeps signing with its shares until the new one is
+	// active on L1, so the new share is only activated then
+	current := localState
+	if current == nil {
+		if stored, err := tReshare.keyShareStore.GetKeyShare(state.PubKey); err == nil {
+			current = &stored
+		}
+	}
+	if err := storage2.PutPendingKeyShare(tReshare.keyShareStore, current, state); err != nil {
+		return nil, fmt.Errorf("fail to save reshare result to key share store: %w", err)
+	}
+
+	address := tReshare.p2pComm.ExportPeerAddress()
+	if err := tReshare.stateManager.SaveAddressBook(address); err != nil {
+		tReshare.logger.Error().Err(err).Msg("fail to save the peer addresses")
+	}
+	return state.LocalData.ECDSAPub, nil
+}
+
+// blameWaitingFor blames the nodes the local parties still wait for
+func (tReshare *TssReshare) blameWaitingFor() {
+	var waitingFor []string
+	for _, party := range tReshare.localParties {
+		for _, partyID := range party.WaitingFor() {
+			waitingFor = append(waitingFor, partyID.Id)
+		}
+	}
+	if len(waitingFor) == 0 {
+		return
+	}
+	pubKeys, err := conversion.AccPubKeysFromPartyIDs(waitingFor, tReshare.partyIDMap)
+	if err != nil {
+		tReshare.logger.Error().Err(err).Msg("fail to get the blamed nodes")
+		return
+	}
+	var blameNodes []*abnormal.Node
+	for _, pubKey := range pubKeys {
+		// a node in both committees can be waited for twice
+		if !containsNode(blameNodes, pubKey) {
+			blameNodes = append(blameNodes, abnormal.NewNode(pubKey, nil, nil))
+		}
+	}
+	tReshare.tssCommonStruct.GetAbnormalMgr().GetAbnormal().SetAbnormal(abnormal.TssTimeout, blameNodes, false)
+}
+
+func containsNode(nodes []*abnormal.Node, pubKey string) bool {
+	for _, node := range nodes {
+		if node.Pubkey == pubKey {
+			return true
+		}
+	}
+	return false
+}
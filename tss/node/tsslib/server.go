@@ -3,6 +3,7 @@ package tsslib
 import (
 	keygen2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/keygen"
 	keysign2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/keysign"
+	reshare2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/reshare"
 )
 
 type Server interface {
@@ -11,6 +12,8 @@ type Server interface {
 	GetLocalPeerID() string
 	Keygen(req keygen2.Request) (keygen2.Response, error)
 	KeySign(req keysign2.Request) (keysign2.Response, error)
+	Reshare(req reshare2.Request) (reshare2.Response, error)
+	ActivateKeyShare(poolPubKey string, keyEpoch uint64) (bool, error)
 	ExportPeerAddress() map[string]string
 	GetParticipants(poolPubkey string) ([]string, error)
 }
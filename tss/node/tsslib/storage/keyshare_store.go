@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	bkeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
+
+	nodeconfig "github.com/mantlenetworkio/mantle/tss/common"
+)
+
+const (
+	// BackendFile keeps the shares as plain json files in the base dir
+	BackendFile = "file"
+	// BackendVaultFile keeps the shares in passphrase encrypted files
+	BackendVaultFile = "vault_file"
+	// BackendVaultKV keeps the shares in a Vault compatible kv v2 http store
+	BackendVaultKV = "vault_kv"
+	// BackendAwsSecrets keeps the shares in one aws secrets manager secret
+	BackendAwsSecrets = "aws_secrets"
+	// BackendAwsShamir splits the shares with shamir to aws s3 and secrets manager
+	BackendAwsShamir = "aws_shamir"
+)
+
+var ErrListNotSupported = errors.New("listing key shares is not supported by the backend")
+
+// KeyShareStore persists the key shares of the pool pub keys the node takes part in
+type KeyShareStore interface {
+	PutKeyShare(state KeygenLocalState) error
+	GetKeyShare(pubKey string) (KeygenLocalState, error)
+	// ListKeyShares returns the pool pub keys of the stored shares
+	ListKeyShares() ([]string, error)
+}
+
+// KeyShareBackend returns the key share backend of the node config, the
+// secrets and shamir enable flags are honored when no backend is configured
+func KeyShareBackend(cfg nodeconfig.NodeConfig) string {
+	switch {
+	case len(cfg.KeyStore.Backend) != 0:
+		return cfg.KeyStore.Backend
+	case cfg.Shamir.Enable:
+		return BackendAwsShamir
+	case cfg.Secrets.Enable:
+		return BackendAwsSecrets
+	default:
+		return BackendFile
+	}
+}
+
+// NewKeyShareStore creates the key share store of the backend
+func NewKeyShareStore(backend string, cfg nodeconfig.NodeConfig, localPartyKey string) (KeyShareStore, error) {
+	switch backend {
+	case BackendFile:
+		return NewFileStateMgr(cfg.BaseDir)
+	case BackendVaultFile:
+		dir := cfg.KeyStore.VaultFile.Dir
+		if len(dir) == 0 {
+			dir = filepath.Join(cfg.BaseDir, "keyshares")
+		}
+		return NewVaultFileStore(dir, cfg.KeyStore.VaultFile.Passphrase)
+	case BackendVaultKV:
+		return NewVaultKVStore(cfg.KeyStore.VaultKV)
+	case BackendAwsSecrets:
+		return NewSecretsMgr(cfg.Secrets.SecretId)
+	case BackendAwsShamir:
+		return NewShamirMgr(cfg.Shamir, localPartyKey)
+	default:
+		return nil, fmt.Errorf("unknown key share backend %s", backend)
+	}
+}
+
+// OnePreParams returns the pre params of any share in the store, nil when there is none
+func OnePreParams(store KeyShareStore) *bkeygen.LocalPreParams {
+	pubKeys, err := store.ListKeyShares()
+	if err != nil {
+		if !errors.Is(err, ErrListNotSupported) {
+			log.Warn().Err(err).Msg("fail to list key shares")
+		}
+		return nil
+	}
+	for _, pubKey := range pubKeys {
+		state, err := store.GetKeyShare(pubKey)
+		if err != nil {
+			log.Warn().Err(err).Msgf("fail to get the key share of %s", pubKey)
+			continue
+		}
+		if state.LocalData.LocalPreParams.Validate() {
+			return &state.LocalData.LocalPreParams
+		}
+	}
+	return nil
+}
+
+// MigrateKeyShares copies the shares of the pub keys from one store to
+// another, all the shares of the source are copied when pubKeys is empty
+func MigrateKeyShares(from, to KeyShareStore, pubKeys []string) ([]string, error) {
+	if len(pubKeys) == 0 {
+		listed, err := from.ListKeyShares()
+		if err != nil {
+			return nil, fmt.Errorf("fail to list the key shares to migrate: %w", err)
+		}
+		pubKeys = listed
+	}
+	migrated := make([]string, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		state, err := from.GetKeyShare(pubKey)
+		if err != nil {
+			return migrated, fmt.Errorf("fail to get the key share of %s: %w", pubKey, err)
+		}
+		if err := to.PutKeyShare(state); err != nil {
+			return migrated, fmt.Errorf("fail to put the key share of %s: %w", pubKey, err)
+		}
+		migrated = append(migrated, pubKey)
+	}
+	return migrated, nil
+}
+
+// PutPendingKeyShare keeps the share dealt at its key epoch next to the share
+// in use, current is the stored state of the pool pub key or nil when the
+// node holds no share of it yet
+func PutPendingKeyShare(store KeyShareStore, current *KeygenLocalState, share KeygenLocalState) error {
+	state := KeygenLocalState{PubKey: share.PubKey}
+	if current != nil {
+		state = *current
+	}
+	pending := make(map[uint64]KeygenLocalState, len(state.PendingShares)+1)
+	for epoch, s := range state.PendingShares {
+		pending[epoch] = s
+	}
+	share.PendingShares = nil
+	pending[share.KeyEpoch] = share
+	state.PendingShares = pending
+	return store.PutKeyShare(state)
+}
+
+// ActivateKeyShare puts the pending share dealt at the key epoch in place of
+// the share in use, the pending shares of older epochs are dropped. It
+// returns false when there is no pending share of the epoch.
+func ActivateKeyShare(store KeyShareStore, pubKey string, keyEpoch uint64) (bool, error) {
+	state, err := store.GetKeyShare(pubKey)
+	if err != nil {
+		return false, err
+	}
+	share, ok := state.PendingShares[keyEpoch]
+	if !ok {
+		return false, nil
+	}
+	for epoch, pending := range state.PendingShares {
+		if epoch > keyEpoch {
+			if share.PendingShares == nil {
+				share.PendingShares = make(map[uint64]KeygenLocalState)
+			}
+			share.PendingShares[epoch] = pending
+		}
+	}
+	if err := store.PutKeyShare(share); err != nil {
+		return false, fmt.Errorf("fail to activate the key share of %s: %w", pubKey, err)
+	}
+	return true, nil
+}
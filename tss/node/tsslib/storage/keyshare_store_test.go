@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	nodeconfig "github.com/mantlenetworkio/mantle/tss/common"
+)
+
+func newKeyShare(t *testing.T) KeygenLocalState {
+	priKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return KeygenLocalState{
+		PubKey:          hex.EncodeToString(crypto.CompressPubkey(&priKey.PublicKey)),
+		ParticipantKeys: []string{"a", "b", "c"},
+		LocalPartyKey:   "a",
+		Threshold:       1,
+		KeyEpoch:        3,
+	}
+}
+
+func TestVaultFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewVaultFileStore(dir, "passphrase")
+	require.NoError(t, err)
+	store.logN = 10
+
+	state := newKeyShare(t)
+	require.NoError(t, store.PutKeyShare(state))
+	got, err := store.GetKeyShare(state.PubKey)
+	require.NoError(t, err)
+	require.Equal(t, state.ParticipantKeys, got.ParticipantKeys)
+	require.EqualValues(t, 3, got.KeyEpoch)
+
+	pubKeys, err := store.ListKeyShares()
+	require.NoError(t, err)
+	require.Equal(t, []string{state.PubKey}, pubKeys)
+
+	// the share is not readable without the passphrase
+	wrong, err := NewVaultFileStore(dir, "wrong")
+	require.NoError(t, err)
+	_, err = wrong.GetKeyShare(state.PubKey)
+	require.ErrorContains(t, err, "wrong passphrase")
+
+	_, err = NewVaultFileStore(dir, "")
+	require.Error(t, err)
+}
+
+// mockVaultKV serves the kv version 2 api of a Vault server from memory
+func mockVaultKV(t *testing.T, token string) *httptest.Server {
+	secrets := make(map[string]json.RawMessage)
+	lock := &sync.Mutex{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == "LIST" && r.URL.Path == "/v1/secret/metadata/tss":
+			keys := make([]string, 0)
+			for key := range secrets {
+				keys = append(keys, key)
+			}
+			if len(keys) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+		case strings.HasPrefix(r.URL.Path, "/v1/secret/data/tss/"):
+			key := strings.TrimPrefix(r.URL.Path, "/v1/secret/data/tss/")
+			if r.Method == http.MethodPost {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				var req struct {
+					Data json.RawMessage `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(body, &req))
+				secrets[key] = req.Data
+				return
+			}
+			data, ok := secrets[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": data}})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestVaultKVStore(t *testing.T) {
+	server := mockVaultKV(t, "token")
+	defer server.Close()
+
+	store, err := NewVaultKVStore(nodeconfig.VaultKVConfig{
+		Address: server.URL,
+		Token:   "token",
+		Mount:   "secret",
+		Path:    "tss",
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	pubKeys, err := store.ListKeyShares()
+	require.NoError(t, err)
+	require.Empty(t, pubKeys)
+
+	state := newKeyShare(t)
+	_, err = store.GetKeyShare(state.PubKey)
+	require.Error(t, err)
+
+	require.NoError(t, store.PutKeyShare(state))
+	got, err := store.GetKeyShare(state.PubKey)
+	require.NoError(t, err)
+	require.Equal(t, state.PubKey, got.PubKey)
+	require.Equal(t, state.Threshold, got.Threshold)
+
+	pubKeys, err = store.ListKeyShares()
+	require.NoError(t, err)
+	require.Equal(t, []string{state.PubKey}, pubKeys)
+
+	unauthorized, err := NewVaultKVStore(nodeconfig.VaultKVConfig{Address: server.URL, Mount: "secret", Path: "tss"})
+	require.NoError(t, err)
+	_, err = unauthorized.GetKeyShare(state.PubKey)
+	require.ErrorContains(t, err, "status 403")
+}
+
+func TestMigrateKeyShares(t *testing.T) {
+	from, err := NewFileStateMgr(t.TempDir())
+	require.NoError(t, err)
+	to, err := NewVaultFileStore(t.TempDir(), "passphrase")
+	require.NoError(t, err)
+	to.logN = 10
+
+	first, second := newKeyShare(t), newKeyShare(t)
+	require.NoError(t, from.PutKeyShare(first))
+	require.NoError(t, from.PutKeyShare(second))
+
+	migrated, err := MigrateKeyShares(from, to, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{first.PubKey, second.PubKey}, migrated)
+	for _, pubKey := range migrated {
+		state, err := to.GetKeyShare(pubKey)
+		require.NoError(t, err)
+		require.Equal(t, pubKey, state.PubKey)
+	}
+
+	_, err = MigrateKeyShares(from, to, []string{"missing"})
+	require.Error(t, err)
+}
+
+func TestActivateKeyShare(t *testing.T) {
+	store, err := NewFileStateMgr(t.TempDir())
+	require.NoError(t, err)
+
+	current := newKeyShare(t)
+	require.NoError(t, store.PutKeyShare(current))
+
+	next := current
+	next.ParticipantKeys = []string{"b", "c", "d"}
+	next.KeyEpoch = 4
+	require.NoError(t, PutPendingKeyShare(store, &current, next))
+
+	// the share in use is kept until the committee of the epoch is active
+	state, err := store.GetKeyShare(current.PubKey)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), state.KeyEpoch)
+	require.Equal(t, current.ParticipantKeys, state.ParticipantKeys)
+	require.Contains(t, state.PendingShares, uint64(4))
+
+	activated, err := ActivateKeyShare(store, current.PubKey, 3)
+	require.NoError(t, err)
+	require.False(t, activated)
+
+	activated, err = ActivateKeyShare(store, current.PubKey, 4)
+	require.NoError(t, err)
+	require.True(t, activated)
+	state, err = store.GetKeyShare(current.PubKey)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), state.KeyEpoch)
+	require.Equal(t, next.ParticipantKeys, state.ParticipantKeys)
+	require.Empty(t, state.PendingShares)
+
+	// a node joining the committee holds no share before
+	joined := newKeyShare(t)
+	require.NoError(t, PutPendingKeyShare(store, nil, joined))
+	state, err = store.GetKeyShare(joined.PubKey)
+	require.NoError(t, err)
+	require.Empty(t, state.ParticipantKeys)
+	activated, err = ActivateKeyShare(store, joined.PubKey, joined.KeyEpoch)
+	require.NoError(t, err)
+	require.True(t, activated)
+	state, err = store.GetKeyShare(joined.PubKey)
+	require.NoError(t, err)
+	require.Equal(t, joined.ParticipantKeys, state.ParticipantKeys)
+}
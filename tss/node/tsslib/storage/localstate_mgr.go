@@ -30,6 +30,13 @@ type KeygenLocalState struct {
 	ParticipantKeys []string                  `json:"participant_keys"` // the paticipant of last key gen
 	LocalPartyKey   string                    `json:"local_party_key"`
 	Threshold       int                       `json:"threshold"`
+	// KeyEpoch is the epoch the share was dealt at, 0 for keygen and the
+	// election id for a reshare
+	KeyEpoch uint64 `json:"key_epoch"`
+	// PendingShares are the shares dealt by reshares to committees that are
+	// not active on L1 yet, keyed by their key epoch. The share above stays
+	// in use until ActivateKeyShare puts the pending one in its place.
+	PendingShares map[uint64]KeygenLocalState `json:"pending_shares,omitempty"`
 }
 
 type LocalStateManager interface {
@@ -130,6 +137,31 @@ func (fsm *FileStateMgr) GetLocalState(pubKey string) (KeygenLocalState, error)
 	return localState, nil
 }
 
+func (fsm *FileStateMgr) PutKeyShare(state KeygenLocalState) error {
+	return fsm.SaveLocalState(state)
+}
+
+func (fsm *FileStateMgr) GetKeyShare(pubKey string) (KeygenLocalState, error) {
+	return fsm.GetLocalState(pubKey)
+}
+
+func (fsm *FileStateMgr) ListKeyShares() ([]string, error) {
+	var pattern = "localstate-*.json"
+	if len(fsm.folder) > 0 {
+		pattern = filepath.Join(fsm.folder, pattern)
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	pubKeys := make([]string, 0, len(files))
+	for _, file := range files {
+		name := filepath.Base(file)
+		pubKeys = append(pubKeys, strings.TrimSuffix(strings.TrimPrefix(name, "localstate-"), ".json"))
+	}
+	return pubKeys, nil
+}
+
 func (fsm *FileStateMgr) GetOneLocalPreParams() (*keygen.LocalPreParams, error) {
 	filePathName, err := fsm.getOneFilePathName()
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -61,11 +62,10 @@ func NewSecretsMgr(secretId string) (*SecretsMgr, error) {
 
 }
 
+// PutKeyFile replaces the key file of the pool pub key, a reshare keeps the
+// pool pub key but deals new shares
 func (sm *SecretsMgr) PutKeyFile(stat KeygenLocalState) error {
-	_, ok := sm.keys[stat.PubKey]
-	if !ok {
-		sm.keys[stat.PubKey] = stat
-	}
+	sm.keys[stat.PubKey] = stat
 	return nil
 }
 
@@ -88,7 +88,7 @@ func (sm *SecretsMgr) Save() error {
 		log.Error().Err(err).Msgf("fail to put data to aws's secrets manager : %v", err)
 		return err
 	}
-	log.Info().Msgf("put data to aws's secrets manager success, version is:%s", output.VersionId)
+	log.Info().Msgf("put data to aws's secrets manager success, version is:%s", aws.ToString(output.VersionId))
 	return nil
 }
 
@@ -101,6 +101,29 @@ func (sm *SecretsMgr) GetKeyFile(pubKey string) (KeygenLocalState, error) {
 	return value, nil
 }
 
+func (sm *SecretsMgr) PutKeyShare(state KeygenLocalState) error {
+	if err := sm.PutKeyFile(state); err != nil {
+		return err
+	}
+	return sm.Save()
+}
+
+func (sm *SecretsMgr) GetKeyShare(pubKey string) (KeygenLocalState, error) {
+	value, ok := sm.keys[pubKey]
+	if !ok {
+		return KeygenLocalState{}, fmt.Errorf("can not find the key share of %s in secrets manager", pubKey)
+	}
+	return value, nil
+}
+
+func (sm *SecretsMgr) ListKeyShares() ([]string, error) {
+	pubKeys := make([]string, 0, len(sm.keys))
+	for pubKey := range sm.keys {
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys, nil
+}
+
 func (sm *SecretsMgr) GetOneLocalState() *bkeygen.LocalPreParams {
 	var preParams *bkeygen.LocalPreParams
 	if len(sm.keys) > 0 {
@@ -39,8 +39,9 @@ type ShamirManager interface {
 
 type (
 	ShamirMgr struct {
-		shamirConfig nodeconfig.ShamirConfig
-		keys         map[string]KeygenLocalState
+		shamirConfig  nodeconfig.ShamirConfig
+		localPartyKey string
+		keys          map[string]KeygenLocalState
 	}
 
 	Share struct {
@@ -49,26 +50,39 @@ type (
 	}
 )
 
-func NewShamirMgr(config nodeconfig.ShamirConfig) (*ShamirMgr, error) {
+func NewShamirMgr(config nodeconfig.ShamirConfig, localPartyKey string) (*ShamirMgr, error) {
 	log.Debug().Msg("create shamir instance ")
 	var keys = map[string]KeygenLocalState{}
 	return &ShamirMgr{
-		shamirConfig: config,
-		keys:         keys,
+		shamirConfig:  config,
+		localPartyKey: localPartyKey,
+		keys:          keys,
 	}, nil
 
 }
 
+func (sh *ShamirMgr) PutKeyShare(state KeygenLocalState) error {
+	return sh.PutKeyFile(state)
+}
+
+func (sh *ShamirMgr) GetKeyShare(pubKey string) (KeygenLocalState, error) {
+	return sh.GetKeyFile(pubKey, sh.localPartyKey)
+}
+
+// ListKeyShares is not supported, the shares are spread over s3 and secrets
+// manager under keys derived from the pool pub key
+func (sh *ShamirMgr) ListKeyShares() ([]string, error) {
+	return nil, ErrListNotSupported
+}
+
 func (sh *ShamirMgr) PutKeyFile(stat KeygenLocalState) error {
 	log.Info().Msg("start to storage new keygen ")
-	_, ok := sh.keys[stat.PubKey]
-	if !ok {
-		sh.keys[stat.PubKey] = stat
-		err := sh.SaveEncrypt(stat)
-		if err != nil {
-			log.Error().Err(err).Msg("put key file failed")
-			return err
-		}
+	// a reshare keeps the pool pub key but deals new shares
+	sh.keys[stat.PubKey] = stat
+	err := sh.SaveEncrypt(stat)
+	if err != nil {
+		log.Error().Err(err).Msg("put key file failed")
+		return err
 	}
 	return nil
 }
@@ -461,7 +475,7 @@ func getFromS3(bucket, filename string, downloader s3manager.Downloader) ([]byte
 		log.Error().Err(err).Msgf("Ubable to download file %q, %v", filename, err)
 		return nil, err
 	}
-	log.Info().Msgf("Downloaded", filename, numBytes, "bytes")
+	log.Info().Msgf("Downloaded %q, %d bytes", filename, numBytes)
 	return file.Bytes(), nil
 
 }
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/conversion"
+)
+
+const (
+	vaultFileVersion = 1
+	vaultFilePrefix  = "keyshare-"
+	vaultFileSuffix  = ".json"
+
+	// scrypt parameters of the passphrase, the same work factor age uses
+	scryptLogN   = 18
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// vaultFile is the on disk format of an encrypted key share, the pool pub key
+// is authenticated as additional data so that files can not be swapped
+type vaultFile struct {
+	Version    int    `json:"version"`
+	PubKey     string `json:"pub_key"`
+	LogN       int    `json:"log_n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// VaultFileStore keeps every key share in its own file encrypted with a key
+// derived from the passphrase by scrypt
+type VaultFileStore struct {
+	dir        string
+	passphrase []byte
+	logN       int
+	lock       *sync.RWMutex
+}
+
+func NewVaultFileStore(dir, passphrase string) (*VaultFileStore, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("the passphrase of the key share vault is empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("fail to create the key share vault dir: %w", err)
+	}
+	return &VaultFileStore{
+		dir:        dir,
+		passphrase: []byte(passphrase),
+		logN:       scryptLogN,
+		lock:       &sync.RWMutex{},
+	}, nil
+}
+
+func (vs *VaultFileStore) filePathName(pubKey string) (string, error) {
+	ret, err := conversion.CheckKeyOnCurve(pubKey)
+	if err != nil {
+		return "", err
+	}
+	if !ret {
+		return "", errors.New("invalid pubkey for file name")
+	}
+	return filepath.Join(vs.dir, vaultFilePrefix+pubKey+vaultFileSuffix), nil
+}
+
+func (vs *VaultFileStore) PutKeyShare(state KeygenLocalState) error {
+	filePathName, err := vs.filePathName(state.PubKey)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("fail to marshal KeygenLocalState to json: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	aead, err := vs.aead(salt, vs.logN, scryptR, scryptP)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(vaultFile{
+		Version:    vaultFileVersion,
+		PubKey:     state.PubKey,
+		LogN:       vs.logN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(state.PubKey)),
+	})
+	if err != nil {
+		return err
+	}
+
+	vs.lock.Lock()
+	defer vs.lock.Unlock()
+	// write to a temporary file first so that a crash never leaves a torn share
+	tmpFile := filePathName + ".tmp"
+	if err := os.WriteFile(tmpFile, buf, 0o600); err != nil {
+		return fmt.Errorf("fail to write the key share file: %w", err)
+	}
+	return os.Rename(tmpFile, filePathName)
+}
+
+func (vs *VaultFileStore) GetKeyShare(pubKey string) (KeygenLocalState, error) {
+	filePathName, err := vs.filePathName(pubKey)
+	if err != nil {
+		return KeygenLocalState{}, err
+	}
+	vs.lock.RLock()
+	buf, err := os.ReadFile(filePathName)
+	vs.lock.RUnlock()
+	if err != nil {
+		return KeygenLocalState{}, err
+	}
+	var file vaultFile
+	if err := json.Unmarshal(buf, &file); err != nil {
+		return KeygenLocalState{}, fmt.Errorf("fail to unmarshal the key share file: %w", err)
+	}
+	if file.Version != vaultFileVersion {
+		return KeygenLocalState{}, fmt.Errorf("unsupported key share file version %d", file.Version)
+	}
+	aead, err := vs.aead(file.Salt, file.LogN, file.R, file.P)
+	if err != nil {
+		return KeygenLocalState{}, err
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, []byte(pubKey))
+	if err != nil {
+		return KeygenLocalState{}, errors.New("fail to decrypt the key share, wrong passphrase or corrupted file")
+	}
+	var state KeygenLocalState
+	if err := json.Unmarshal(plaintext, &state); err != nil {
+		return KeygenLocalState{}, fmt.Errorf("fail to unmarshal KeygenLocalState: %w", err)
+	}
+	return state, nil
+}
+
+func (vs *VaultFileStore) ListKeyShares() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(vs.dir, vaultFilePrefix+"*"+vaultFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	pubKeys := make([]string, 0, len(files))
+	for _, file := range files {
+		name := filepath.Base(file)
+		pubKeys = append(pubKeys, strings.TrimSuffix(strings.TrimPrefix(name, vaultFilePrefix), vaultFileSuffix))
+	}
+	return pubKeys, nil
+}
+
+func (vs *VaultFileStore) aead(salt []byte, logN, r, p int) (cipher.AEAD, error) {
+	if logN <= 0 || logN > 22 {
+		return nil, fmt.Errorf("invalid scrypt work factor %d", logN)
+	}
+	key, err := scrypt.Key(vs.passphrase, salt, 1<<logN, r, p, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("fail to derive the key share vault key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	nodeconfig "github.com/mantlenetworkio/mantle/tss/common"
+)
+
+var errVaultKVNotFound = errors.New("not found in vault kv store")
+
+// VaultKVStore keeps the key shares in the kv version 2 secrets engine of a
+// Vault compatible http server, one secret per pool pub key
+type VaultKVStore struct {
+	address string
+	token   string
+	mount   string
+	path    string
+	client  *http.Client
+}
+
+func NewVaultKVStore(config nodeconfig.VaultKVConfig) (*VaultKVStore, error) {
+	if len(config.Address) == 0 {
+		return nil, errors.New("the address of the vault kv store is empty")
+	}
+	if _, err := url.Parse(config.Address); err != nil {
+		return nil, fmt.Errorf("invalid vault kv store address: %w", err)
+	}
+	if len(config.Mount) == 0 {
+		return nil, errors.New("the mount of the vault kv store is empty")
+	}
+	return &VaultKVStore{
+		address: strings.TrimSuffix(config.Address, "/"),
+		token:   config.Token,
+		mount:   strings.Trim(config.Mount, "/"),
+		path:    strings.Trim(config.Path, "/"),
+		client:  &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+func (vs *VaultKVStore) url(kind, pubKey string) string {
+	parts := []string{vs.address, "v1", vs.mount, kind}
+	if len(vs.path) > 0 {
+		parts = append(parts, vs.path)
+	}
+	if len(pubKey) > 0 {
+		parts = append(parts, url.PathEscape(pubKey))
+	}
+	return strings.Join(parts, "/")
+}
+
+func (vs *VaultKVStore) do(method, url string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), method, url, reader)
+	if err != nil {
+		return err
+	}
+	if len(vs.token) > 0 {
+		req.Header.Set("X-Vault-Token", vs.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := vs.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fail to request vault kv store: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errVaultKVNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("vault kv store returns status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (vs *VaultKVStore) PutKeyShare(state KeygenLocalState) error {
+	if len(state.PubKey) == 0 {
+		return errors.New("pub key is empty")
+	}
+	body := struct {
+		Data KeygenLocalState `json:"data"`
+	}{Data: state}
+	if err := vs.do(http.MethodPost, vs.url("data", state.PubKey), body, nil); err != nil {
+		return fmt.Errorf("fail to put the key share of %s: %w", state.PubKey, err)
+	}
+	return nil
+}
+
+func (vs *VaultKVStore) GetKeyShare(pubKey string) (KeygenLocalState, error) {
+	if len(pubKey) == 0 {
+		return KeygenLocalState{}, errors.New("pub key is empty")
+	}
+	var result struct {
+		Data struct {
+			Data *KeygenLocalState `json:"data"`
+		} `json:"data"`
+	}
+	if err := vs.do(http.MethodGet, vs.url("data", pubKey), nil, &result); err != nil {
+		return KeygenLocalState{}, fmt.Errorf("fail to get the key share of %s: %w", pubKey, err)
+	}
+	// a deleted secret version has no data
+	if result.Data.Data == nil {
+		return KeygenLocalState{}, fmt.Errorf("fail to get the key share of %s: %w", pubKey, errVaultKVNotFound)
+	}
+	return *result.Data.Data, nil
+}
+
+func (vs *VaultKVStore) ListKeyShares() ([]string, error) {
+	var result struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	err := vs.do("LIST", vs.url("metadata", ""), nil, &result)
+	if errors.Is(err, errVaultKVNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fail to list the key shares: %w", err)
+	}
+	pubKeys := make([]string, 0, len(result.Data.Keys))
+	for _, key := range result.Data.Keys {
+		// keys ending with a slash are folders
+		if !strings.HasSuffix(key, "/") {
+			pubKeys = append(pubKeys, key)
+		}
+	}
+	return pubKeys, nil
+}
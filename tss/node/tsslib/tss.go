@@ -5,7 +5,6 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 	"sync"
@@ -17,13 +16,13 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mantlenetworkio/mantle/l2geth/crypto"
-	tssconfig "github.com/mantlenetworkio/mantle/tss/common"
 	common2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/common"
 	"github.com/mantlenetworkio/mantle/tss/node/tsslib/conversion"
 	"github.com/mantlenetworkio/mantle/tss/node/tsslib/keygen"
 	"github.com/mantlenetworkio/mantle/tss/node/tsslib/keysign"
 	"github.com/mantlenetworkio/mantle/tss/node/tsslib/monitor"
 	p2p2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/p2p"
+	"github.com/mantlenetworkio/mantle/tss/node/tsslib/reshare"
 	storage2 "github.com/mantlenetworkio/mantle/tss/node/tsslib/storage"
 	"github.com/mantlenetworkio/mantle/tss/node/types"
 )
@@ -40,12 +39,9 @@ type TssServer struct {
 	tssKeyGenLocker     *sync.Mutex
 	stopChan            chan struct{}
 	stateManager        storage2.LocalStateManager
-	secretsManager      storage2.SecretsManager
-	shamirManager       storage2.ShamirManager
+	keyShareStore       storage2.KeyShareStore
 	privateKey          *ecdsa.PrivateKey
 	tssMetrics          *monitor.Metric
-	secretsEnable       bool
-	shamirEnable        bool
 	tssGroupMemberStore types.TssMemberStore
 }
 
@@ -58,9 +54,7 @@ func NewTss(
 	conf common2.TssConfig,
 	preParamsFile string,
 	externalIP string,
-	secretsEnable bool,
-	secretId string,
-	shamirConfig tssconfig.ShamirConfig,
+	keyShareStore storage2.KeyShareStore,
 	store types.TssMemberStore,
 ) (*TssServer, error) {
 
@@ -83,21 +77,6 @@ func NewTss(
 	if err != nil {
 		return nil, errors.New("fail to create file state manager")
 	}
-	var secretsManager *storage2.SecretsMgr
-	var shamirManager *storage2.ShamirMgr
-	if shamirConfig.Enable {
-		shamirManager, err = storage2.NewShamirMgr(shamirConfig)
-		if err != nil {
-			log.Error().Err(err).Msgf("fail to create shamir manager :%v", err)
-			return nil, errors.New("fail to create shamir manager")
-		}
-	} else if secretsEnable {
-		secretsManager, err = storage2.NewSecretsMgr(secretId)
-		if err != nil {
-			log.Error().Err(err).Msgf("fail to create secrets manager :%v", err)
-			return nil, errors.New("fail to create secrets manager")
-		}
-	}
 	cmdBootstrapPeerS := strings.Split(cmdBootstrapPeers, ",")
 	var bootstrapPeers p2p2.AddrList
 	savedPeers, err := stateManager.RetrieveP2PAddresses()
@@ -129,34 +108,14 @@ func NewTss(
 		if err != nil {
 			return nil, fmt.Errorf("fail to generate pre parameters: %w", err)
 		}
-	} else if shamirConfig.Enable {
-		preParams = shamirManager.GetOneLocalState()
-		if preParams == nil {
-			log.Info().Msg("start to generate pre params...")
-			preParams, err = bkeygen.GeneratePreParams(conf.PreParamTimeout)
-			if err != nil {
-				return nil, fmt.Errorf("fail to generate pre parameters: %w", err)
-			}
-		}
-	} else if secretsEnable {
-		preParams = secretsManager.GetOneLocalState()
-		if preParams == nil {
-			log.Info().Msg("start to generate pre params...")
-			preParams, err = bkeygen.GeneratePreParams(conf.PreParamTimeout)
-			if err != nil {
-				return nil, fmt.Errorf("fail to generate pre parameters: %w", err)
-			}
-		}
 	} else {
-		preParams, err = stateManager.GetOneLocalPreParams()
-		if err != nil && os.IsNotExist(err) {
+		preParams = storage2.OnePreParams(keyShareStore)
+		if preParams == nil {
 			log.Info().Msg("start to generate pre params...")
 			preParams, err = bkeygen.GeneratePreParams(conf.PreParamTimeout)
 			if err != nil {
 				return nil, fmt.Errorf("fail to generate pre parameters: %w", err)
 			}
-		} else if err != nil {
-			return nil, err
 		}
 	}
 
@@ -182,12 +141,9 @@ func NewTss(
 		tssKeyGenLocker:     &sync.Mutex{},
 		stopChan:            make(chan struct{}),
 		stateManager:        stateManager,
-		secretsManager:      secretsManager,
-		shamirManager:       shamirManager,
+		keyShareStore:       keyShareStore,
 		privateKey:          priKey,
 		tssMetrics:          metrics,
-		secretsEnable:       secretsEnable,
-		shamirEnable:        shamirConfig.Enable,
 		tssGroupMemberStore: store,
 	}
 
@@ -224,6 +180,9 @@ func (t *TssServer) requestToMsgId(request interface{}) (string, error) {
 	case keysign.Request:
 		dat = value.Message
 		keys = value.SignerPubKeys
+	case reshare.Request:
+		dat = []byte(fmt.Sprintf("%s-%d-%d", value.PoolPubKey, value.OldEpoch, value.NewEpoch))
+		keys = append(append([]string{}, value.OldKeys...), value.NewKeys...)
 	default:
 		t.logger.Error().Msg("unknown request type")
 		return "", errors.New("unknown request type")
@@ -275,6 +234,21 @@ func (t *TssServer) requestCheck(request interface{}) error {
 			return errors.New("not active signer")
 		}
 
+	case reshare.Request:
+		if len(value.PoolPubKey) != poolPublicKey {
+			return errors.New("the length of the pool public key is not 66, " + value.PoolPubKey)
+		}
+		if len(value.OldKeys) <= value.OldThreshold || len(value.NewKeys) <= value.NewThreshold {
+			t.logger.Error().Msg("check params : pub_keys size is smaller than threshold !")
+			return errors.New("check params : pub_keys size is smaller than threshold")
+		}
+		if value.NewEpoch <= value.OldEpoch {
+			return errors.New("the new key epoch has to be greater than the old one")
+		}
+		if !t.isPartOfKeysignParty(value.OldKeys) && !t.isPartOfKeysignParty(value.NewKeys) {
+			return errors.New("not a member of the old or the new committee")
+		}
+
 	default:
 		t.logger.Error().Msg("unknown request type")
 		return errors.New("unknown request type")
@@ -283,6 +257,15 @@ func (t *TssServer) requestCheck(request interface{}) error {
 
 }
 
+// getLocalState loads the share of the pool pub key from the key share store
+func (t *TssServer) getLocalState(poolPubKey string) (storage2.KeygenLocalState, error) {
+	localStateItem, err := t.keyShareStore.GetKeyShare(poolPubKey)
+	if err != nil {
+		return storage2.KeygenLocalState{}, fmt.Errorf("fail to get local keygen state from key share store: %w", err)
+	}
+	return localStateItem, nil
+}
+
 func (t *TssServer) ExportPeerAddress() map[string]string {
 	ret := make(map[string]string)
 	rs := t.p2pCommunication.ExportPeerAddress()
@@ -0,0 +1,55 @@
+package slash
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NodeLiveness is the signed batches window of a node as operators see it
+type NodeLiveness struct {
+	SigningInfo
+	Window    uint64 `json:"window"`
+	MaxMissed uint64 `json:"max_missed"`
+	// Missed holds the tracked batches of the window, oldest first
+	Missed []bool `json:"missed"`
+	// RemainingMisses is how many more batches the node may miss before it
+	// is slashed
+	RemainingMisses uint64 `json:"remaining_misses"`
+}
+
+func (s Slashing) Liveness(address common.Address) (NodeLiveness, bool) {
+	found, signingInfo := s.slashingStore.GetSigningInfo(address)
+	if !found {
+		return NodeLiveness{}, false
+	}
+	return s.liveness(signingInfo), true
+}
+
+func (s Slashing) ListLiveness() []NodeLiveness {
+	signingInfos := s.slashingStore.ListSigningInfo()
+	livenesses := make([]NodeLiveness, 0, len(signingInfos))
+	for _, signingInfo := range signingInfos {
+		livenesses = append(livenesses, s.liveness(signingInfo))
+	}
+	return livenesses
+}
+
+func (s Slashing) liveness(signingInfo SigningInfo) NodeLiveness {
+	window := s.params.SignedBatchesWindow
+	nl := NodeLiveness{
+		SigningInfo: signingInfo,
+		Window:      window,
+		MaxMissed:   s.params.MaxMissed(),
+	}
+	tracked := signingInfo.IndexOffset
+	if tracked > window {
+		tracked = window
+	}
+	nl.Missed = make([]bool, 0, tracked)
+	for offset := signingInfo.IndexOffset - tracked; offset < signingInfo.IndexOffset; offset++ {
+		nl.Missed = append(nl.Missed, s.slashingStore.GetNodeMissedBatchBitArray(signingInfo.Address, offset%window))
+	}
+	if nl.MaxMissed > signingInfo.MissedBlocksCounter {
+		nl.RemainingMisses = nl.MaxMissed - signingInfo.MissedBlocksCounter
+	}
+	return nl
+}
@@ -2,26 +2,49 @@ package slash
 
 import (
 	"errors"
+	"math"
 
 	"github.com/ethereum/go-ethereum/common"
-	
+
 	"github.com/mantlenetworkio/mantle/l2geth/common/hexutil"
 	tss "github.com/mantlenetworkio/mantle/tss/common"
 	"github.com/mantlenetworkio/mantle/tss/index"
 )
 
+// Params is the liveness rule: a node has to sign at least
+// MinSignedPerWindow of the last SignedBatchesWindow state batches
+type Params struct {
+	SignedBatchesWindow uint64
+	MinSignedPerWindow  float64
+	// JailDuration is the number of batches a slashed node is not tracked
+	// for, so that it is not slashed again for the same downtime
+	JailDuration uint64
+}
+
+// MaxMissed is the number of batches a node may miss in a window
+func (p Params) MaxMissed() uint64 {
+	minSigned := uint64(math.Ceil(float64(p.SignedBatchesWindow) * p.MinSignedPerWindow))
+	if minSigned > p.SignedBatchesWindow {
+		return 0
+	}
+	return p.SignedBatchesWindow - minSigned
+}
+
 type Slashing struct {
 	stateBatchStore index.StateBatchStore
 	slashingStore   SlashingStore
 
-	missSignedNumber int
+	params Params
 }
 
-func NewSlashing(sbs index.StateBatchStore, ss SlashingStore, missSignedNumber int) Slashing {
+func NewSlashing(sbs index.StateBatchStore, ss SlashingStore, params Params) Slashing {
+	if params.SignedBatchesWindow == 0 {
+		params.SignedBatchesWindow = 1
+	}
 	return Slashing{
-		stateBatchStore:  sbs,
-		slashingStore:    ss,
-		missSignedNumber: missSignedNumber,
+		stateBatchStore: sbs,
+		slashingStore:   ss,
+		params:          params,
 	}
 }
 
@@ -31,23 +54,13 @@ func (s Slashing) AfterStateBatchIndexed(root [32]byte) error {
 		return errors.New("can not find the state batch with root: " + hexutil.Encode(root[:]))
 	}
 
-	// check whether it is a new round election
-	var electionAdvanced bool
-	if stateBatch.ElectionId > 1 {
-		found, previousBatchRoot := s.stateBatchStore.GetIndexStateBatch(stateBatch.BatchIndex - 1)
-		if found {
-			_, previousStateBatch := s.stateBatchStore.GetStateBatch(previousBatchRoot)
-			electionAdvanced = stateBatch.ElectionId != previousStateBatch.ElectionId
-		}
-	}
-
 	// update signingInfo for working nodes
 	for _, workingNode := range stateBatch.WorkingNodes {
 		address, err := tss.NodeToAddress(workingNode)
 		if err != nil {
 			return err
 		}
-		s.UpdateSigningInfo(address, electionAdvanced, false)
+		s.HandleNodeBatch(address, stateBatch, false)
 	}
 
 	// update signingInfo for absent nodes
@@ -56,48 +69,77 @@ func (s Slashing) AfterStateBatchIndexed(root [32]byte) error {
 		if err != nil {
 			return err
 		}
-		updatedSigningInfo := s.UpdateSigningInfo(address, electionAdvanced, true)
-		if updatedSigningInfo.MissedBlocksCounter > uint64(s.missSignedNumber) {
-			s.slashingStore.SetSlashingInfo(SlashingInfo{
-				Address:    address,
-				ElectionId: stateBatch.ElectionId,
-				BatchIndex: stateBatch.BatchIndex,
-				SlashType:  tss.SlashTypeLiveness,
-			})
-			updatedSigningInfo.MissedBlocksCounter = 0
-			s.slashingStore.SetSigningInfo(updatedSigningInfo)
-		}
+		s.HandleNodeBatch(address, stateBatch, true)
 	}
 
 	return nil
 }
 
-func (s Slashing) UpdateSigningInfo(address common.Address, electionAdvanced, missed bool) SigningInfo {
-	if electionAdvanced {
-		return s.InitializeSigningInfo(address, missed)
+// HandleNodeBatch records whether the node missed the state batch and
+// slashes it once it missed more than MaxMissed batches of a full window
+func (s Slashing) HandleNodeBatch(address common.Address, stateBatch index.StateBatchInfo, missed bool) SigningInfo {
+	found, signingInfo := s.slashingStore.GetSigningInfo(address)
+	switch {
+	case !found:
+		signingInfo = s.InitializeSigningInfo(address, stateBatch.ElectionId, stateBatch.BatchIndex, 0)
+	case signingInfo.StartBatchIndex+signingInfo.IndexOffset < stateBatch.BatchIndex:
+		// the node was not tracked for the batches in between, it left the
+		// committee and joins again with a clean window, but a node slashed
+		// before stays jailed
+		signingInfo = s.InitializeSigningInfo(address, stateBatch.ElectionId, stateBatch.BatchIndex, signingInfo.JailedUntil)
+	case signingInfo.ElectionId != stateBatch.ElectionId:
+		// a node that remains a member keeps its window across elections
+		signingInfo.ElectionId = stateBatch.ElectionId
+		s.slashingStore.SetSigningInfo(signingInfo)
+	}
+	if stateBatch.BatchIndex < signingInfo.JailedUntil {
+		return signingInfo
 	}
 
-	found, signingInfo := s.slashingStore.GetSigningInfo(address)
-	if !found {
-		signingInfo = s.InitializeSigningInfo(address, missed)
-	} else {
-		if missed {
-			signingInfo.MissedBlocksCounter++
-		}
+	window := s.params.SignedBatchesWindow
+	position := signingInfo.IndexOffset % window
+	signingInfo.IndexOffset++
+	previous := s.slashingStore.GetNodeMissedBatchBitArray(address, position)
+	switch {
+	case !previous && missed:
+		s.slashingStore.SetNodeMissedBatchBitArray(address, position, true)
+		signingInfo.MissedBlocksCounter++
+	case previous && !missed:
+		s.slashingStore.SetNodeMissedBatchBitArray(address, position, false)
+		signingInfo.MissedBlocksCounter--
+	}
+
+	// a node is only judged once it has been tracked for a full window
+	if signingInfo.IndexOffset >= window && signingInfo.MissedBlocksCounter > s.params.MaxMissed() {
+		s.slashingStore.SetSlashingInfo(SlashingInfo{
+			Address:    address,
+			ElectionId: stateBatch.ElectionId,
+			BatchIndex: stateBatch.BatchIndex,
+			SlashType:  tss.SlashTypeLiveness,
+		})
+		s.slashingStore.ClearNodeMissedBatchBitArray(address)
+		signingInfo.JailedUntil = stateBatch.BatchIndex + s.params.JailDuration + 1
+		signingInfo.StartBatchIndex = signingInfo.JailedUntil
+		signingInfo.IndexOffset = 0
+		signingInfo.MissedBlocksCounter = 0
 	}
 	s.slashingStore.SetSigningInfo(signingInfo)
 	return signingInfo
 }
 
-func (s Slashing) InitializeSigningInfo(address common.Address, missed bool) SigningInfo {
-	signingInfo := SigningInfo{
-		Address:             address,
-		MissedBlocksCounter: 0,
+// InitializeSigningInfo starts tracking the node with an empty window, a node
+// jailed until a later batch starts its window once it is released
+func (s Slashing) InitializeSigningInfo(address common.Address, electionId, startBatchIndex, jailedUntil uint64) SigningInfo {
+	s.slashingStore.ClearNodeMissedBatchBitArray(address)
+	if jailedUntil > startBatchIndex {
+		startBatchIndex = jailedUntil
 	}
-	if missed {
-		signingInfo.MissedBlocksCounter++
+	signingInfo := SigningInfo{
+		Address:         address,
+		ElectionId:      electionId,
+		StartBatchIndex: startBatchIndex,
+		JailedUntil:     jailedUntil,
 	}
 	s.slashingStore.SetSigningInfo(signingInfo)
-
 	return signingInfo
 }
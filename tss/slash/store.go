@@ -4,9 +4,21 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// SigningInfo is the liveness of a node over the last signed batches window,
+// the window is kept across elections while the node remains a member
 type SigningInfo struct {
-	Address             common.Address `json:"address"`
-	MissedBlocksCounter uint64         `json:"missed_blocks_counter"`
+	Address    common.Address `json:"address"`
+	ElectionId uint64         `json:"election_id"`
+	// StartBatchIndex is the batch the current window tracking started at
+	StartBatchIndex uint64 `json:"start_batch_index"`
+	// IndexOffset is the number of batches tracked since StartBatchIndex, the
+	// missed batch bit array is a ring buffer indexed by IndexOffset % window
+	IndexOffset uint64 `json:"index_offset"`
+	// MissedBlocksCounter is the number of missed batches in the window
+	MissedBlocksCounter uint64 `json:"missed_blocks_counter"`
+	// JailedUntil is the first batch index the node is tracked again after it
+	// was slashed
+	JailedUntil uint64 `json:"jailed_until"`
 }
 
 type SlashingInfo struct {
@@ -19,6 +31,7 @@ type SlashingInfo struct {
 type SlashingStore interface {
 	SetSigningInfo(SigningInfo)
 	GetSigningInfo(common.Address) (bool, SigningInfo)
+	ListSigningInfo() []SigningInfo
 
 	GetNodeMissedBatchBitArray(common.Address, uint64) bool
 	SetNodeMissedBatchBitArray(common.Address, uint64, bool)
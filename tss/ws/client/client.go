@@ -3,6 +3,9 @@ package client
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	tmsync "github.com/tendermint/tendermint/libs/sync"
@@ -12,32 +15,71 @@ import (
 	"github.com/mantlenetworkio/mantle/tss/ws/client/tm"
 )
 
+const (
+	// redialInterval is how often a manager that could not be reached is
+	// dialed again
+	redialInterval = 10 * time.Second
+	// maxOrigins is how many request ids are remembered to route the
+	// responses back to the manager that sent the request
+	maxOrigins = 1024
+)
+
 type WSClients struct {
 	mtx tmsync.RWMutex
 
 	ReqChan  chan tmtypes.RPCRequest
 	StopChan chan struct{}
 
-	//This is used to subscribe the msg from server
-	Cli *tm.WSClient
+	//This is used to subscribe the msg from the servers, one per connected manager
+	Clis []*tm.WSClient
+
+	origins   map[string]*tm.WSClient
+	originIds []string
+	quit      chan struct{}
+	stopped   bool
 }
 
-func NewWSClient(remoteAddr, endpoint string, privKey *ecdsa.PrivateKey, pubkey string) (*WSClients, error) {
-	if client, err := tm.NewWS(remoteAddr, endpoint); err != nil {
-		return nil, err
-	} else {
+// NewWSClient connects to every manager in the comma separated remoteAddrs,
+// so the nodes are already connected to a standby manager when it takes the
+// leader lease. It fails only when none of the managers can be reached, the
+// others are dialed again in the background.
+func NewWSClient(remoteAddrs, endpoint string, privKey *ecdsa.PrivateKey, pubkey string) (*WSClients, error) {
+	wsc := &WSClients{
+		origins: make(map[string]*tm.WSClient),
+		quit:    make(chan struct{}),
+	}
+	var pending []*tm.WSClient
+	var dialErr error
+	for _, remoteAddr := range strings.Split(remoteAddrs, ",") {
+		remoteAddr = strings.TrimSpace(remoteAddr)
+		if len(remoteAddr) == 0 {
+			continue
+		}
+		client, err := tm.NewWS(remoteAddr, endpoint)
+		if err != nil {
+			return nil, err
+		}
 		client.PubKey = pubkey
 		client.PriKey = privKey
-
-		wsc := &WSClients{
-			Cli: client,
+		if err := client.Start(); err != nil {
+			log.Error("failed to connect to tss manager", "address", remoteAddr, "err", err)
+			dialErr = err
+			pending = append(pending, client)
+			continue
 		}
-		if err := wsc.Cli.Start(); err != nil {
-			return nil, err
+		wsc.Clis = append(wsc.Clis, client)
+	}
+	if len(wsc.Clis) == 0 {
+		if dialErr == nil {
+			dialErr = errors.New("no tss manager address is configured")
 		}
-		log.Info("auth success!")
-		return wsc, nil
+		return nil, dialErr
 	}
+	for _, client := range pending {
+		go wsc.redial(client)
+	}
+	log.Info("auth success!", "managers", len(wsc.Clis))
+	return wsc, nil
 }
 
 func (wsc *WSClients) RegisterResChannel(requestMsg chan tmtypes.RPCRequest, stopChan chan struct{}) error {
@@ -47,35 +89,115 @@ func (wsc *WSClients) RegisterResChannel(requestMsg chan tmtypes.RPCRequest, sto
 	wsc.ReqChan = requestMsg
 	wsc.StopChan = stopChan
 
-	wsc.Cli.Logger.Info("register-res-channel")
+	log.Info("register-res-channel")
 
-	//subscribe the message from the server
-	go wsc.rspListener()
+	//subscribe the message from the servers
+	for _, client := range wsc.Clis {
+		go wsc.rspListener(client)
+	}
 
 	return nil
 }
 
+// SendMsg sends the response to the manager the request came from, or to
+// every manager when the request is not known
 func (wsc *WSClients) SendMsg(rsp tmtypes.RPCResponse) error {
-	if err := wsc.Cli.Send(context.Background(), rsp); err != nil {
-		log.Error("send rsp failed!")
-		return err
+	wsc.mtx.RLock()
+	targets := wsc.Clis
+	if origin, ok := wsc.origins[fmt.Sprint(rsp.ID)]; ok {
+		targets = []*tm.WSClient{origin}
+	}
+	wsc.mtx.RUnlock()
+
+	for _, client := range targets {
+		if err := client.Send(context.Background(), rsp); err != nil {
+			log.Error("send rsp failed!", "address", client.Address)
+			return err
+		}
 	}
 	log.Info("send rsp success!")
 	return nil
 }
 
-func (wsc *WSClients) rspListener() {
+// Stop closes the connections to every manager
+func (wsc *WSClients) Stop() {
+	wsc.mtx.Lock()
+	if wsc.stopped {
+		wsc.mtx.Unlock()
+		return
+	}
+	wsc.stopped = true
+	close(wsc.quit)
+	clients := wsc.Clis
+	wsc.mtx.Unlock()
+
+	for _, client := range clients {
+		if err := client.Stop(); err != nil {
+			log.Error("failed to stop ws client", "address", client.Address, "err", err)
+		}
+	}
+}
+
+func (wsc *WSClients) redial(client *tm.WSClient) {
+	ticker := time.NewTicker(redialInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-wsc.quit:
+			return
+		case <-ticker.C:
+		}
+		if err := client.Start(); err != nil {
+			log.Debug("tss manager is still unreachable", "address", client.Address, "err", err)
+			continue
+		}
+		wsc.mtx.Lock()
+		if wsc.stopped {
+			wsc.mtx.Unlock()
+			if err := client.Stop(); err != nil {
+				log.Error("failed to stop ws client", "address", client.Address, "err", err)
+			}
+			return
+		}
+		log.Info("connected to tss manager", "address", client.Address)
+		wsc.Clis = append(wsc.Clis, client)
+		if wsc.ReqChan != nil {
+			go wsc.rspListener(client)
+		}
+		wsc.mtx.Unlock()
+		return
+	}
+}
+
+func (wsc *WSClients) recordOrigin(id string, client *tm.WSClient) {
+	wsc.mtx.Lock()
+	defer wsc.mtx.Unlock()
+	if _, ok := wsc.origins[id]; !ok {
+		wsc.originIds = append(wsc.originIds, id)
+	}
+	wsc.origins[id] = client
+	if len(wsc.originIds) > maxOrigins {
+		delete(wsc.origins, wsc.originIds[0])
+		wsc.originIds = wsc.originIds[1:]
+	}
+}
+
+func (wsc *WSClients) rspListener(client *tm.WSClient) {
 	ticker := time.NewTicker(100 * time.Second)
+	defer ticker.Stop()
 	for {
 		select {
-		case msg := <-wsc.Cli.RequestsCh:
+		case msg, ok := <-client.RequestsCh:
+			if !ok {
+				return
+			}
+			wsc.recordOrigin(fmt.Sprint(msg.ID), client)
 			wsc.ReqChan <- msg
 		case <-wsc.StopChan:
-			wsc.Cli.Logger.Info("we are stopping channel")
-			wsc.StopChan = nil
+			client.Logger.Info("we are stopping channel")
 			return
 		case <-ticker.C:
-			wsc.Cli.Logger.Info("rsp goroutine is alive")
+			client.Logger.Info("rsp goroutine is alive")
 		}
 	}
 }